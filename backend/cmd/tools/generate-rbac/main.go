@@ -1,8 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"go/format"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -12,77 +16,254 @@ import (
 )
 
 type OpenAPISpec struct {
-	Paths map[string]PathItem `yaml:"paths"`
+	Servers []Server            `yaml:"servers,omitempty"`
+	Paths   map[string]PathItem `yaml:"paths"`
+	// Security is the default for operations without their own
+	Security   []map[string][]string `yaml:"security,omitempty"`
+	Components Components            `yaml:"components"`
+	// Permissions declares every permission routes may require, with a
+	// description for the admin API
+	Permissions map[string]string `yaml:"x-permissions"`
+}
+
+type Server struct {
+	URL       string                    `yaml:"url"`
+	Variables map[string]ServerVariable `yaml:"variables,omitempty"`
+}
+
+type ServerVariable struct {
+	Default string `yaml:"default"`
+}
+
+type Components struct {
+	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `yaml:"type"`
+	Scheme string `yaml:"scheme,omitempty"`
+	In     string `yaml:"in,omitempty"`
+	Name   string `yaml:"name,omitempty"`
 }
 
 type PathItem struct {
-	Get    *Operation `yaml:"get,omitempty"`
-	Post   *Operation `yaml:"post,omitempty"`
-	Put    *Operation `yaml:"put,omitempty"`
-	Delete *Operation `yaml:"delete,omitempty"`
-	Patch  *Operation `yaml:"patch,omitempty"`
+	Get     *Operation `yaml:"get,omitempty"`
+	Put     *Operation `yaml:"put,omitempty"`
+	Post    *Operation `yaml:"post,omitempty"`
+	Delete  *Operation `yaml:"delete,omitempty"`
+	Options *Operation `yaml:"options,omitempty"`
+	Head    *Operation `yaml:"head,omitempty"`
+	Patch   *Operation `yaml:"patch,omitempty"`
+	Trace   *Operation `yaml:"trace,omitempty"`
 }
 
 type Operation struct {
-	OperationID string                `yaml:"operationId"`
-	Security    []map[string][]string `yaml:"security,omitempty"`
+	OperationID string `yaml:"operationId"`
+	// Security is nil when the operation inherits the top-level default,
+	// and empty when it overrides it with security: []
+	Security        *[]map[string][]string `yaml:"security,omitempty"`
+	AllowUnverified bool                   `yaml:"x-allow-unverified,omitempty"`
+	AllowWithoutMFA bool                   `yaml:"x-allow-without-mfa,omitempty"`
+	Sensitive       bool                   `yaml:"x-sensitive,omitempty"`
 }
 
 func main() {
-	var specPath, outputPath string
-
-	if len(os.Args) > 2 {
-		specPath = os.Args[1]
-		outputPath = os.Args[2]
-	} else {
-		specPath = "../contracts/openapi.bundled.yaml"
-		outputPath = "internal/generated/rbac.go"
+	specFlag := flag.String("spec", "../contracts/openapi.bundled.yaml", "bundled OpenAPI spec to read")
+	outFlag := flag.String("out", "internal/generated/rbac.go", "Go file to write")
+	check := flag.Bool("check", false, "exit non-zero if the output is stale instead of writing it")
+	flag.Parse()
+
+	// Paths are flags so a stray argument cannot be mistaken for one
+	if flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected arguments: %s\n", strings.Join(flag.Args(), " "))
+		flag.Usage()
+		os.Exit(2)
 	}
+	specPath := *specFlag
+	outputPath := *outFlag
 
 	data, err := os.ReadFile(specPath)
 	if err != nil {
 		log.Fatalf("Failed to read OpenAPI spec: %v", err)
 	}
 
-	var spec OpenAPISpec
-	if err := yaml.Unmarshal(data, &spec); err != nil {
-		log.Fatalf("Failed to parse OpenAPI spec: %v", err)
+	spec, code, err := generate(data)
+	if err != nil {
+		log.Fatalf("Invalid OpenAPI spec: %v", err)
 	}
 
-	code := generateRBACCode(spec)
+	if *check {
+		current, err := os.ReadFile(outputPath)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatalf("Failed to read output: %v", err)
+		}
+		if !bytes.Equal(current, code) {
+			fmt.Fprintf(os.Stderr, "❌ %s is out of date with %s, run npm run generate:be\n", outputPath, specPath)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ RBAC map is up to date: %s\n", outputPath)
+		return
+	}
 
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		log.Fatalf("Failed to create output directory: %v", err)
 	}
 
-	if err := os.WriteFile(outputPath, []byte(code), 0644); err != nil {
+	if err := os.WriteFile(outputPath, code, 0644); err != nil {
 		log.Fatalf("Failed to write output: %v", err)
 	}
 
 	fmt.Printf("✅ Generated RBAC map: %s\n", outputPath)
 	fmt.Printf("📊 Total routes: %d\n", len(spec.Paths))
+	fmt.Printf("🔑 Total permissions: %d\n", len(spec.Permissions))
+}
+
+// generate parses and checks a spec and renders rbac.go for it. The
+// output only depends on the spec, so it can be compared byte for byte.
+func generate(data []byte) (OpenAPISpec, []byte, error) {
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return spec, nil, fmt.Errorf("parse: %w", err)
+	}
+
+	if err := checkSchemes(spec); err != nil {
+		return spec, nil, err
+	}
+
+	if err := checkPermissions(spec); err != nil {
+		return spec, nil, err
+	}
+
+	basePath, err := serverBasePath(spec)
+	if err != nil {
+		return spec, nil, err
+	}
+
+	code, err := format.Source([]byte(generateRBACCode(spec, basePath)))
+	if err != nil {
+		return spec, nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return spec, code, nil
+}
+
+// serverBasePath is the path of the first server URL, with variables set
+// to their defaults. Routes are mounted under it; other servers are
+// expected to reach the same paths through a proxy.
+func serverBasePath(spec OpenAPISpec) (string, error) {
+	if len(spec.Servers) == 0 {
+		return "", nil
+	}
+
+	server := spec.Servers[0]
+	raw := server.URL
+	for name, variable := range server.Variables {
+		raw = strings.ReplaceAll(raw, "{"+name+"}", variable.Default)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("server url %q: %w", server.URL, err)
+	}
+	if strings.Contains(u.Path, "{") {
+		return "", fmt.Errorf("server url %q has a variable without a default", server.URL)
+	}
+	return strings.TrimSuffix(u.Path, "/"), nil
+}
+
+// checkSchemes makes sure every scheme a route names is declared under
+// components.securitySchemes and carries what the middleware needs
+func checkSchemes(spec OpenAPISpec) error {
+	for name, scheme := range spec.Components.SecuritySchemes {
+		if scheme.Type == "apiKey" && (scheme.In == "" || scheme.Name == "") {
+			return fmt.Errorf("apiKey scheme %q needs in and name", name)
+		}
+	}
+	for path, item := range spec.Paths {
+		for method, info := range collectMethods(spec, item) {
+			for _, req := range info.Security {
+				for _, scheme := range req {
+					declared, ok := spec.Components.SecuritySchemes[scheme.Scheme]
+					if !ok {
+						return fmt.Errorf("%s %s uses undeclared security scheme %q", method, path, scheme.Scheme)
+					}
+					// Service clients hold no permissions, so scopes on them
+					// could never be checked
+					if declared.Type == "http" && strings.EqualFold(declared.Scheme, "basic") && len(scheme.Scopes) > 0 {
+						return fmt.Errorf("%s %s requires scopes on basic scheme %q, which service clients cannot hold", method, path, scheme.Scheme)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkPermissions makes sure every permission a route requires is
+// declared under x-permissions, so a typo cannot lock a route
+func checkPermissions(spec OpenAPISpec) error {
+	for path, item := range spec.Paths {
+		for method, info := range collectMethods(spec, item) {
+			for _, req := range info.Security {
+				for _, scheme := range req {
+					for _, permission := range scheme.Scopes {
+						if _, ok := spec.Permissions[permission]; !ok {
+							return fmt.Errorf("%s %s requires undeclared permission %q", method, path, permission)
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
 }
 
-func generateRBACCode(spec OpenAPISpec) string {
+func generateRBACCode(spec OpenAPISpec, basePath string) string {
 	var sb strings.Builder
 
 	sb.WriteString("// Code generated by oapi-codegen (generate-rbac) - DO NOT EDIT.\n")
 	sb.WriteString("// Source: contracts/openapi.bundled.yaml\n\n")
 	sb.WriteString("package generated\n\n")
 
+	sb.WriteString("// BasePath is the path of the spec's first server, under which every route is mounted\n")
+	sb.WriteString(fmt.Sprintf("const BasePath = %q\n\n", basePath))
+
+	sb.WriteString("// SchemeRequirement names a security scheme and the permissions the\n")
+	sb.WriteString("// credential presented for it must grant\n")
+	sb.WriteString("type SchemeRequirement struct {\n")
+	sb.WriteString("\tScheme string\n")
+	sb.WriteString("\tScopes []string\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// SecurityRequirement is met when every scheme in it is satisfied.\n")
+	sb.WriteString("// An empty requirement lets anonymous callers through.\n")
+	sb.WriteString("type SecurityRequirement []SchemeRequirement\n\n")
+
 	sb.WriteString("// RouteSecurityInfo contains security information for a route\n")
 	sb.WriteString("type RouteSecurityInfo struct {\n")
-	sb.WriteString("\tIsPublic       bool\n")
-	sb.WriteString("\tRequiredScopes []string\n")
+	sb.WriteString("\tIsPublic bool\n")
+	sb.WriteString("\t// Security lists alternative requirements, any one of which grants access\n")
+	sb.WriteString("\tSecurity []SecurityRequirement\n")
+	sb.WriteString("\t// AllowUnverified lets users without a verified email through (x-allow-unverified)\n")
+	sb.WriteString("\tAllowUnverified bool\n")
+	sb.WriteString("\t// AllowWithoutMFA stays reachable before mandatory 2FA is set up (x-allow-without-mfa)\n")
+	sb.WriteString("\tAllowWithoutMFA bool\n")
+	sb.WriteString("\t// Sensitive routes refuse delegated credentials such as API keys (x-sensitive)\n")
+	sb.WriteString("\tSensitive bool\n")
 	sb.WriteString("}\n\n")
 
 	sb.WriteString("// RouteSecurity defines security requirements for each route\n")
 	sb.WriteString("// Automatically generated from OpenAPI security specifications\n")
 	sb.WriteString("//\n")
 	sb.WriteString("// Rules:\n")
-	sb.WriteString("//   No security field          = PUBLIC (IsPublic: true)\n")
-	sb.WriteString("//   security: - BearerAuth: [] = ANY authenticated user (IsPublic: false, RequiredScopes: [])\n")
-	sb.WriteString("//   security: - BearerAuth: [admin] = ADMIN only (IsPublic: false, RequiredScopes: [admin])\n")
+	sb.WriteString("//   No security field             = top-level security, PUBLIC when there is none\n")
+	sb.WriteString("//   security: []                  = PUBLIC (IsPublic: true)\n")
+	sb.WriteString("//   - BearerAuth: []              = ANY authenticated user\n")
+	sb.WriteString("//   - BearerAuth: [users:read]    = roles granted users:read\n")
+	sb.WriteString("//   - BearerAuth: [...]\n")
+	sb.WriteString("//   - ApiKeyAuth: [...]           = either scheme (OR)\n")
+	sb.WriteString("//   - BearerAuth: []\n")
+	sb.WriteString("//     ServiceAuth: []             = both schemes (AND)\n")
+	sb.WriteString("//   - {}                          = anonymous callers too\n")
 	sb.WriteString("var RouteSecurity = map[string]map[string]RouteSecurityInfo{\n")
 
 	paths := make([]string, 0, len(spec.Paths))
@@ -93,9 +274,9 @@ func generateRBACCode(spec OpenAPISpec) string {
 
 	for _, path := range paths {
 		item := spec.Paths[path]
-		fullPath := "/api/v1" + path
+		fullPath := basePath + path
 
-		methods := collectMethods(item)
+		methods := collectMethods(spec, item)
 		if len(methods) == 0 {
 			continue
 		}
@@ -110,80 +291,161 @@ func generateRBACCode(spec OpenAPISpec) string {
 
 		for _, method := range methodNames {
 			secInfo := methods[method]
-			sb.WriteString(fmt.Sprintf("\t\t\"%s\": {IsPublic: %v, RequiredScopes: %s},\n",
-				method, secInfo.IsPublic, formatScopes(secInfo.RequiredScopes)))
+			sb.WriteString(fmt.Sprintf("\t\t\"%s\": {IsPublic: %v, Security: %s, AllowUnverified: %v, AllowWithoutMFA: %v, Sensitive: %v},\n",
+				method, secInfo.IsPublic, formatSecurity(secInfo.Security),
+				secInfo.AllowUnverified, secInfo.AllowWithoutMFA, secInfo.Sensitive))
 		}
 
 		sb.WriteString("\t},\n")
 	}
 
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// SecuritySchemeInfo describes a scheme under components.securitySchemes\n")
+	sb.WriteString("type SecuritySchemeInfo struct {\n")
+	sb.WriteString("\t// Type is http, apiKey, oauth2, openIdConnect or mutualTLS\n")
+	sb.WriteString("\tType string\n")
+	sb.WriteString("\t// Scheme is the HTTP authentication scheme, such as bearer or basic\n")
+	sb.WriteString("\tScheme string\n")
+	sb.WriteString("\t// In and Name locate an API key: header, query or cookie, and its name\n")
+	sb.WriteString("\tIn   string\n")
+	sb.WriteString("\tName string\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// SecuritySchemes maps scheme names to their definitions\n")
+	sb.WriteString("var SecuritySchemes = map[string]SecuritySchemeInfo{\n")
+
+	schemeNames := make([]string, 0, len(spec.Components.SecuritySchemes))
+	for name := range spec.Components.SecuritySchemes {
+		schemeNames = append(schemeNames, name)
+	}
+	sort.Strings(schemeNames)
+
+	for _, name := range schemeNames {
+		scheme := spec.Components.SecuritySchemes[name]
+		sb.WriteString(fmt.Sprintf("\t%q: {Type: %q, Scheme: %q, In: %q, Name: %q},\n",
+			name, scheme.Type, strings.ToLower(scheme.Scheme), scheme.In, scheme.Name))
+	}
+
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// PermissionInfo is a permission declared under x-permissions\n")
+	sb.WriteString("type PermissionInfo struct {\n")
+	sb.WriteString("\tName        string\n")
+	sb.WriteString("\tDescription string\n")
+	sb.WriteString("}\n\n")
+
+	sb.WriteString("// Permissions lists the permissions declared by the spec, sorted by name\n")
+	sb.WriteString("var Permissions = []PermissionInfo{\n")
+
+	names := make([]string, 0, len(spec.Permissions))
+	for name := range spec.Permissions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\t{Name: %q, Description: %q},\n", name, spec.Permissions[name]))
+	}
+
 	sb.WriteString("}\n")
 	return sb.String()
 }
 
 type SecurityInfo struct {
-	IsPublic       bool
-	RequiredScopes []string
+	IsPublic        bool
+	Security        [][]SchemeRequirement
+	AllowUnverified bool
+	AllowWithoutMFA bool
+	Sensitive       bool
 }
 
-func collectMethods(item PathItem) map[string]SecurityInfo {
+type SchemeRequirement struct {
+	Scheme string
+	Scopes []string
+}
+
+func collectMethods(spec OpenAPISpec, item PathItem) map[string]SecurityInfo {
 	methods := make(map[string]SecurityInfo)
 
-	if item.Get != nil {
-		methods["GET"] = extractSecurityInfo(item.Get.Security)
-	}
-	if item.Post != nil {
-		methods["POST"] = extractSecurityInfo(item.Post.Security)
-	}
-	if item.Put != nil {
-		methods["PUT"] = extractSecurityInfo(item.Put.Security)
+	operations := map[string]*Operation{
+		"GET":     item.Get,
+		"PUT":     item.Put,
+		"POST":    item.Post,
+		"DELETE":  item.Delete,
+		"OPTIONS": item.Options,
+		"HEAD":    item.Head,
+		"PATCH":   item.Patch,
+		"TRACE":   item.Trace,
 	}
-	if item.Delete != nil {
-		methods["DELETE"] = extractSecurityInfo(item.Delete.Security)
-	}
-	if item.Patch != nil {
-		methods["PATCH"] = extractSecurityInfo(item.Patch.Security)
+	for method, op := range operations {
+		if op != nil {
+			methods[method] = operationSecurityInfo(spec, op)
+		}
 	}
 
 	return methods
 }
 
+func operationSecurityInfo(spec OpenAPISpec, op *Operation) SecurityInfo {
+	// Operations without their own security inherit the top-level default
+	security := spec.Security
+	if op.Security != nil {
+		security = *op.Security
+	}
+
+	info := extractSecurityInfo(security)
+	info.AllowUnverified = op.AllowUnverified
+	info.AllowWithoutMFA = op.AllowWithoutMFA
+	info.Sensitive = op.Sensitive
+	return info
+}
+
+// extractSecurityInfo keeps every requirement object, in spec order, with
+// the schemes inside each sorted by name
 func extractSecurityInfo(security []map[string][]string) SecurityInfo {
-	// No security field = PUBLIC endpoint
+	// No requirements = PUBLIC endpoint
 	if len(security) == 0 {
-		return SecurityInfo{
-			IsPublic:       true,
-			RequiredScopes: nil,
+		return SecurityInfo{IsPublic: true}
+	}
+
+	requirements := make([][]SchemeRequirement, 0, len(security))
+	for _, req := range security {
+		names := make([]string, 0, len(req))
+		for name := range req {
+			names = append(names, name)
 		}
+		sort.Strings(names)
+
+		schemes := make([]SchemeRequirement, 0, len(names))
+		for _, name := range names {
+			scopes := req[name]
+			if scopes == nil {
+				scopes = []string{}
+			}
+			schemes = append(schemes, SchemeRequirement{Scheme: name, Scopes: scopes})
+		}
+		requirements = append(requirements, schemes)
 	}
 
-	// Get first security requirement
-	firstReq := security[0]
+	return SecurityInfo{Security: requirements}
+}
 
-	// Empty map = PUBLIC endpoint (shouldn't happen, but handle it)
-	if len(firstReq) == 0 {
-		return SecurityInfo{
-			IsPublic:       true,
-			RequiredScopes: nil,
-		}
+func formatSecurity(security [][]SchemeRequirement) string {
+	if security == nil {
+		return "nil"
 	}
 
-	// Get scopes from BearerAuth
-	if scopes, ok := firstReq["BearerAuth"]; ok {
-		// BearerAuth: [] = any authenticated user
-		// BearerAuth: [admin] = admin only
-		// BearerAuth: [user, admin] = user or admin
-		return SecurityInfo{
-			IsPublic:       false,
-			RequiredScopes: scopes,
+	reqs := make([]string, len(security))
+	for i, req := range security {
+		schemes := make([]string, len(req))
+		for j, scheme := range req {
+			schemes[j] = fmt.Sprintf("{Scheme: %q, Scopes: %s}", scheme.Scheme, formatScopes(scheme.Scopes))
 		}
+		reqs[i] = "{" + strings.Join(schemes, ", ") + "}"
 	}
 
-	// Has security but no BearerAuth = treat as authenticated
-	return SecurityInfo{
-		IsPublic:       false,
-		RequiredScopes: []string{},
-	}
+	return "[]SecurityRequirement{" + strings.Join(reqs, ", ") + "}"
 }
 
 func formatScopes(scopes []string) string {
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	specs, err := filepath.Glob(filepath.Join("testdata", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, specPath := range specs {
+		name := strings.TrimSuffix(filepath.Base(specPath), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(specPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, got, err := generate(data)
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+
+			goldenPath := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("read golden file (run go test -update): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s (run go test -update)\n%s", goldenPath, got)
+			}
+		})
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "full.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, first, err := generate(data)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		_, again, err := generate(data)
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatalf("run %d produced different output", i+2)
+		}
+	}
+}
+
+func TestGenerateRejectsInvalidSpecs(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{
+			name: "undeclared permission",
+			spec: `
+paths:
+  /items:
+    get:
+      security:
+        - BearerAuth: [items:read]
+components:
+  securitySchemes:
+    BearerAuth: {type: http, scheme: bearer}
+`,
+			want: `requires undeclared permission "items:read"`,
+		},
+		{
+			name: "undeclared scheme",
+			spec: `
+paths:
+  /items:
+    head:
+      security:
+        - CookieAuth: []
+`,
+			want: `uses undeclared security scheme "CookieAuth"`,
+		},
+		{
+			name: "scopes on a basic scheme",
+			spec: `
+x-permissions:
+  service:call: Call internal endpoints
+paths:
+  /items:
+    get:
+      security:
+        - ServiceAuth: [service:call]
+components:
+  securitySchemes:
+    ServiceAuth: {type: http, scheme: Basic}
+`,
+			want: `GET /items requires scopes on basic scheme "ServiceAuth"`,
+		},
+		{
+			name: "api key without location",
+			spec: `
+paths: {}
+components:
+  securitySchemes:
+    ApiKeyAuth: {type: apiKey}
+`,
+			want: `apiKey scheme "ApiKeyAuth" needs in and name`,
+		},
+		{
+			name: "server variable without default",
+			spec: `
+servers:
+  - url: https://example.com/{version}
+paths: {}
+`,
+			want: "has a variable without a default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := generate([]byte(tt.spec))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCommittedOutputIsCurrent fails when internal/generated/rbac.go
+// was not regenerated after a contract change
+func TestCommittedOutputIsCurrent(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "contracts", "openapi.bundled.yaml"))
+	if err != nil {
+		t.Skipf("bundled spec not available: %v", err)
+	}
+
+	_, want, err := generate(data)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join("..", "..", "..", "internal", "generated", "rbac.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("internal/generated/rbac.go is out of date, run npm run generate:be")
+	}
+}
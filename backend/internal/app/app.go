@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,7 +14,13 @@ import (
 	"backend/internal/cache"
 	"backend/internal/config"
 	"backend/internal/database"
+	"backend/internal/mailer"
+	"backend/internal/middleware"
 	"backend/internal/router"
+	jwt "backend/pkg"
+	"backend/pkg/oidc"
+	"backend/pkg/password"
+	"backend/pkg/webauthn"
 
 	"gorm.io/gorm"
 )
@@ -22,7 +29,11 @@ type App struct {
 	config *config.Config
 	db     *gorm.DB
 	cache  *cache.RedisCache
-	server *http.Server
+	mailer mailer.Mailer
+	sso    *oidc.Provider
+	// passkeys is nil when WebAuthn is disabled
+	passkeys *webauthn.RelyingParty
+	server   *http.Server
 }
 
 func New() (*App, error) {
@@ -36,6 +47,20 @@ func New() (*App, error) {
 		config: cfg,
 	}
 
+	// Initialize JWT signing keys
+	if err := app.initJWT(); err != nil {
+		return nil, fmt.Errorf("failed to initialize jwt keys: %w", err)
+	}
+
+	// Initialize password hashing
+	if err := password.SetParams(password.Params{
+		Memory:      cfg.Password.Argon2Memory,
+		Iterations:  cfg.Password.Argon2Iterations,
+		Parallelism: cfg.Password.Argon2Parallelism,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to initialize password hashing: %w", err)
+	}
+
 	// Initialize database
 	if err := app.initDatabase(); err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
@@ -46,12 +71,67 @@ func New() (*App, error) {
 		log.Printf("Warning: Failed to initialize cache: %v", err)
 	}
 
+	// Initialize mailer
+	if err := app.initMailer(); err != nil {
+		return nil, fmt.Errorf("failed to initialize mailer: %w", err)
+	}
+
+	// Initialize single sign-on (optional)
+	if err := app.initOIDC(); err != nil {
+		return nil, fmt.Errorf("failed to initialize oidc: %w", err)
+	}
+
+	// Initialize passkeys (optional)
+	if err := app.initWebAuthn(); err != nil {
+		return nil, fmt.Errorf("failed to initialize webauthn: %w", err)
+	}
+
 	// Initialize server
-	app.initServer()
+	if err := app.initServer(); err != nil {
+		return nil, fmt.Errorf("failed to initialize server: %w", err)
+	}
 
 	return app, nil
 }
 
+func (a *App) initJWT() error {
+	activeID := a.config.JWT.ActiveKeyID
+	keys := make([]jwt.Key, 0, len(a.config.JWT.Keys))
+	for _, k := range a.config.JWT.Keys {
+		key, err := jwt.ParseKey(k.ID, k.Material)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	// Development without configured keys gets a throwaway key, so tokens
+	// do not survive a restart but nothing guessable is ever used.
+	if len(keys) == 0 && a.config.IsDevelopment() {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		activeID = "dev-ephemeral"
+		key, err := jwt.NewHMACKey(activeID, secret)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		log.Println("⚠ No JWT keys configured, using an ephemeral development key")
+	}
+
+	keySet, err := jwt.NewKeySet(activeID, keys)
+	if err != nil {
+		return err
+	}
+
+	jwt.SetKeySet(keySet)
+	jwt.SetAccessTokenTTL(a.config.JWT.AccessTokenTTL)
+	log.Printf("✓ JWT keys loaded (active kid: %s, %d total)", keySet.ActiveKeyID(), len(keys))
+	return nil
+}
+
 func (a *App) initDatabase() error {
 	dbConfig := database.Config{
 		Host:     a.config.Database.Host,
@@ -97,16 +177,92 @@ func (a *App) initCache() error {
 	return nil
 }
 
-func (a *App) initServer() {
-	container := NewContainer(a.db, a.cache)
+func (a *App) initMailer() error {
+	m, err := mailer.New(a.config.Mail)
+	if err != nil {
+		return err
+	}
 
-	r := router.New(container.Handlers())
-	ginRouter := r.Setup(a.config.IsDevelopment())
+	a.mailer = m
+	log.Printf("✓ Mailer initialized (driver: %s)", a.config.Mail.Driver)
+	return nil
+}
+
+// initOIDC only validates the settings; the provider is contacted on the
+// first login so an outage there does not stop the API from starting
+func (a *App) initOIDC() error {
+	cfg := a.config.OIDC
+	if !cfg.Enabled() {
+		log.Println("ℹ Single sign-on is disabled")
+		return nil
+	}
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       cfg.Scopes,
+	})
+	if err != nil {
+		return err
+	}
+
+	a.sso = provider
+	log.Printf("✓ Single sign-on enabled (issuer: %s)", cfg.Issuer)
+	return nil
+}
+
+func (a *App) initWebAuthn() error {
+	cfg := a.config.WebAuthn
+	if !cfg.Enabled() {
+		log.Println("ℹ Passkeys are disabled")
+		return nil
+	}
+
+	rp, err := webauthn.New(webauthn.Config{
+		RPID:    cfg.RPID,
+		RPName:  cfg.RPName,
+		Origins: cfg.Origins,
+	})
+	if err != nil {
+		return err
+	}
+
+	a.passkeys = rp
+	log.Printf("✓ Passkeys enabled (rp id: %s)", cfg.RPID)
+	return nil
+}
+
+func (a *App) initServer() error {
+	container := NewContainer(a.config, a.db, a.cache, a.mailer, a.sso, a.passkeys)
+
+	// Permissions come from the spec; roles must exist before requests do
+	if err := container.RoleService.Sync(context.Background()); err != nil {
+		return fmt.Errorf("failed to sync roles and permissions: %w", err)
+	}
+	log.Println("✓ Roles and permissions synced")
+
+	r := router.New(container.Handlers(), container.TokenService, container.RoleService, middleware.SecurityOptions{
+		RequireVerifiedEmail: a.config.Auth.EmailVerification == config.EmailVerificationRoutes,
+		MFARequiredRoles:     a.config.Auth.MFARequiredRoles,
+		ServiceClients:       a.config.Auth.ServiceClients,
+		SessionCookies:       a.config.Auth.SessionCookies,
+	}, a.config.Server.CORSOrigins)
+	if container.SCIMHandler != nil {
+		r.WithSCIM(container.SCIMHandler, a.config.Auth.SCIMToken)
+		log.Println("✓ SCIM provisioning enabled at /scim/v2")
+	}
+	ginRouter, err := r.Setup(a.config.IsDevelopment())
+	if err != nil {
+		return fmt.Errorf("failed to set up routes: %w", err)
+	}
 
 	a.server = &http.Server{
 		Addr:    ":" + a.config.Server.Port,
 		Handler: ginRouter,
 	}
+	return nil
 }
 
 func (a *App) Run() error {
@@ -2,45 +2,130 @@ package app
 
 import (
 	"backend/internal/cache"
+	"backend/internal/config"
 	"backend/internal/handlers"
+	"backend/internal/mailer"
 	"backend/internal/repository"
 	"backend/internal/service"
+	"backend/internal/session"
+	"backend/pkg/oidc"
+	"backend/pkg/password"
+	"backend/pkg/webauthn"
 
 	"gorm.io/gorm"
 )
 
 type Container struct {
-	UserHandler    *handlers.UserHandler
-	ProductHandler *handlers.ProductHandler
-	AuthHandler    *handlers.AuthHandler
+	UserHandler          *handlers.UserHandler
+	ProductHandler       *handlers.ProductHandler
+	AuthHandler          *handlers.AuthHandler
+	TokenHandler         *handlers.TokenHandler
+	InvitationHandler    *handlers.InvitationHandler
+	ImpersonationHandler *handlers.ImpersonationHandler
+	IntrospectionHandler *handlers.IntrospectionHandler
+	RoleHandler          *handlers.RoleHandler
+	// SCIMHandler is nil unless SCIM_TOKEN is set
+	SCIMHandler  *handlers.SCIMHandler
+	TokenService service.TokenService
+	RoleService  service.RoleService
 }
 
-func NewContainer(db *gorm.DB, cache *cache.RedisCache) *Container {
+func NewContainer(cfg *config.Config, db *gorm.DB, redisCache *cache.RedisCache, mail mailer.Mailer, sso *oidc.Provider, passkeys *webauthn.RelyingParty) *Container {
 	// repositories
 	userRepo := repository.NewUserRepository(db)
 	productRepo := repository.NewProductRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	oneTimeTokenRepo := repository.NewOneTimeTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	patRepo := repository.NewPersonalAccessTokenRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	impersonationRepo := repository.NewImpersonationRepository(db)
+	passkeyRepo := repository.NewWebAuthnCredentialRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+
+	// shared stores (Redis when enabled, in-memory otherwise)
+	store := cache.NewStore(redisCache)
+
+	passwordPolicy := password.NewPolicy(cfg.Password.MinLength, cfg.Password.MinCharClasses, cfg.Password.Denylist)
 
 	// services
-	userService := service.NewUserService(userRepo, cache)
-	productService := service.NewProductService(productRepo, cache)
-	authService := service.NewAuthService(userRepo)
+	roleService := service.NewRoleService(roleRepo, permissionRepo, store)
+	tokenService := service.NewTokenService(userRepo, refreshTokenRepo, patRepo, roleService, store)
+	loginThrottle := service.NewLoginThrottle(store, service.LoginThrottleOptions{
+		MaxAttempts:     cfg.Auth.LoginMaxAttempts,
+		LockoutDuration: cfg.Auth.LoginLockoutDuration,
+	})
+	userService := service.NewUserService(userRepo, roleRepo, roleService, redisCache, tokenService, loginThrottle)
+	productService := service.NewProductService(productRepo, redisCache, service.NewProductPolicy(roleService))
+	patService := service.NewPersonalAccessTokenService(patRepo, store)
+	invitationService := service.NewInvitationService(invitationRepo, userRepo, roleRepo, redisCache, tokenService, mail, passwordPolicy, service.InvitationOptions{
+		TTL:    cfg.Auth.InvitationTTL,
+		AppURL: cfg.Server.AppURL,
+	})
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, oneTimeTokenRepo, recoveryCodeRepo, loginAttemptRepo, identityRepo, passkeyRepo, tokenService, loginThrottle, store, mail, sso, passkeys, service.AuthOptions{
+		RefreshTokenTTL:      cfg.JWT.RefreshTokenTTL,
+		PasswordResetTTL:     cfg.Auth.PasswordResetTTL,
+		EmailVerificationTTL: cfg.Auth.EmailVerificationTTL,
+		RequireVerifiedEmail: cfg.Auth.EmailVerification == config.EmailVerificationLogin,
+		MFARequiredRoles:     cfg.Auth.MFARequiredRoles,
+		MFAIssuer:            cfg.Auth.MFAIssuer,
+		AppURL:               cfg.Server.AppURL,
+		OIDCAutoProvision:    cfg.OIDC.AutoProvision,
+		MagicLinkEnabled:     cfg.Auth.MagicLinkEnabled,
+		MagicLinkTTL:         cfg.Auth.MagicLinkTTL,
+		PasswordPolicy:       passwordPolicy,
+	})
+
+	impersonationService := service.NewImpersonationService(impersonationRepo, userRepo, roleService, cfg.Auth.ImpersonationTTL)
 
 	// handlers
-	userHandler := handlers.NewUserHandler(userService)
+	userHandler := handlers.NewUserHandler(userService, invitationService)
 	productHandler := handlers.NewProductHandler(productService)
-	authHandler := handlers.NewAuthHandler(authService)
+	sameSite, _ := session.ParseSameSite(cfg.Auth.SessionCookieSameSite)
+	authHandler := handlers.NewAuthHandler(authService, session.Options{
+		Enabled:    cfg.Auth.SessionCookies,
+		Domain:     cfg.Auth.SessionCookieDomain,
+		SameSite:   sameSite,
+		RefreshTTL: cfg.JWT.RefreshTokenTTL,
+	})
+	tokenHandler := handlers.NewTokenHandler(patService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationService)
+	introspectionHandler := handlers.NewIntrospectionHandler(tokenService)
+	roleHandler := handlers.NewRoleHandler(roleService)
+
+	var scimHandler *handlers.SCIMHandler
+	if cfg.Auth.SCIMToken != "" {
+		scimHandler = handlers.NewSCIMHandler(service.NewSCIMService(userService, userRepo))
+	}
 
 	return &Container{
-		UserHandler:    userHandler,
-		ProductHandler: productHandler,
-		AuthHandler:    authHandler,
+		UserHandler:          userHandler,
+		ProductHandler:       productHandler,
+		AuthHandler:          authHandler,
+		TokenHandler:         tokenHandler,
+		InvitationHandler:    invitationHandler,
+		ImpersonationHandler: impersonationHandler,
+		IntrospectionHandler: introspectionHandler,
+		RoleHandler:          roleHandler,
+		SCIMHandler:          scimHandler,
+		TokenService:         tokenService,
+		RoleService:          roleService,
 	}
 }
 
 func (c *Container) Handlers() *handlers.CombinedHandler {
 	return &handlers.CombinedHandler{
-		UserHandler:    c.UserHandler,
-		ProductHandler: c.ProductHandler,
-		AuthHandler:    c.AuthHandler,
+		UserHandler:          c.UserHandler,
+		ProductHandler:       c.ProductHandler,
+		AuthHandler:          c.AuthHandler,
+		TokenHandler:         c.TokenHandler,
+		InvitationHandler:    c.InvitationHandler,
+		ImpersonationHandler: c.ImpersonationHandler,
+		IntrospectionHandler: c.IntrospectionHandler,
+		RoleHandler:          c.RoleHandler,
 	}
 }
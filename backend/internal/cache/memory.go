@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is a process-local Store used when Redis is disabled.
+// Values are JSON encoded like in Redis so callers behave identically.
+type MemoryCache struct {
+	entries map[string]memoryEntry
+	mu      sync.RWMutex
+}
+
+func NewMemoryCache() *MemoryCache {
+	mc := &MemoryCache{
+		entries: make(map[string]memoryEntry),
+	}
+
+	// Cleanup expired entries every minute
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			mc.cleanup()
+		}
+	}()
+
+	return mc
+}
+
+func (m *MemoryCache) cleanup() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+func (m *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	m.mu.RLock()
+	entry, ok := m.entries[key]
+	m.mu.RUnlock()
+
+	if !ok || entry.expired(time.Now()) {
+		return ErrCacheMiss
+	}
+
+	return json.Unmarshal(entry.value, dest)
+}
+
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	entry := memoryEntry{value: data}
+	if expiration > 0 {
+		entry.expiresAt = time.Now().Add(expiration)
+	}
+
+	m.mu.Lock()
+	m.entries[key] = entry
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+	return nil
+}
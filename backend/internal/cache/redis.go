@@ -3,12 +3,15 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+var ErrCacheMiss = errors.New("key not found")
+
 type RedisCache struct {
 	client *redis.Client
 }
@@ -37,7 +40,7 @@ func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
 func (r *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return fmt.Errorf("key not found")
+		return ErrCacheMiss
 	}
 	if err != nil {
 		return err
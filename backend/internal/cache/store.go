@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store is the subset of cache operations shared by Redis and the
+// in-memory fallback. Get returns ErrCacheMiss for absent keys.
+type Store interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStore returns Redis when it is enabled and a process-local memory
+// store otherwise. The memory store is not shared between instances.
+func NewStore(redis *RedisCache) Store {
+	if redis != nil {
+		return redis
+	}
+	return NewMemoryCache()
+}
@@ -3,6 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,11 +15,22 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	Redis    RedisConfig
+	JWT      JWTConfig
+	Auth     AuthConfig
+	Password PasswordConfig
+	OIDC     OIDCConfig
+	WebAuthn WebAuthnConfig
+	Mail     MailConfig
 }
 
 type ServerConfig struct {
 	Port string
 	Env  string
+	// AppURL is the frontend base URL used in links sent by email
+	AppURL string
+	// CORSOrigins may call the API from a browser; "*" allows any origin
+	// but no credentials
+	CORSOrigins []string
 }
 
 type DatabaseConfig struct {
@@ -34,6 +49,128 @@ type RedisConfig struct {
 	DB       int
 }
 
+// JWTConfig holds the keys used to sign and verify access tokens.
+// The active key signs new tokens; every other key is kept only to
+// verify tokens issued before a rotation.
+type JWTConfig struct {
+	ActiveKeyID     string
+	Keys            []JWTKey
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// JWTKey material is either an HMAC secret or a PEM encoded RSA/Ed25519
+// key. Retiring asymmetric keys may be given as public keys only.
+type JWTKey struct {
+	ID       string
+	Material []byte
+}
+
+// Email verification modes. "optional" only sends the verification link,
+// "login" refuses to log in unverified users and "routes" lets them log in
+// but rejects them on protected routes not marked x-allow-unverified.
+const (
+	EmailVerificationOptional = "optional"
+	EmailVerificationLogin    = "login"
+	EmailVerificationRoutes   = "routes"
+)
+
+// minServiceSecretLength keeps service client secrets out of guessing range
+const minServiceSecretLength = 32
+
+type AuthConfig struct {
+	PasswordResetTTL     time.Duration
+	EmailVerification    string
+	EmailVerificationTTL time.Duration
+	// InvitationTTL is how long an invitation link from an admin works
+	InvitationTTL time.Duration
+	// ImpersonationTTL is the lifetime of tokens admins get to act as a user
+	ImpersonationTTL time.Duration
+	// MagicLinkEnabled allows passwordless login through emailed links
+	// that work once for MagicLinkTTL
+	MagicLinkEnabled bool
+	MagicLinkTTL     time.Duration
+	// MFARequiredRoles must enroll in TOTP before using anything but the
+	// enrollment endpoints
+	MFARequiredRoles []string
+	// MFAIssuer is the account issuer shown in authenticator apps
+	MFAIssuer string
+	// LoginMaxAttempts failed logins for one email lock it for
+	// LoginLockoutDuration; fewer failures only add a growing delay
+	LoginMaxAttempts     int
+	LoginLockoutDuration time.Duration
+	// ServiceClients maps the client ids of internal services to their
+	// secrets; they authenticate to /auth/introspect with HTTP Basic
+	ServiceClients map[string]string
+	// SessionCookies lets browser clients receive tokens in HttpOnly
+	// cookies instead of the response body
+	SessionCookies        bool
+	SessionCookieDomain   string
+	SessionCookieSameSite string
+	// SCIMToken is the bearer token identity providers use on /scim/v2;
+	// provisioning is off while it is empty
+	SCIMToken string
+}
+
+// PasswordConfig sets the argon2id cost of new password hashes and the
+// policy new passwords must meet
+type PasswordConfig struct {
+	// Argon2Memory is in KiB
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	MinLength         int
+	// MinCharClasses counts lowercase, uppercase, digits and symbols
+	MinCharClasses int
+	// Denylist holds the entries of PASSWORD_DENYLIST_FILE, one per line
+	Denylist []string
+}
+
+// OIDCConfig enables single sign-on with one OpenID Connect provider.
+// It is off unless OIDC_ISSUER is set.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the frontend page the provider sends the browser back
+	// to; it posts the code to /auth/oidc/callback
+	RedirectURL string
+	Scopes      []string
+	// AutoProvision creates accounts for verified emails with no user yet
+	AutoProvision bool
+}
+
+func (c OIDCConfig) Enabled() bool {
+	return c.Issuer != ""
+}
+
+// WebAuthnConfig enables passkey login. It is off unless WEBAUTHN_RP_ID
+// is set.
+type WebAuthnConfig struct {
+	// RPID is the domain passkeys are bound to; it must be the host of
+	// every origin or a parent domain of it
+	RPID   string
+	RPName string
+	// Origins the browser may run ceremonies from (default APP_URL)
+	Origins []string
+}
+
+func (c WebAuthnConfig) Enabled() bool {
+	return c.RPID != ""
+}
+
+// MailConfig selects the mail driver: "smtp" for real delivery or "log"
+// to write messages to LogFile (stdout when empty) in development and tests.
+type MailConfig struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	LogFile      string
+}
+
 func Load() (*Config, error) {
 	// Load .env file if exists
 	if err := godotenv.Load(); err != nil {
@@ -42,8 +179,10 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
-			Env:  getEnv("APP_ENV", "development"),
+			Port:        getEnv("PORT", "8080"),
+			Env:         getEnv("APP_ENV", "development"),
+			AppURL:      strings.TrimRight(getEnv("APP_URL", "http://localhost:5173"), "/"),
+			CORSOrigins: splitList(getEnv("CORS_ALLOWED_ORIGINS", "*")),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -59,7 +198,48 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       0,
 		},
+		OIDC: OIDCConfig{
+			Issuer:        getEnv("OIDC_ISSUER", ""),
+			ClientID:      getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+			Scopes:        splitList(getEnv("OIDC_SCOPES", "openid,email,profile")),
+			AutoProvision: getEnv("OIDC_AUTO_PROVISION", "false") == "true",
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@example.com"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			LogFile:      getEnv("MAIL_LOG_FILE", ""),
+		},
+	}
+
+	config.OIDC.RedirectURL = getEnv("OIDC_REDIRECT_URL", config.Server.AppURL+"/auth/oidc/callback")
+	config.WebAuthn = WebAuthnConfig{
+		RPID:    getEnv("WEBAUTHN_RP_ID", ""),
+		RPName:  getEnv("WEBAUTHN_RP_NAME", "Backend API"),
+		Origins: splitList(getEnv("WEBAUTHN_ORIGINS", config.Server.AppURL)),
+	}
+
+	jwtConfig, err := loadJWTConfig()
+	if err != nil {
+		return nil, err
 	}
+	config.JWT = jwtConfig
+
+	authConfig, err := loadAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Auth = authConfig
+
+	passwordConfig, err := loadPasswordConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Password = passwordConfig
 
 	if err := config.Validate(); err != nil {
 		return nil, err
@@ -78,9 +258,225 @@ func (c *Config) Validate() error {
 	if c.Database.DBName == "" {
 		return fmt.Errorf("database name is required")
 	}
+	if len(c.JWT.Keys) == 0 && !c.IsDevelopment() {
+		return fmt.Errorf("jwt signing keys are required outside development")
+	}
+	// A single key is selected by loadJWTConfig
+	if len(c.JWT.Keys) > 1 && c.JWT.ActiveKeyID == "" {
+		return fmt.Errorf("JWT_ACTIVE_KEY_ID is required when more than one jwt key is configured")
+	}
+	// Revoking a user's sessions only blocks tokens for AccessTokenTTL
+	if c.Auth.ImpersonationTTL > c.JWT.AccessTokenTTL {
+		return fmt.Errorf("IMPERSONATION_TTL must not exceed JWT_ACCESS_TOKEN_TTL (%s)", c.JWT.AccessTokenTTL)
+	}
+	switch c.Auth.EmailVerification {
+	case EmailVerificationOptional, EmailVerificationLogin, EmailVerificationRoutes:
+	default:
+		return fmt.Errorf("EMAIL_VERIFICATION must be optional, login or routes, got %q", c.Auth.EmailVerification)
+	}
+	if c.Password.MinCharClasses > 4 {
+		return fmt.Errorf("PASSWORD_MIN_CHAR_CLASSES must be between 1 and 4, got %d", c.Password.MinCharClasses)
+	}
+	if c.Password.Argon2Parallelism == 0 || c.Password.Argon2Memory < 8*uint32(c.Password.Argon2Parallelism) {
+		return fmt.Errorf("ARGON2_MEMORY must be at least 8 KiB per ARGON2_PARALLELISM lane")
+	}
+	for id, secret := range c.Auth.ServiceClients {
+		if len(secret) < minServiceSecretLength {
+			return fmt.Errorf("secret of service client %q must be at least %d characters", id, minServiceSecretLength)
+		}
+	}
+	if c.Auth.SCIMToken != "" && len(c.Auth.SCIMToken) < minServiceSecretLength {
+		return fmt.Errorf("SCIM_TOKEN must be at least %d characters", minServiceSecretLength)
+	}
+	switch c.Auth.SessionCookieSameSite {
+	case "lax", "strict", "none":
+	default:
+		return fmt.Errorf("SESSION_COOKIE_SAMESITE must be lax, strict or none, got %q", c.Auth.SessionCookieSameSite)
+	}
+	// Browsers do not send cookies to an API that answers any origin
+	if c.Auth.SessionCookies && slices.Contains(c.Server.CORSOrigins, "*") {
+		return fmt.Errorf("SESSION_COOKIES_ENABLED requires CORS_ALLOWED_ORIGINS to list the frontend origins")
+	}
+	if c.OIDC.Enabled() && c.OIDC.ClientID == "" {
+		return fmt.Errorf("OIDC_CLIENT_ID is required when OIDC_ISSUER is set")
+	}
+	if c.Mail.Driver != "smtp" && c.Mail.Driver != "log" {
+		return fmt.Errorf("MAIL_DRIVER must be smtp or log, got %q", c.Mail.Driver)
+	}
 	return nil
 }
 
+func loadAuthConfig() (AuthConfig, error) {
+	var cfg AuthConfig
+	var err error
+
+	if cfg.PasswordResetTTL, err = getDuration("PASSWORD_RESET_TTL", time.Hour); err != nil {
+		return cfg, err
+	}
+	if cfg.EmailVerificationTTL, err = getDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour); err != nil {
+		return cfg, err
+	}
+	if cfg.InvitationTTL, err = getDuration("INVITATION_TTL", 7*24*time.Hour); err != nil {
+		return cfg, err
+	}
+	if cfg.ImpersonationTTL, err = getDuration("IMPERSONATION_TTL", 15*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.MagicLinkTTL, err = getDuration("MAGIC_LINK_TTL", 15*time.Minute); err != nil {
+		return cfg, err
+	}
+	cfg.MagicLinkEnabled = getEnv("MAGIC_LINK_ENABLED", "false") == "true"
+
+	cfg.EmailVerification = getEnv("EMAIL_VERIFICATION", EmailVerificationOptional)
+	cfg.MFARequiredRoles = splitList(getEnv("MFA_REQUIRED_ROLES", ""))
+	cfg.MFAIssuer = getEnv("MFA_ISSUER", "Backend API")
+
+	if cfg.LoginMaxAttempts, err = getInt("LOGIN_MAX_ATTEMPTS", 10); err != nil {
+		return cfg, err
+	}
+	if cfg.LoginLockoutDuration, err = getDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute); err != nil {
+		return cfg, err
+	}
+
+	cfg.SessionCookies = getEnv("SESSION_COOKIES_ENABLED", "false") == "true"
+	cfg.SessionCookieDomain = getEnv("SESSION_COOKIE_DOMAIN", "")
+	cfg.SessionCookieSameSite = strings.ToLower(getEnv("SESSION_COOKIE_SAMESITE", "lax"))
+	cfg.SCIMToken = getEnv("SCIM_TOKEN", "")
+
+	cfg.ServiceClients = make(map[string]string)
+	for _, entry := range splitList(getEnv("SERVICE_CLIENTS", "")) {
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || secret == "" {
+			return cfg, fmt.Errorf("SERVICE_CLIENTS entries must look like id:secret")
+		}
+		cfg.ServiceClients[id] = secret
+	}
+
+	return cfg, nil
+}
+
+func loadPasswordConfig() (PasswordConfig, error) {
+	var cfg PasswordConfig
+
+	memory, err := getInt("ARGON2_MEMORY", 64*1024)
+	if err != nil {
+		return cfg, err
+	}
+	iterations, err := getInt("ARGON2_ITERATIONS", 3)
+	if err != nil {
+		return cfg, err
+	}
+	parallelism, err := getInt("ARGON2_PARALLELISM", 2)
+	if err != nil {
+		return cfg, err
+	}
+	if parallelism > 255 {
+		return cfg, fmt.Errorf("ARGON2_PARALLELISM must be at most 255, got %d", parallelism)
+	}
+	cfg.Argon2Memory = uint32(memory)
+	cfg.Argon2Iterations = uint32(iterations)
+	cfg.Argon2Parallelism = uint8(parallelism)
+
+	if cfg.MinLength, err = getInt("PASSWORD_MIN_LENGTH", 8); err != nil {
+		return cfg, err
+	}
+	if cfg.MinCharClasses, err = getInt("PASSWORD_MIN_CHAR_CLASSES", 2); err != nil {
+		return cfg, err
+	}
+
+	if path := getEnv("PASSWORD_DENYLIST_FILE", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read password denylist: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				cfg.Denylist = append(cfg.Denylist, line)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// loadJWTConfig reads signing keys from JWT_KEYS ("kid:secret" pairs) and
+// JWT_KEY_FILES ("kid:/path/to/key" pairs), both comma separated. PEM keys
+// can only be loaded from files.
+func loadJWTConfig() (JWTConfig, error) {
+	cfg := JWTConfig{
+		ActiveKeyID: getEnv("JWT_ACTIVE_KEY_ID", ""),
+	}
+
+	var err error
+	if cfg.AccessTokenTTL, err = getDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.RefreshTokenTTL, err = getDuration("JWT_REFRESH_TOKEN_TTL", 30*24*time.Hour); err != nil {
+		return cfg, err
+	}
+
+	seen := make(map[string]bool)
+	addKey := func(id string, material []byte) error {
+		if seen[id] {
+			return fmt.Errorf("duplicate jwt key id %q", id)
+		}
+		seen[id] = true
+		cfg.Keys = append(cfg.Keys, JWTKey{ID: id, Material: material})
+		return nil
+	}
+
+	for _, pair := range splitList(getEnv("JWT_KEYS", "")) {
+		id, secret, err := splitKeyPair(pair)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid JWT_KEYS entry: %w", err)
+		}
+		if err := addKey(id, []byte(secret)); err != nil {
+			return cfg, err
+		}
+	}
+
+	for _, pair := range splitList(getEnv("JWT_KEY_FILES", "")) {
+		id, path, err := splitKeyPair(pair)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid JWT_KEY_FILES entry: %w", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read jwt key %q: %w", id, err)
+		}
+		if err := addKey(id, []byte(strings.TrimSpace(string(data)))); err != nil {
+			return cfg, err
+		}
+	}
+
+	// A single key needs no explicit selection
+	if cfg.ActiveKeyID == "" && len(cfg.Keys) == 1 {
+		cfg.ActiveKeyID = cfg.Keys[0].ID
+	}
+
+	return cfg, nil
+}
+
+func splitKeyPair(pair string) (string, string, error) {
+	id, value, ok := strings.Cut(pair, ":")
+	id = strings.TrimSpace(id)
+	value = strings.TrimSpace(value)
+	if !ok || id == "" || value == "" {
+		return "", "", fmt.Errorf("expected kid:value, got %q", pair)
+	}
+	return id, value, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -88,6 +484,30 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration, got %q", key, value)
+	}
+	return d, nil
+}
+
+func getInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", key, value)
+	}
+	return n, nil
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.Server.Env == "development"
 }
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadJWTConfigSelectsOnlyKey(t *testing.T) {
+	t.Setenv("JWT_ACTIVE_KEY_ID", "")
+	t.Setenv("JWT_KEYS", "k1:0123456789abcdef0123456789abcdef")
+	t.Setenv("JWT_KEY_FILES", "")
+
+	cfg, err := loadJWTConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveKeyID != "k1" {
+		t.Fatalf("got active key %q, want k1", cfg.ActiveKeyID)
+	}
+}
+
+func TestLoadJWTConfigRejectsDuplicateKeyIDs(t *testing.T) {
+	t.Setenv("JWT_KEYS", "k1:0123456789abcdef0123456789abcdef,k1:fedcba9876543210fedcba9876543210")
+	t.Setenv("JWT_KEY_FILES", "")
+
+	if _, err := loadJWTConfig(); err == nil || !strings.Contains(err.Error(), "duplicate jwt key id") {
+		t.Fatalf("got %v, want a duplicate key error", err)
+	}
+}
+
+func TestValidateActiveKeyID(t *testing.T) {
+	one := []JWTKey{{ID: "k1"}}
+	two := []JWTKey{{ID: "k1"}, {ID: "k2"}}
+
+	tests := []struct {
+		name    string
+		keys    []JWTKey
+		active  string
+		wantErr bool
+	}{
+		{"single key without selection", one, "", false},
+		{"several keys with selection", two, "k2", false},
+		{"several keys without selection", two, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.JWT.Keys = tt.keys
+			cfg.JWT.ActiveKeyID = tt.active
+
+			err := cfg.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("got %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "JWT_ACTIVE_KEY_ID") {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateImpersonationTTL(t *testing.T) {
+	tests := []struct {
+		name          string
+		impersonation time.Duration
+		wantErr       bool
+	}{
+		{"shorter than access tokens", 5 * time.Minute, false},
+		{"same as access tokens", 15 * time.Minute, false},
+		{"outlives access tokens", 16 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Auth.ImpersonationTTL = tt.impersonation
+
+			err := cfg.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("got %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "IMPERSONATION_TTL") {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+// validConfig passes Validate so tests can change one setting at a time
+func validConfig() *Config {
+	cfg := &Config{}
+	cfg.Server.Port = "8080"
+	cfg.Server.Env = "production"
+	cfg.Database.Host = "localhost"
+	cfg.Database.DBName = "app"
+	cfg.JWT.Keys = []JWTKey{{ID: "k1"}}
+	cfg.JWT.ActiveKeyID = "k1"
+	cfg.JWT.AccessTokenTTL = 15 * time.Minute
+	cfg.Auth.ImpersonationTTL = 15 * time.Minute
+	cfg.Auth.EmailVerification = EmailVerificationOptional
+	cfg.Auth.SessionCookieSameSite = "lax"
+	cfg.Mail.Driver = "log"
+	cfg.Password.MinCharClasses = 1
+	cfg.Password.Argon2Parallelism = 1
+	cfg.Password.Argon2Memory = 64 * 1024
+	return cfg
+}
@@ -53,8 +53,41 @@ func NewPostgresDB(cfg Config) (*gorm.DB, error) {
 }
 
 func AutoMigrate(db *gorm.DB) error {
+	if err := addEmailVerifiedAt(db); err != nil {
+		return err
+	}
+
 	return db.AutoMigrate(
 		&models.User{},
 		&models.Product{},
+		&models.RefreshToken{},
+		&models.OneTimeToken{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.PersonalAccessToken{},
+		&models.UserIdentity{},
+		&models.Invitation{},
+		&models.Impersonation{},
+		&models.WebAuthnCredential{},
+		&models.Permission{},
+		&models.Role{},
 	)
 }
+
+// addEmailVerifiedAt adds users.email_verified_at to databases created
+// before email verification existed. Those accounts count as verified, or
+// EMAIL_VERIFICATION=login would lock every one of them out. The column
+// and backfill share a transaction so the backfill runs exactly once.
+func addEmailVerifiedAt(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.User{}) || migrator.HasColumn(&models.User{}, "EmailVerifiedAt") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.User{}, "EmailVerifiedAt"); err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL").Error
+	})
+}
@@ -21,44 +21,95 @@ import (
 )
 
 const (
-	BearerAuthScopes = "BearerAuth.Scopes"
+	ApiKeyAuthScopes  = "ApiKeyAuth.Scopes"
+	BearerAuthScopes  = "BearerAuth.Scopes"
+	ServiceAuthScopes = "ServiceAuth.Scopes"
 )
 
-// Defines values for CreateUserRequestRole.
+// Defines values for IntrospectionRequestTokenTypeHint.
 const (
-	CreateUserRequestRoleAdmin CreateUserRequestRole = "admin"
-	CreateUserRequestRoleGuest CreateUserRequestRole = "guest"
-	CreateUserRequestRoleUser  CreateUserRequestRole = "user"
+	IntrospectionRequestTokenTypeHintAccessToken         IntrospectionRequestTokenTypeHint = "access_token"
+	IntrospectionRequestTokenTypeHintPersonalAccessToken IntrospectionRequestTokenTypeHint = "personal_access_token"
 )
 
-// Defines values for UpdateUserRequestRole.
+// Defines values for IntrospectionResponseTokenType.
 const (
-	UpdateUserRequestRoleAdmin UpdateUserRequestRole = "admin"
-	UpdateUserRequestRoleGuest UpdateUserRequestRole = "guest"
-	UpdateUserRequestRoleUser  UpdateUserRequestRole = "user"
+	IntrospectionResponseTokenTypeAccessToken         IntrospectionResponseTokenType = "access_token"
+	IntrospectionResponseTokenTypePersonalAccessToken IntrospectionResponseTokenType = "personal_access_token"
 )
 
-// Defines values for UserRole.
+// Defines values for LoginAttemptOutcome.
 const (
-	UserRoleAdmin UserRole = "admin"
-	UserRoleGuest UserRole = "guest"
-	UserRoleUser  UserRole = "user"
+	AccountDisabled    LoginAttemptOutcome = "account_disabled"
+	EmailNotVerified   LoginAttemptOutcome = "email_not_verified"
+	InvalidCredentials LoginAttemptOutcome = "invalid_credentials"
+	InvalidMfaCode     LoginAttemptOutcome = "invalid_mfa_code"
+	LockedOut          LoginAttemptOutcome = "locked_out"
+	MfaChallenge       LoginAttemptOutcome = "mfa_challenge"
+	Success            LoginAttemptOutcome = "success"
 )
 
-// Defines values for UserDataRole.
+// Defines values for UserStatus.
 const (
-	UserDataRoleAdmin UserDataRole = "admin"
-	UserDataRoleGuest UserDataRole = "guest"
-	UserDataRoleUser  UserDataRole = "user"
+	Active  UserStatus = "active"
+	Pending UserStatus = "pending"
 )
 
+// AcceptInvitationRequest defines model for AcceptInvitationRequest.
+type AcceptInvitationRequest struct {
+	// Password Password for the new account; the same policy as registration applies
+	Password string `json:"password"`
+
+	// Token Token from the invitation email
+	Token string `json:"token"`
+}
+
 // AuthResponse defines model for AuthResponse.
 type AuthResponse struct {
+	// ExpiresIn Access token lifetime in seconds
+	ExpiresIn int `json:"expires_in"`
+
+	// RefreshToken Opaque single-use refresh token
+	RefreshToken string `json:"refresh_token"`
+
 	// Token JWT authentication token
 	Token string   `json:"token"`
 	User  UserData `json:"user"`
 }
 
+// ChangePasswordRequest defines model for ChangePasswordRequest.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+
+	// NewPassword The same policy as registration applies
+	NewPassword string `json:"new_password"`
+}
+
+// CreatePermissionRequest defines model for CreatePermissionRequest.
+type CreatePermissionRequest struct {
+	Description *string `json:"description,omitempty"`
+
+	// Name A resource:action name for clients to check
+	Name string `json:"name"`
+}
+
+// CreatePersonalAccessTokenRequest defines model for CreatePersonalAccessTokenRequest.
+type CreatePersonalAccessTokenRequest struct {
+	ExpiresInDays *int   `json:"expires_in_days,omitempty"`
+	Name          string `json:"name"`
+
+	// Scopes Permissions from x-permissions; the token is also limited by the owner's role
+	Scopes []string `json:"scopes"`
+}
+
+// CreatePersonalAccessTokenResponse defines model for CreatePersonalAccessTokenResponse.
+type CreatePersonalAccessTokenResponse struct {
+	Data PersonalAccessToken `json:"data"`
+
+	// Token The token value; it cannot be retrieved again
+	Token string `json:"token"`
+}
+
 // CreateProductRequest defines model for CreateProductRequest.
 type CreateProductRequest struct {
 	Category    *string `json:"category"`
@@ -68,19 +119,24 @@ type CreateProductRequest struct {
 	Stock       int     `json:"stock"`
 }
 
+// CreateRoleRequest defines model for CreateRoleRequest.
+type CreateRoleRequest struct {
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+
+	// Permissions Names of existing permissions
+	Permissions *[]string `json:"permissions,omitempty"`
+}
+
 // CreateUserRequest defines model for CreateUserRequest.
 type CreateUserRequest struct {
 	Email openapi_types.Email `json:"email"`
 	Name  string              `json:"name"`
 
-	// Password User password (will be hashed)
-	Password string                 `json:"password"`
-	Role     *CreateUserRequestRole `json:"role,omitempty"`
+	// Role Name of an existing role
+	Role *string `json:"role,omitempty"`
 }
 
-// CreateUserRequestRole defines model for CreateUserRequest.Role.
-type CreateUserRequestRole string
-
 // Error defines model for Error.
 type Error struct {
 	Code    *string              `json:"code,omitempty"`
@@ -88,6 +144,131 @@ type Error struct {
 	Message string               `json:"message"`
 }
 
+// ForgotPasswordRequest defines model for ForgotPasswordRequest.
+type ForgotPasswordRequest struct {
+	// Email Email address of the account
+	Email openapi_types.Email `json:"email"`
+}
+
+// ImpersonateRequest defines model for ImpersonateRequest.
+type ImpersonateRequest struct {
+	// Reason Why the user is impersonated; kept in the audit trail
+	Reason string `json:"reason"`
+}
+
+// Impersonation defines model for Impersonation.
+type Impersonation struct {
+	// ActorId Admin who impersonated
+	ActorId   openapi_types.UUID `json:"actor_id"`
+	CreatedAt time.Time          `json:"created_at"`
+
+	// ExpiresAt When the issued token expires
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Id Record UUID, also the jti of the issued token
+	Id        openapi_types.UUID `json:"id"`
+	IpAddress *string            `json:"ip_address,omitempty"`
+	Reason    string             `json:"reason"`
+
+	// TargetId User who was impersonated
+	TargetId  openapi_types.UUID `json:"target_id"`
+	UserAgent *string            `json:"user_agent,omitempty"`
+}
+
+// ImpersonationResponse defines model for ImpersonationResponse.
+type ImpersonationResponse struct {
+	// ExpiresIn Token lifetime in seconds
+	ExpiresIn     int           `json:"expires_in"`
+	Impersonation Impersonation `json:"impersonation"`
+
+	// Token Access token for the user with the admin in its act claim. It cannot
+	// be refreshed and is refused on sensitive operations.
+	Token string `json:"token"`
+}
+
+// IntrospectionRequest defines model for IntrospectionRequest.
+type IntrospectionRequest struct {
+	// Token Access token or personal access token to check
+	Token string `json:"token"`
+
+	// TokenTypeHint Accepted for compatibility; the type is detected from the token
+	TokenTypeHint *IntrospectionRequestTokenTypeHint `json:"token_type_hint,omitempty"`
+}
+
+// IntrospectionRequestTokenTypeHint Accepted for compatibility; the type is detected from the token
+type IntrospectionRequestTokenTypeHint string
+
+// IntrospectionResponse RFC 7662 introspection result. Inactive tokens carry nothing but
+// active=false, whatever the reason.
+type IntrospectionResponse struct {
+	// Act Present when an admin is impersonating the user
+	Act *struct {
+		// Sub The impersonating admin
+		Sub openapi_types.UUID `json:"sub"`
+	} `json:"act,omitempty"`
+
+	// Active Valid signature or hash, unexpired, unrevoked, and the account is active
+	Active bool `json:"active"`
+
+	// Exp Expiry as seconds since the epoch
+	Exp *int64 `json:"exp,omitempty"`
+
+	// Iat Issue time as seconds since the epoch
+	Iat *int64 `json:"iat,omitempty"`
+
+	// Jti Token identifier of access tokens
+	Jti *string `json:"jti,omitempty"`
+
+	// Role The user's current role
+	Role *string `json:"role,omitempty"`
+
+	// Scope Space separated scopes; only personal access tokens are limited by scopes
+	Scope *string `json:"scope,omitempty"`
+
+	// Sub User the token belongs to
+	Sub       *openapi_types.UUID             `json:"sub,omitempty"`
+	TokenType *IntrospectionResponseTokenType `json:"token_type,omitempty"`
+
+	// Username The user's current email address
+	Username *openapi_types.Email `json:"username,omitempty"`
+}
+
+// IntrospectionResponseTokenType defines model for IntrospectionResponse.TokenType.
+type IntrospectionResponseTokenType string
+
+// Invitation defines model for Invitation.
+type Invitation struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// Expired Whether the link has expired; resending issues a new one
+	Expired bool `json:"expired"`
+
+	// ExpiresAt When the emailed link stops working
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Id Invitation UUID
+	Id openapi_types.UUID `json:"id"`
+
+	// InvitedBy Admin who sent the invitation
+	InvitedBy *openapi_types.UUID `json:"invited_by"`
+
+	// SentAt When the invitation was last emailed
+	SentAt time.Time `json:"sent_at"`
+	User   User      `json:"user"`
+}
+
+// LoginAttempt defines model for LoginAttempt.
+type LoginAttempt struct {
+	CreatedAt time.Time           `json:"created_at"`
+	Id        openapi_types.UUID  `json:"id"`
+	IpAddress *string             `json:"ip_address,omitempty"`
+	Outcome   LoginAttemptOutcome `json:"outcome"`
+	UserAgent *string             `json:"user_agent,omitempty"`
+}
+
+// LoginAttemptOutcome defines model for LoginAttempt.Outcome.
+type LoginAttemptOutcome string
+
 // LoginRequest defines model for LoginRequest.
 type LoginRequest struct {
 	// Email User email address
@@ -97,16 +278,47 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// LogoutRequest defines model for LogoutRequest.
+type LogoutRequest struct {
+	// RefreshToken Refresh token to revoke together with the access token
+	RefreshToken *string `json:"refresh_token,omitempty"`
+}
+
+// MagicLinkRequest defines model for MagicLinkRequest.
+type MagicLinkRequest struct {
+	// Email Email address of the account
+	Email openapi_types.Email `json:"email"`
+}
+
+// MagicLinkVerifyRequest defines model for MagicLinkVerifyRequest.
+type MagicLinkVerifyRequest struct {
+	// Token Token from the login link
+	Token string `json:"token"`
+}
+
 // MeResponse defines model for MeResponse.
 type MeResponse struct {
+	CreatedAt time.Time `json:"created_at"`
+
 	// Email Current user email
-	Email *openapi_types.Email `json:"email,omitempty"`
+	Email         openapi_types.Email `json:"email"`
+	EmailVerified bool                `json:"email_verified"`
+
+	// ImpersonatedBy Admin acting as this user when the session is an impersonation
+	ImpersonatedBy *openapi_types.UUID `json:"impersonated_by"`
+	IsActive       bool                `json:"is_active"`
+	MfaEnabled     bool                `json:"mfa_enabled"`
+	Name           string              `json:"name"`
+
+	// PendingEmail New address waiting for confirmation, if any
+	PendingEmail *openapi_types.Email `json:"pending_email"`
 
 	// Role Current user role
-	Role *string `json:"role,omitempty"`
+	Role      string    `json:"role"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	// UserId Current user UUID
-	UserId *openapi_types.UUID `json:"user_id,omitempty"`
+	UserId openapi_types.UUID `json:"user_id"`
 }
 
 // Meta defines model for Meta.
@@ -117,12 +329,180 @@ type Meta struct {
 	TotalPages *int `json:"total_pages,omitempty"`
 }
 
+// MfaChallengeResponse defines model for MfaChallengeResponse.
+type MfaChallengeResponse struct {
+	// ExpiresIn Challenge lifetime in seconds
+	ExpiresIn int `json:"expires_in"`
+
+	// MfaToken Challenge token to exchange at /auth/mfa/verify
+	MfaToken string `json:"mfa_token"`
+}
+
+// MfaVerifyRequest defines model for MfaVerifyRequest.
+type MfaVerifyRequest struct {
+	// Code Current TOTP code or an unused recovery code
+	Code string `json:"code"`
+
+	// MfaToken Challenge token from /auth/login
+	MfaToken string `json:"mfa_token"`
+}
+
+// OidcAuthorizationResponse defines model for OidcAuthorizationResponse.
+type OidcAuthorizationResponse struct {
+	// AuthorizationUrl Provider URL to send the browser to
+	AuthorizationUrl string `json:"authorization_url"`
+
+	// State Opaque value the provider echoes back to the callback
+	State string `json:"state"`
+}
+
+// OidcCallbackRequest defines model for OidcCallbackRequest.
+type OidcCallbackRequest struct {
+	// Code Authorization code from the provider redirect
+	Code string `json:"code"`
+
+	// State State from the provider redirect
+	State string `json:"state"`
+}
+
 // PaginationParams defines model for PaginationParams.
 type PaginationParams struct {
 	Page    *int `json:"page,omitempty"`
 	PerPage *int `json:"per_page,omitempty"`
 }
 
+// Passkey defines model for Passkey.
+type Passkey struct {
+	// Aaguid Authenticator model, all zeros when not disclosed
+	Aaguid openapi_types.UUID `json:"aaguid"`
+
+	// BackupEligible The passkey may be synced between the user's devices
+	BackupEligible bool               `json:"backup_eligible"`
+	CreatedAt      time.Time          `json:"created_at"`
+	Id             openapi_types.UUID `json:"id"`
+	LastUsedAt     *time.Time         `json:"last_used_at"`
+	Name           string             `json:"name"`
+}
+
+// PasskeyAssertionCredential defines model for PasskeyAssertionCredential.
+type PasskeyAssertionCredential struct {
+	Id       string                   `json:"id"`
+	RawId    string                   `json:"rawId"`
+	Response PasskeyAssertionResponse `json:"response"`
+	Type     string                   `json:"type"`
+}
+
+// PasskeyAssertionResponse defines model for PasskeyAssertionResponse.
+type PasskeyAssertionResponse struct {
+	AuthenticatorData string  `json:"authenticatorData"`
+	ClientDataJSON    string  `json:"clientDataJSON"`
+	Signature         string  `json:"signature"`
+	UserHandle        *string `json:"userHandle"`
+}
+
+// PasskeyAttestationCredential defines model for PasskeyAttestationCredential.
+type PasskeyAttestationCredential struct {
+	Id       string                     `json:"id"`
+	RawId    string                     `json:"rawId"`
+	Response PasskeyAttestationResponse `json:"response"`
+	Type     string                     `json:"type"`
+}
+
+// PasskeyAttestationResponse defines model for PasskeyAttestationResponse.
+type PasskeyAttestationResponse struct {
+	AttestationObject string `json:"attestationObject"`
+	ClientDataJSON    string `json:"clientDataJSON"`
+}
+
+// PasskeyAuthenticatorSelection defines model for PasskeyAuthenticatorSelection.
+type PasskeyAuthenticatorSelection struct {
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	ResidentKey        string `json:"residentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// PasskeyCreationOptions defines model for PasskeyCreationOptions.
+type PasskeyCreationOptions struct {
+	Attestation            string                        `json:"attestation"`
+	AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	Challenge              string                        `json:"challenge"`
+
+	// ExcludeCredentials Passkeys the user already has, so they are not registered twice
+	ExcludeCredentials []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+	PubKeyCredParams   []PasskeyCredentialParameter  `json:"pubKeyCredParams"`
+	Rp                 PasskeyRelyingParty           `json:"rp"`
+
+	// Timeout Milliseconds the ceremony may take
+	Timeout int         `json:"timeout"`
+	User    PasskeyUser `json:"user"`
+}
+
+// PasskeyCredentialDescriptor defines model for PasskeyCredentialDescriptor.
+type PasskeyCredentialDescriptor struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// PasskeyCredentialParameter defines model for PasskeyCredentialParameter.
+type PasskeyCredentialParameter struct {
+	// Alg COSE algorithm identifier
+	Alg  int    `json:"alg"`
+	Type string `json:"type"`
+}
+
+// PasskeyRegistrationRequest defines model for PasskeyRegistrationRequest.
+type PasskeyRegistrationRequest struct {
+	Credential PasskeyAttestationCredential `json:"credential"`
+
+	// Name Label shown in the passkey list
+	Name *string `json:"name,omitempty"`
+}
+
+// PasskeyRelyingParty defines model for PasskeyRelyingParty.
+type PasskeyRelyingParty struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PasskeyRequestOptions defines model for PasskeyRequestOptions.
+type PasskeyRequestOptions struct {
+	Challenge        string `json:"challenge"`
+	RpId             string `json:"rpId"`
+	Timeout          int    `json:"timeout"`
+	UserVerification string `json:"userVerification"`
+}
+
+// PasskeyUser defines model for PasskeyUser.
+type PasskeyUser struct {
+	DisplayName string `json:"displayName"`
+
+	// Id User handle, the account id as base64url
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Permission defines model for Permission.
+type Permission struct {
+	// Builtin Declared by the API and required by its routes; cannot be deleted
+	Builtin     bool      `json:"builtin"`
+	CreatedAt   time.Time `json:"created_at"`
+	Description string    `json:"description"`
+	Name        string    `json:"name"`
+}
+
+// PersonalAccessToken defines model for PersonalAccessToken.
+type PersonalAccessToken struct {
+	CreatedAt  time.Time          `json:"created_at"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	Id         openapi_types.UUID `json:"id"`
+	LastUsedAt *time.Time         `json:"last_used_at"`
+	Name       string             `json:"name"`
+	Scopes     []string           `json:"scopes"`
+
+	// TokenPrefix First characters of the token, to tell tokens apart
+	TokenPrefix string `json:"token_prefix"`
+}
+
 // Product defines model for Product.
 type Product struct {
 	// Category Product category
@@ -140,6 +520,11 @@ type Product struct {
 	// Name Product name
 	Name string `json:"name"`
 
+	// OwnerId User who created the product. Only they and roles granted
+	// products:manage may change it; products without an owner are left
+	// to the latter.
+	OwnerId *openapi_types.UUID `json:"owner_id"`
+
 	// Price Product price
 	Price float64 `json:"price"`
 
@@ -150,6 +535,18 @@ type Product struct {
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
 
+// RecoveryCodesResponse defines model for RecoveryCodesResponse.
+type RecoveryCodesResponse struct {
+	// RecoveryCodes Single-use codes to log in without the authenticator
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// RefreshRequest defines model for RefreshRequest.
+type RefreshRequest struct {
+	// RefreshToken Refresh token from the last login or refresh
+	RefreshToken string `json:"refresh_token"`
+}
+
 // RegisterRequest defines model for RegisterRequest.
 type RegisterRequest struct {
 	// Email Valid email address
@@ -158,8 +555,78 @@ type RegisterRequest struct {
 	// Name Full name of the user
 	Name string `json:"name"`
 
-	// Password Password (minimum 6 characters)
+	// Password Password. Must also meet the server's password policy: character
+	// classes, no common passwords, no name or email.
+	Password string `json:"password"`
+}
+
+// RegistrationPendingResponse defines model for RegistrationPendingResponse.
+type RegistrationPendingResponse struct {
+	Message string `json:"message"`
+}
+
+// ResendVerificationRequest defines model for ResendVerificationRequest.
+type ResendVerificationRequest struct {
+	// Email Email address of the account
+	Email openapi_types.Email `json:"email"`
+}
+
+// ResetPasswordRequest defines model for ResetPasswordRequest.
+type ResetPasswordRequest struct {
+	// Password New password; the same policy as registration applies
 	Password string `json:"password"`
+
+	// Token Token from the password reset email
+	Token string `json:"token"`
+}
+
+// Role defines model for Role.
+type Role struct {
+	// Builtin Created by the server and cannot be deleted. The admin role always
+	// holds every permission.
+	Builtin     bool      `json:"builtin"`
+	CreatedAt   time.Time `json:"created_at"`
+	Description string    `json:"description"`
+
+	// Name Unique role name, referenced by users
+	Name string `json:"name"`
+
+	// Permissions Names of the permissions granted to the role
+	Permissions []string  `json:"permissions"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TotpCodeRequest defines model for TotpCodeRequest.
+type TotpCodeRequest struct {
+	// Code Current TOTP code (or a recovery code when disabling)
+	Code string `json:"code"`
+}
+
+// TotpEnrollResponse defines model for TotpEnrollResponse.
+type TotpEnrollResponse struct {
+	// OtpauthUrl otpauth URI to render as a QR code
+	OtpauthUrl string `json:"otpauth_url"`
+
+	// Secret Base32 secret for manual entry
+	Secret string `json:"secret"`
+}
+
+// UpdateProfileRequest defines model for UpdateProfileRequest.
+type UpdateProfileRequest struct {
+	// CurrentPassword Required when changing the email
+	CurrentPassword *string `json:"current_password,omitempty"`
+
+	// Email New address; applied once confirmed from the link sent to it
+	Email *openapi_types.Email `json:"email,omitempty"`
+	Name  *string              `json:"name,omitempty"`
+}
+
+// UpdateRoleRequest defines model for UpdateRoleRequest.
+type UpdateRoleRequest struct {
+	Description *string `json:"description,omitempty"`
+
+	// Permissions Replaces the role's permissions when present
+	Permissions *[]string `json:"permissions,omitempty"`
 }
 
 // UpdateUserRequest defines model for UpdateUserRequest.
@@ -169,12 +636,11 @@ type UpdateUserRequest struct {
 	Name     *string              `json:"name,omitempty"`
 
 	// Password New password (optional, will be hashed)
-	Password *string                `json:"password,omitempty"`
-	Role     *UpdateUserRequestRole `json:"role,omitempty"`
-}
+	Password *string `json:"password,omitempty"`
 
-// UpdateUserRequestRole defines model for UpdateUserRequest.Role.
-type UpdateUserRequestRole string
+	// Role Name of an existing role
+	Role *string `json:"role,omitempty"`
+}
 
 // User defines model for User.
 type User struct {
@@ -184,6 +650,9 @@ type User struct {
 	// Email User's email address
 	Email openapi_types.Email `json:"email"`
 
+	// EmailVerifiedAt When the user verified their email address
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
 	// Id User UUID
 	Id openapi_types.UUID `json:"id"`
 
@@ -193,36 +662,48 @@ type User struct {
 	// Name User's full name
 	Name string `json:"name"`
 
-	// Role User role
-	Role *UserRole `json:"role,omitempty"`
+	// Role Name of the user's role, one of those at /roles
+	Role *string `json:"role,omitempty"`
+
+	// Status Invited users stay pending until they accept the invitation
+	Status *UserStatus `json:"status,omitempty"`
 
 	// UpdatedAt User last update timestamp
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
 
-// UserRole User role
-type UserRole string
+// UserStatus Invited users stay pending until they accept the invitation
+type UserStatus string
 
 // UserData defines model for UserData.
 type UserData struct {
 	// Email User's email address
 	Email openapi_types.Email `json:"email"`
 
+	// EmailVerified Whether the user has verified their email address
+	EmailVerified bool `json:"email_verified"`
+
 	// Id User UUID
 	Id openapi_types.UUID `json:"id"`
 
 	// IsActive Whether the user account is active
 	IsActive bool `json:"is_active"`
 
+	// MfaEnabled Whether two-factor authentication is enabled
+	MfaEnabled bool `json:"mfa_enabled"`
+
 	// Name User's full name
 	Name string `json:"name"`
 
-	// Role User role
-	Role UserDataRole `json:"role"`
+	// Role Name of the user's role
+	Role string `json:"role"`
 }
 
-// UserDataRole User role
-type UserDataRole string
+// VerifyEmailRequest defines model for VerifyEmailRequest.
+type VerifyEmailRequest struct {
+	// Token Token from the verification email
+	Token string `json:"token"`
+}
 
 // IdParam defines model for IdParam.
 type IdParam = openapi_types.UUID
@@ -233,6 +714,12 @@ type PageParam = int
 // PerPageParam defines model for PerPageParam.
 type PerPageParam = int
 
+// PermissionNameParam defines model for PermissionNameParam.
+type PermissionNameParam = string
+
+// RoleNameParam defines model for RoleNameParam.
+type RoleNameParam = string
+
 // BadRequest defines model for BadRequest.
 type BadRequest = Error
 
@@ -248,9 +735,45 @@ type InternalServerError = Error
 // NotFound defines model for NotFound.
 type NotFound = Error
 
+// TooManyRequests defines model for TooManyRequests.
+type TooManyRequests = Error
+
 // Unauthorized defines model for Unauthorized.
 type Unauthorized = Error
 
+// GetLoginHistoryParams defines parameters for GetLoginHistory.
+type GetLoginHistoryParams struct {
+	// Page Page number
+	Page *PageParam `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage Items per page
+	PerPage *PerPageParam `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
+// ListImpersonationsParams defines parameters for ListImpersonations.
+type ListImpersonationsParams struct {
+	// Page Page number
+	Page *PageParam `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage Items per page
+	PerPage *PerPageParam `form:"per_page,omitempty" json:"per_page,omitempty"`
+
+	// ActorId Only impersonations by this admin
+	ActorId *openapi_types.UUID `form:"actor_id,omitempty" json:"actor_id,omitempty"`
+
+	// TargetId Only impersonations of this user
+	TargetId *openapi_types.UUID `form:"target_id,omitempty" json:"target_id,omitempty"`
+}
+
+// ListInvitationsParams defines parameters for ListInvitations.
+type ListInvitationsParams struct {
+	// Page Page number
+	Page *PageParam `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage Items per page
+	PerPage *PerPageParam `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
 // ListProductsParams defines parameters for ListProducts.
 type ListProductsParams struct {
 	// Page Page number
@@ -258,6 +781,9 @@ type ListProductsParams struct {
 
 	// PerPage Items per page
 	PerPage *PerPageParam `form:"per_page,omitempty" json:"per_page,omitempty"`
+
+	// Mine Only products owned by the caller
+	Mine *bool `form:"mine,omitempty" json:"mine,omitempty"`
 }
 
 // ListUsersParams defines parameters for ListUsers.
@@ -269,35 +795,215 @@ type ListUsersParams struct {
 	PerPage *PerPageParam `form:"per_page,omitempty" json:"per_page,omitempty"`
 }
 
+// ConfirmEmailChangeJSONRequestBody defines body for ConfirmEmailChange for application/json ContentType.
+type ConfirmEmailChangeJSONRequestBody = VerifyEmailRequest
+
+// VerifyEmailJSONRequestBody defines body for VerifyEmail for application/json ContentType.
+type VerifyEmailJSONRequestBody = VerifyEmailRequest
+
+// ResendVerificationEmailJSONRequestBody defines body for ResendVerificationEmail for application/json ContentType.
+type ResendVerificationEmailJSONRequestBody = ResendVerificationRequest
+
+// IntrospectTokenFormdataRequestBody defines body for IntrospectToken for application/x-www-form-urlencoded ContentType.
+type IntrospectTokenFormdataRequestBody = IntrospectionRequest
+
+// AcceptInvitationJSONRequestBody defines body for AcceptInvitation for application/json ContentType.
+type AcceptInvitationJSONRequestBody = AcceptInvitationRequest
+
 // LoginJSONRequestBody defines body for Login for application/json ContentType.
 type LoginJSONRequestBody = LoginRequest
 
+// LogoutJSONRequestBody defines body for Logout for application/json ContentType.
+type LogoutJSONRequestBody = LogoutRequest
+
+// RequestMagicLinkJSONRequestBody defines body for RequestMagicLink for application/json ContentType.
+type RequestMagicLinkJSONRequestBody = MagicLinkRequest
+
+// RedeemMagicLinkJSONRequestBody defines body for RedeemMagicLink for application/json ContentType.
+type RedeemMagicLinkJSONRequestBody = MagicLinkVerifyRequest
+
+// UpdateCurrentUserJSONRequestBody defines body for UpdateCurrentUser for application/json ContentType.
+type UpdateCurrentUserJSONRequestBody = UpdateProfileRequest
+
+// ChangePasswordJSONRequestBody defines body for ChangePassword for application/json ContentType.
+type ChangePasswordJSONRequestBody = ChangePasswordRequest
+
+// ConfirmTotpJSONRequestBody defines body for ConfirmTotp for application/json ContentType.
+type ConfirmTotpJSONRequestBody = TotpCodeRequest
+
+// DisableTotpJSONRequestBody defines body for DisableTotp for application/json ContentType.
+type DisableTotpJSONRequestBody = TotpCodeRequest
+
+// VerifyMfaJSONRequestBody defines body for VerifyMfa for application/json ContentType.
+type VerifyMfaJSONRequestBody = MfaVerifyRequest
+
+// CompleteOidcLoginJSONRequestBody defines body for CompleteOidcLogin for application/json ContentType.
+type CompleteOidcLoginJSONRequestBody = OidcCallbackRequest
+
+// ForgotPasswordJSONRequestBody defines body for ForgotPassword for application/json ContentType.
+type ForgotPasswordJSONRequestBody = ForgotPasswordRequest
+
+// ResetPasswordJSONRequestBody defines body for ResetPassword for application/json ContentType.
+type ResetPasswordJSONRequestBody = ResetPasswordRequest
+
+// RefreshTokenJSONRequestBody defines body for RefreshToken for application/json ContentType.
+type RefreshTokenJSONRequestBody = RefreshRequest
+
 // RegisterJSONRequestBody defines body for Register for application/json ContentType.
 type RegisterJSONRequestBody = RegisterRequest
 
+// CreatePersonalAccessTokenJSONRequestBody defines body for CreatePersonalAccessToken for application/json ContentType.
+type CreatePersonalAccessTokenJSONRequestBody = CreatePersonalAccessTokenRequest
+
+// FinishPasskeyLoginJSONRequestBody defines body for FinishPasskeyLogin for application/json ContentType.
+type FinishPasskeyLoginJSONRequestBody = PasskeyAssertionCredential
+
+// FinishPasskeyRegistrationJSONRequestBody defines body for FinishPasskeyRegistration for application/json ContentType.
+type FinishPasskeyRegistrationJSONRequestBody = PasskeyRegistrationRequest
+
+// CreatePermissionJSONRequestBody defines body for CreatePermission for application/json ContentType.
+type CreatePermissionJSONRequestBody = CreatePermissionRequest
+
 // CreateProductJSONRequestBody defines body for CreateProduct for application/json ContentType.
 type CreateProductJSONRequestBody = CreateProductRequest
 
 // UpdateProductJSONRequestBody defines body for UpdateProduct for application/json ContentType.
 type UpdateProductJSONRequestBody = CreateProductRequest
 
+// CreateRoleJSONRequestBody defines body for CreateRole for application/json ContentType.
+type CreateRoleJSONRequestBody = CreateRoleRequest
+
+// UpdateRoleJSONRequestBody defines body for UpdateRole for application/json ContentType.
+type UpdateRoleJSONRequestBody = UpdateRoleRequest
+
 // CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
 type CreateUserJSONRequestBody = CreateUserRequest
 
 // UpdateUserJSONRequestBody defines body for UpdateUser for application/json ContentType.
 type UpdateUserJSONRequestBody = UpdateUserRequest
 
+// ImpersonateUserJSONRequestBody defines body for ImpersonateUser for application/json ContentType.
+type ImpersonateUserJSONRequestBody = ImpersonateRequest
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Confirm email change
+	// (POST /auth/email/change/confirm)
+	ConfirmEmailChange(c *gin.Context)
+	// Verify email address
+	// (POST /auth/email/verify)
+	VerifyEmail(c *gin.Context)
+	// Resend verification email
+	// (POST /auth/email/verify/resend)
+	ResendVerificationEmail(c *gin.Context)
+	// Introspect a token
+	// (POST /auth/introspect)
+	IntrospectToken(c *gin.Context)
+	// Accept an invitation
+	// (POST /auth/invitations/accept)
+	AcceptInvitation(c *gin.Context)
 	// Login user
 	// (POST /auth/login)
 	Login(c *gin.Context)
+	// Logout
+	// (POST /auth/logout)
+	Logout(c *gin.Context)
+	// Request a login link
+	// (POST /auth/magic-link)
+	RequestMagicLink(c *gin.Context)
+	// Log in with a login link
+	// (POST /auth/magic-link/verify)
+	RedeemMagicLink(c *gin.Context)
 	// Get current user
 	// (GET /auth/me)
 	GetCurrentUser(c *gin.Context)
+	// Update current user
+	// (PATCH /auth/me)
+	UpdateCurrentUser(c *gin.Context)
+	// Get login history
+	// (GET /auth/me/login-history)
+	GetLoginHistory(c *gin.Context, params GetLoginHistoryParams)
+	// Change password
+	// (POST /auth/me/password)
+	ChangePassword(c *gin.Context)
+	// Confirm TOTP enrollment
+	// (POST /auth/mfa/totp/confirm)
+	ConfirmTotp(c *gin.Context)
+	// Disable TOTP
+	// (POST /auth/mfa/totp/disable)
+	DisableTotp(c *gin.Context)
+	// Start TOTP enrollment
+	// (POST /auth/mfa/totp/enroll)
+	EnrollTotp(c *gin.Context)
+	// Complete a two-factor login
+	// (POST /auth/mfa/verify)
+	VerifyMfa(c *gin.Context)
+	// Start single sign-on
+	// (GET /auth/oidc/authorize)
+	StartOidcLogin(c *gin.Context)
+	// Complete single sign-on
+	// (POST /auth/oidc/callback)
+	CompleteOidcLogin(c *gin.Context)
+	// Request a password reset
+	// (POST /auth/password/forgot)
+	ForgotPassword(c *gin.Context)
+	// Reset password
+	// (POST /auth/password/reset)
+	ResetPassword(c *gin.Context)
+	// Refresh access token
+	// (POST /auth/refresh)
+	RefreshToken(c *gin.Context)
 	// Register new user
 	// (POST /auth/register)
 	Register(c *gin.Context)
+	// List personal access tokens
+	// (GET /auth/tokens)
+	ListPersonalAccessTokens(c *gin.Context)
+	// Create a personal access token
+	// (POST /auth/tokens)
+	CreatePersonalAccessToken(c *gin.Context)
+	// Revoke a personal access token
+	// (DELETE /auth/tokens/{id})
+	RevokePersonalAccessToken(c *gin.Context, id IdParam)
+	// List passkeys
+	// (GET /auth/webauthn/credentials)
+	ListPasskeys(c *gin.Context)
+	// Delete a passkey
+	// (DELETE /auth/webauthn/credentials/{id})
+	DeletePasskey(c *gin.Context, id IdParam)
+	// Log in with a passkey
+	// (POST /auth/webauthn/login)
+	FinishPasskeyLogin(c *gin.Context)
+	// Start passkey login
+	// (POST /auth/webauthn/login/options)
+	StartPasskeyLogin(c *gin.Context)
+	// Register a passkey
+	// (POST /auth/webauthn/register)
+	FinishPasskeyRegistration(c *gin.Context)
+	// Start passkey registration
+	// (POST /auth/webauthn/register/options)
+	StartPasskeyRegistration(c *gin.Context)
+	// List impersonations
+	// (GET /impersonations)
+	ListImpersonations(c *gin.Context, params ListImpersonationsParams)
+	// List pending invitations
+	// (GET /invitations)
+	ListInvitations(c *gin.Context, params ListInvitationsParams)
+	// Revoke an invitation
+	// (DELETE /invitations/{id})
+	RevokeInvitation(c *gin.Context, id IdParam)
+	// Resend an invitation
+	// (POST /invitations/{id}/resend)
+	ResendInvitation(c *gin.Context, id IdParam)
+	// List permissions
+	// (GET /permissions)
+	ListPermissions(c *gin.Context)
+	// Create a permission
+	// (POST /permissions)
+	CreatePermission(c *gin.Context)
+	// Delete a permission
+	// (DELETE /permissions/{name})
+	DeletePermission(c *gin.Context, name PermissionNameParam)
 	// Get all products
 	// (GET /products)
 	ListProducts(c *gin.Context, params ListProductsParams)
@@ -313,10 +1019,25 @@ type ServerInterface interface {
 	// Update product
 	// (PUT /products/{id})
 	UpdateProduct(c *gin.Context, id IdParam)
+	// List roles
+	// (GET /roles)
+	ListRoles(c *gin.Context)
+	// Create a role
+	// (POST /roles)
+	CreateRole(c *gin.Context)
+	// Delete a role
+	// (DELETE /roles/{name})
+	DeleteRole(c *gin.Context, name RoleNameParam)
+	// Get a role
+	// (GET /roles/{name})
+	GetRole(c *gin.Context, name RoleNameParam)
+	// Update a role
+	// (PUT /roles/{name})
+	UpdateRole(c *gin.Context, name RoleNameParam)
 	// Get all users
 	// (GET /users)
 	ListUsers(c *gin.Context, params ListUsersParams)
-	// Create new user
+	// Invite a new user
 	// (POST /users)
 	CreateUser(c *gin.Context)
 	// Delete user
@@ -328,6 +1049,15 @@ type ServerInterface interface {
 	// Update user
 	// (PUT /users/{id})
 	UpdateUser(c *gin.Context, id IdParam)
+	// Impersonate a user
+	// (POST /users/{id}/impersonate)
+	ImpersonateUser(c *gin.Context, id IdParam)
+	// Clear a login lockout
+	// (DELETE /users/{id}/lockout)
+	ClearUserLockout(c *gin.Context, id IdParam)
+	// Revoke all sessions of a user
+	// (DELETE /users/{id}/sessions)
+	RevokeUserSessions(c *gin.Context, id IdParam)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -339,8 +1069,8 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
-// Login operation middleware
-func (siw *ServerInterfaceWrapper) Login(c *gin.Context) {
+// ConfirmEmailChange operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmEmailChange(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -349,13 +1079,11 @@ func (siw *ServerInterfaceWrapper) Login(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.Login(c)
+	siw.Handler.ConfirmEmailChange(c)
 }
 
-// GetCurrentUser operation middleware
-func (siw *ServerInterfaceWrapper) GetCurrentUser(c *gin.Context) {
-
-	c.Set(BearerAuthScopes, []string{"user", "admin"})
+// VerifyEmail operation middleware
+func (siw *ServerInterfaceWrapper) VerifyEmail(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -364,11 +1092,11 @@ func (siw *ServerInterfaceWrapper) GetCurrentUser(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetCurrentUser(c)
+	siw.Handler.VerifyEmail(c)
 }
 
-// Register operation middleware
-func (siw *ServerInterfaceWrapper) Register(c *gin.Context) {
+// ResendVerificationEmail operation middleware
+func (siw *ServerInterfaceWrapper) ResendVerificationEmail(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -377,34 +1105,13 @@ func (siw *ServerInterfaceWrapper) Register(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.Register(c)
+	siw.Handler.ResendVerificationEmail(c)
 }
 
-// ListProducts operation middleware
-func (siw *ServerInterfaceWrapper) ListProducts(c *gin.Context) {
-
-	var err error
-
-	c.Set(BearerAuthScopes, []string{})
-
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListProductsParams
-
-	// ------------- Optional query parameter "page" -------------
-
-	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
-		return
-	}
-
-	// ------------- Optional query parameter "per_page" -------------
+// IntrospectToken operation middleware
+func (siw *ServerInterfaceWrapper) IntrospectToken(c *gin.Context) {
 
-	err = runtime.BindQueryParameter("form", true, false, "per_page", c.Request.URL.Query(), &params.PerPage)
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter per_page: %w", err), http.StatusBadRequest)
-		return
-	}
+	c.Set(ServiceAuthScopes, []string{})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -413,13 +1120,11 @@ func (siw *ServerInterfaceWrapper) ListProducts(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ListProducts(c, params)
+	siw.Handler.IntrospectToken(c)
 }
 
-// CreateProduct operation middleware
-func (siw *ServerInterfaceWrapper) CreateProduct(c *gin.Context) {
-
-	c.Set(BearerAuthScopes, []string{})
+// AcceptInvitation operation middleware
+func (siw *ServerInterfaceWrapper) AcceptInvitation(c *gin.Context) {
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -428,7 +1133,702 @@ func (siw *ServerInterfaceWrapper) CreateProduct(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.CreateProduct(c)
+	siw.Handler.AcceptInvitation(c)
+}
+
+// Login operation middleware
+func (siw *ServerInterfaceWrapper) Login(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Login(c)
+}
+
+// Logout operation middleware
+func (siw *ServerInterfaceWrapper) Logout(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Logout(c)
+}
+
+// RequestMagicLink operation middleware
+func (siw *ServerInterfaceWrapper) RequestMagicLink(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RequestMagicLink(c)
+}
+
+// RedeemMagicLink operation middleware
+func (siw *ServerInterfaceWrapper) RedeemMagicLink(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RedeemMagicLink(c)
+}
+
+// GetCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) GetCurrentUser(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{"profile:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"profile:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCurrentUser(c)
+}
+
+// UpdateCurrentUser operation middleware
+func (siw *ServerInterfaceWrapper) UpdateCurrentUser(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateCurrentUser(c)
+}
+
+// GetLoginHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetLoginHistory(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetLoginHistoryParams
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", c.Request.URL.Query(), &params.PerPage)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter per_page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetLoginHistory(c, params)
+}
+
+// ChangePassword operation middleware
+func (siw *ServerInterfaceWrapper) ChangePassword(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ChangePassword(c)
+}
+
+// ConfirmTotp operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmTotp(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ConfirmTotp(c)
+}
+
+// DisableTotp operation middleware
+func (siw *ServerInterfaceWrapper) DisableTotp(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DisableTotp(c)
+}
+
+// EnrollTotp operation middleware
+func (siw *ServerInterfaceWrapper) EnrollTotp(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.EnrollTotp(c)
+}
+
+// VerifyMfa operation middleware
+func (siw *ServerInterfaceWrapper) VerifyMfa(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.VerifyMfa(c)
+}
+
+// StartOidcLogin operation middleware
+func (siw *ServerInterfaceWrapper) StartOidcLogin(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StartOidcLogin(c)
+}
+
+// CompleteOidcLogin operation middleware
+func (siw *ServerInterfaceWrapper) CompleteOidcLogin(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CompleteOidcLogin(c)
+}
+
+// ForgotPassword operation middleware
+func (siw *ServerInterfaceWrapper) ForgotPassword(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ForgotPassword(c)
+}
+
+// ResetPassword operation middleware
+func (siw *ServerInterfaceWrapper) ResetPassword(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ResetPassword(c)
+}
+
+// RefreshToken operation middleware
+func (siw *ServerInterfaceWrapper) RefreshToken(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RefreshToken(c)
+}
+
+// Register operation middleware
+func (siw *ServerInterfaceWrapper) Register(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.Register(c)
+}
+
+// ListPersonalAccessTokens operation middleware
+func (siw *ServerInterfaceWrapper) ListPersonalAccessTokens(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListPersonalAccessTokens(c)
+}
+
+// CreatePersonalAccessToken operation middleware
+func (siw *ServerInterfaceWrapper) CreatePersonalAccessToken(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreatePersonalAccessToken(c)
+}
+
+// RevokePersonalAccessToken operation middleware
+func (siw *ServerInterfaceWrapper) RevokePersonalAccessToken(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id IdParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RevokePersonalAccessToken(c, id)
+}
+
+// ListPasskeys operation middleware
+func (siw *ServerInterfaceWrapper) ListPasskeys(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListPasskeys(c)
+}
+
+// DeletePasskey operation middleware
+func (siw *ServerInterfaceWrapper) DeletePasskey(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id IdParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeletePasskey(c, id)
+}
+
+// FinishPasskeyLogin operation middleware
+func (siw *ServerInterfaceWrapper) FinishPasskeyLogin(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.FinishPasskeyLogin(c)
+}
+
+// StartPasskeyLogin operation middleware
+func (siw *ServerInterfaceWrapper) StartPasskeyLogin(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StartPasskeyLogin(c)
+}
+
+// FinishPasskeyRegistration operation middleware
+func (siw *ServerInterfaceWrapper) FinishPasskeyRegistration(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.FinishPasskeyRegistration(c)
+}
+
+// StartPasskeyRegistration operation middleware
+func (siw *ServerInterfaceWrapper) StartPasskeyRegistration(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.StartPasskeyRegistration(c)
+}
+
+// ListImpersonations operation middleware
+func (siw *ServerInterfaceWrapper) ListImpersonations(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{"users:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListImpersonationsParams
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", c.Request.URL.Query(), &params.PerPage)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter per_page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "actor_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor_id", c.Request.URL.Query(), &params.ActorId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter actor_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "target_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "target_id", c.Request.URL.Query(), &params.TargetId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter target_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListImpersonations(c, params)
+}
+
+// ListInvitations operation middleware
+func (siw *ServerInterfaceWrapper) ListInvitations(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{"users:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListInvitationsParams
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", c.Request.URL.Query(), &params.PerPage)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter per_page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListInvitations(c, params)
+}
+
+// RevokeInvitation operation middleware
+func (siw *ServerInterfaceWrapper) RevokeInvitation(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id IdParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"users:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RevokeInvitation(c, id)
+}
+
+// ResendInvitation operation middleware
+func (siw *ServerInterfaceWrapper) ResendInvitation(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id IdParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"users:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ResendInvitation(c, id)
+}
+
+// ListPermissions operation middleware
+func (siw *ServerInterfaceWrapper) ListPermissions(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{"roles:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"roles:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListPermissions(c)
+}
+
+// CreatePermission operation middleware
+func (siw *ServerInterfaceWrapper) CreatePermission(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{"roles:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreatePermission(c)
+}
+
+// DeletePermission operation middleware
+func (siw *ServerInterfaceWrapper) DeletePermission(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name PermissionNameParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", c.Param("name"), &name, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"roles:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeletePermission(c, name)
+}
+
+// ListProducts operation middleware
+func (siw *ServerInterfaceWrapper) ListProducts(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{"products:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"products:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListProductsParams
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", c.Request.URL.Query(), &params.PerPage)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter per_page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "mine" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mine", c.Request.URL.Query(), &params.Mine)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter mine: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListProducts(c, params)
+}
+
+// CreateProduct operation middleware
+func (siw *ServerInterfaceWrapper) CreateProduct(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{"products:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"products:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateProduct(c)
 }
 
 // DeleteProduct operation middleware
@@ -445,7 +1845,9 @@ func (siw *ServerInterfaceWrapper) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"products:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"products:write"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -471,7 +1873,9 @@ func (siw *ServerInterfaceWrapper) GetProduct(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"products:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"products:read"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -497,7 +1901,9 @@ func (siw *ServerInterfaceWrapper) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"products:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"products:write"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -509,12 +1915,126 @@ func (siw *ServerInterfaceWrapper) UpdateProduct(c *gin.Context) {
 	siw.Handler.UpdateProduct(c, id)
 }
 
+// ListRoles operation middleware
+func (siw *ServerInterfaceWrapper) ListRoles(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{"roles:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"roles:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListRoles(c)
+}
+
+// CreateRole operation middleware
+func (siw *ServerInterfaceWrapper) CreateRole(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{"roles:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateRole(c)
+}
+
+// DeleteRole operation middleware
+func (siw *ServerInterfaceWrapper) DeleteRole(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name RoleNameParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", c.Param("name"), &name, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"roles:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteRole(c, name)
+}
+
+// GetRole operation middleware
+func (siw *ServerInterfaceWrapper) GetRole(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name RoleNameParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", c.Param("name"), &name, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"roles:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"roles:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetRole(c, name)
+}
+
+// UpdateRole operation middleware
+func (siw *ServerInterfaceWrapper) UpdateRole(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "name" -------------
+	var name RoleNameParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", c.Param("name"), &name, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"roles:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateRole(c, name)
+}
+
 // ListUsers operation middleware
 func (siw *ServerInterfaceWrapper) ListUsers(c *gin.Context) {
 
 	var err error
 
-	c.Set(BearerAuthScopes, []string{"admin"})
+	c.Set(BearerAuthScopes, []string{"users:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:read"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ListUsersParams
@@ -548,7 +2068,9 @@ func (siw *ServerInterfaceWrapper) ListUsers(c *gin.Context) {
 // CreateUser operation middleware
 func (siw *ServerInterfaceWrapper) CreateUser(c *gin.Context) {
 
-	c.Set(BearerAuthScopes, []string{"admin"})
+	c.Set(BearerAuthScopes, []string{"users:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:write"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -574,7 +2096,9 @@ func (siw *ServerInterfaceWrapper) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{"admin"})
+	c.Set(BearerAuthScopes, []string{"users:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:write"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -600,7 +2124,9 @@ func (siw *ServerInterfaceWrapper) GetUser(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{"admin"})
+	c.Set(BearerAuthScopes, []string{"users:read"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:read"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -626,7 +2152,9 @@ func (siw *ServerInterfaceWrapper) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{"admin"})
+	c.Set(BearerAuthScopes, []string{"users:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:write"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -638,6 +2166,88 @@ func (siw *ServerInterfaceWrapper) UpdateUser(c *gin.Context) {
 	siw.Handler.UpdateUser(c, id)
 }
 
+// ImpersonateUser operation middleware
+func (siw *ServerInterfaceWrapper) ImpersonateUser(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id IdParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"users:impersonate"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ImpersonateUser(c, id)
+}
+
+// ClearUserLockout operation middleware
+func (siw *ServerInterfaceWrapper) ClearUserLockout(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id IdParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"users:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ClearUserLockout(c, id)
+}
+
+// RevokeUserSessions operation middleware
+func (siw *ServerInterfaceWrapper) RevokeUserSessions(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id IdParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"users:write"})
+
+	c.Set(ApiKeyAuthScopes, []string{"users:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.RevokeUserSessions(c, id)
+}
+
 // GinServerOptions provides options for the Gin server.
 type GinServerOptions struct {
 	BaseURL      string
@@ -665,67 +2275,259 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.POST(options.BaseURL+"/auth/email/change/confirm", wrapper.ConfirmEmailChange)
+	router.POST(options.BaseURL+"/auth/email/verify", wrapper.VerifyEmail)
+	router.POST(options.BaseURL+"/auth/email/verify/resend", wrapper.ResendVerificationEmail)
+	router.POST(options.BaseURL+"/auth/introspect", wrapper.IntrospectToken)
+	router.POST(options.BaseURL+"/auth/invitations/accept", wrapper.AcceptInvitation)
 	router.POST(options.BaseURL+"/auth/login", wrapper.Login)
+	router.POST(options.BaseURL+"/auth/logout", wrapper.Logout)
+	router.POST(options.BaseURL+"/auth/magic-link", wrapper.RequestMagicLink)
+	router.POST(options.BaseURL+"/auth/magic-link/verify", wrapper.RedeemMagicLink)
 	router.GET(options.BaseURL+"/auth/me", wrapper.GetCurrentUser)
+	router.PATCH(options.BaseURL+"/auth/me", wrapper.UpdateCurrentUser)
+	router.GET(options.BaseURL+"/auth/me/login-history", wrapper.GetLoginHistory)
+	router.POST(options.BaseURL+"/auth/me/password", wrapper.ChangePassword)
+	router.POST(options.BaseURL+"/auth/mfa/totp/confirm", wrapper.ConfirmTotp)
+	router.POST(options.BaseURL+"/auth/mfa/totp/disable", wrapper.DisableTotp)
+	router.POST(options.BaseURL+"/auth/mfa/totp/enroll", wrapper.EnrollTotp)
+	router.POST(options.BaseURL+"/auth/mfa/verify", wrapper.VerifyMfa)
+	router.GET(options.BaseURL+"/auth/oidc/authorize", wrapper.StartOidcLogin)
+	router.POST(options.BaseURL+"/auth/oidc/callback", wrapper.CompleteOidcLogin)
+	router.POST(options.BaseURL+"/auth/password/forgot", wrapper.ForgotPassword)
+	router.POST(options.BaseURL+"/auth/password/reset", wrapper.ResetPassword)
+	router.POST(options.BaseURL+"/auth/refresh", wrapper.RefreshToken)
 	router.POST(options.BaseURL+"/auth/register", wrapper.Register)
+	router.GET(options.BaseURL+"/auth/tokens", wrapper.ListPersonalAccessTokens)
+	router.POST(options.BaseURL+"/auth/tokens", wrapper.CreatePersonalAccessToken)
+	router.DELETE(options.BaseURL+"/auth/tokens/:id", wrapper.RevokePersonalAccessToken)
+	router.GET(options.BaseURL+"/auth/webauthn/credentials", wrapper.ListPasskeys)
+	router.DELETE(options.BaseURL+"/auth/webauthn/credentials/:id", wrapper.DeletePasskey)
+	router.POST(options.BaseURL+"/auth/webauthn/login", wrapper.FinishPasskeyLogin)
+	router.POST(options.BaseURL+"/auth/webauthn/login/options", wrapper.StartPasskeyLogin)
+	router.POST(options.BaseURL+"/auth/webauthn/register", wrapper.FinishPasskeyRegistration)
+	router.POST(options.BaseURL+"/auth/webauthn/register/options", wrapper.StartPasskeyRegistration)
+	router.GET(options.BaseURL+"/impersonations", wrapper.ListImpersonations)
+	router.GET(options.BaseURL+"/invitations", wrapper.ListInvitations)
+	router.DELETE(options.BaseURL+"/invitations/:id", wrapper.RevokeInvitation)
+	router.POST(options.BaseURL+"/invitations/:id/resend", wrapper.ResendInvitation)
+	router.GET(options.BaseURL+"/permissions", wrapper.ListPermissions)
+	router.POST(options.BaseURL+"/permissions", wrapper.CreatePermission)
+	router.DELETE(options.BaseURL+"/permissions/:name", wrapper.DeletePermission)
 	router.GET(options.BaseURL+"/products", wrapper.ListProducts)
 	router.POST(options.BaseURL+"/products", wrapper.CreateProduct)
 	router.DELETE(options.BaseURL+"/products/:id", wrapper.DeleteProduct)
 	router.GET(options.BaseURL+"/products/:id", wrapper.GetProduct)
 	router.PUT(options.BaseURL+"/products/:id", wrapper.UpdateProduct)
+	router.GET(options.BaseURL+"/roles", wrapper.ListRoles)
+	router.POST(options.BaseURL+"/roles", wrapper.CreateRole)
+	router.DELETE(options.BaseURL+"/roles/:name", wrapper.DeleteRole)
+	router.GET(options.BaseURL+"/roles/:name", wrapper.GetRole)
+	router.PUT(options.BaseURL+"/roles/:name", wrapper.UpdateRole)
 	router.GET(options.BaseURL+"/users", wrapper.ListUsers)
 	router.POST(options.BaseURL+"/users", wrapper.CreateUser)
 	router.DELETE(options.BaseURL+"/users/:id", wrapper.DeleteUser)
 	router.GET(options.BaseURL+"/users/:id", wrapper.GetUser)
 	router.PUT(options.BaseURL+"/users/:id", wrapper.UpdateUser)
+	router.POST(options.BaseURL+"/users/:id/impersonate", wrapper.ImpersonateUser)
+	router.DELETE(options.BaseURL+"/users/:id/lockout", wrapper.ClearUserLockout)
+	router.DELETE(options.BaseURL+"/users/:id/sessions", wrapper.RevokeUserSessions)
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xb227jONJ+FYL/D+wMoMTOoTMTX22Os85knawTdw+2EQSMVLbZLZFqkkq3J/C7L3iQ",
-	"LNmULTsJYOzsXWSRxWLVV1Ufi8oLDnmScgZMSdx5wSkRJAEFwjx1o1v9rP+MQIaCpopyhju4D5JnIgQ0",
-	"GHTPcYDhB0nSGHAH7+0fwOGHo1924Nfjp529/ehghxx+ONo53D862jvc++Ww3W7jAFMtJSVqjAPMSKJn",
-	"0ggHWMC3jAqIcEeJDAIswzEkRCsw5CIhCndwlpmRapLqWVIJykZ4Og3wLRlBjb76FWJZ8gQiX/xbBmIy",
-	"Wz0lI8Dl9SIYkixWuLMX4IQymmSJ+dutS5mCEQi7MIgla3cVJBKlIJBbw7s8iMclKrQDnJAfTod2e4VG",
-	"U21HmXImwbjxlER9+JaBVPop5EwBM3+SNI1pSLSirS9Sa/syc6UeGWnBpyfnj/2Lfw0u7u5xgBOQUiva",
-	"wV32TGIaIcrSTOFpWfP/FzDEHfx/rRm6WvatbF0IwZ2WVUOdkggJp+c0wGecDWMabqbz2U3v8rp7VlX4",
-	"IiE0RiQWQKIJEjCiUoEG2+t1z5VFO6iIjXwh+EGlknqRSy6eaBQB22hPlzf90+75+UWvsqmTMAQpUQSM",
-	"vslOZjpOA9xlCgQj8R2IZxB2ziaqd3v3F/3eyfXjRb9/059DkV0CSbMGAqvYq/dRK7fH1SXPWLTRRno3",
-	"94+XN4PeeWUPhcsZV2hohL9+A36hA0YyNeaC/gmb7WDQOxnc/+Om3/33RXUTJ5kaA1NOAioy8et3UtF5",
-	"WsgzuUmv2nfJyhQgwVMQitrMpfhXGyxVgVef7hGpqmtHlisRTK7GT7+F9IZedQd/dvd6tCu7rP8hPOse",
-	"db+mf3w8uzre3d1dLCUBziSIVbsdSBDnRBFs021etj7jXBMj5KGQzp++QGgTmwCi4FbwKAtVKTFXtx4S",
-	"BSMuJhUv4osYQiU4o6HUxSOLY/KkX9hqubCRitnKctzqqDyggTxbrXyCevqNqVLXwEZqjDv7Hz6YOlU8",
-	"e+SlgoZVgcfHu8fHwazaRzzTChVzXRHXIFI8/FqZayqjpz6XvcOsnnbhXEi9l7SXa10Eup5UzfGFj9nf",
-	"3eNuyBNc2ood3siqV3zM0Dmfs2he+ZdalEj5nYtoMWj0VlD+Gv30ncYxegI0JnIM0c+V0MlH7e0flDdQ",
-	"yK5oceTRQnCbcwoGY6MhwMA0afmMSZTQIkgCPDIGfvCxOo/zckMW+vj8VxSrubAyWbBs627v48l19/yx",
-	"27sd3PvcYyqHmUyiiGprkvi2IpRqimfy1fxc9wMRgkxKzzM1i/xbUanCrFZZJRfhs8I1H1G2GsAepIAl",
-	"S1EkQMoKOjbCeENcrgnD5YZphJN/Qn35qbHPWSYEMIWywk6vt88sYmpXMkPKC7ng8ZavRxqtELfp0W31",
-	"QcxjY0UWrZvO495zlglmJ6NqpvcNVVyReHVFcAONVLlSrG8/t2REmeEd5swn6/fW9BRZ3eemBz+fqrY+",
-	"L+cXc4dlV9KLEcHmBCQ0VTR6JMqDRv3OcDeagFQkScvoioiCHf0Gr+Y1fv2r3ObV3McXULmod4qlGTXw",
-	"L5vXw7dmY/7Vcs70Gq5WlXynf0bfMsIUVRWc1QRulka1cLomUiE7YG1EzRUO44vGTLHvGgnrltmPpsa/",
-	"cZ31A+Yyi2ODFsSHSI0B5XRsTcZ5sFZlvy3Ipsta6AiFYyJIqEDIN6WcryCKAwOZZkR/tf2pfCShos9Q",
-	"ooNPnMdAWNk9b8bqe/C9ROp5aulpgBbp/Sto/PqsfdHK7kg9V4SWlAfDC8PNa8QScvs3+dZhR+uI7btV",
-	"hgrQCsJga1dVj09jUGMQRdwjKpGbGiyBqNdswzyRbJA89tc5KHpMmfPf9cC4vGgYwfH7VA4LloeaYDC9",
-	"o3VOZP+FoF2KUhKGPGOqgtZCowpHezPwNjyVrYPGVae1lfhxSpRttwgpTbEgzARVk7twDIkF00lKf4fJ",
-	"SaaD78VeO42BREYNd+/0x87JbXfnd5jMNCNmlt79KRABIp//ZJ4ucxdffbrPL6uMD8zbmZSxUqntBVM2",
-	"5HmnmtiTiEM5llmacqHmgOtUO7ntojs7AC82xy/u7odZjPSg71SN59rBWg+qjNlPSfgVWKRH4gA/g5BW",
-	"wt5ue7etBfMUGEkp7uCD3fbugaELamwM2NJSWzEfUXPMSLn0pJBS3zxHLouQAJUJhq4+3RetaR3nRrtu",
-	"pOmqkWq9D1Kd8miyXj8/N6In9Gd0ocKrGnfyK12jaRWjOvLmLxj32+31dHd9/TV69HlLfsmudTprfP88",
-	"S0Q2lTjQlZKBjX278LSx6Sr3GZ67EGNaJDNzazfMYu2Uw/ZendjCzK3FS5QsSYg+vzuZeX4hI2kSkg7b",
-	"Bz3QotjmxRF4EPwbKBS61lApjiBydIHZvG7jqori30C5ptLALv86XCzxbtkdpRZXM4c3dl+pG+i75S33",
-	"z8pm2dyJLmvjzueXSr79nO/UFpaH6UPZ42WPrfB7fs1dn8DsTQciiMH3auk1mdWRDhaVm7RVFOQn4HdK",
-	"Z4ux+doEN39kb5Tj9v6X45rlOMuPis8rStkunthQaa8OldJHK2bK8eopxRcj1fSYO7vAtz9aUtvmkrVp",
-	"sg9KUHjWkZLaBjBEKKZSIT5ExeyFOk+lup29LH9g9dm/odmQ1uyzommwenD5MySdLzbIxTMAVM8lkTut",
-	"FDddy/CR95w991+JuwlYnoPdrfo8yV0A2p0F1saYetuM7cnRJI7LyMhRV/z0MA2aJGU3fgFblS8J1k6+",
-	"zYLd+7XCm+XLVXhrBLMmUCkuNWzHaTsh45xedbkHNOV01Xqh0dTiJwblOa2em9910qpBkR0wQ9F6KSr/",
-	"RtSTcA6X3c3oNaMNbaonHa6eVHzptZYTnL2WOiBYWSFkCiEd0jCXg54meVtlgUG/g+nb2xR7lTS9Xc7W",
-	"SbrkIeMff5bOfN1D2zMkzH7jSdmoNsjs0Lfx9DYl+fY2JnnX8d1GwDnIrEzvmqduSEXtVB8PHbg3fwUS",
-	"avoRfzEGWt8s0EQ0x0WOOPvckIKavsBPRj7iLJ78XENGizbQeyWp8kXtVtDQgfR/e1NzLt4uAupHTImH",
-	"zp2Xc8wUKao5/XSSfNzTgeY9iaex/TaxTr/lnbVqrL4G7TTxuoRzvrXJ21sTZdvENusTcu6fCuMsZeSG",
-	"dNMbVLMvWraOaC5+bLMVLHPNHL5N/NIPMQeVuuxtJIrnHBTzGfsZYp4mwJT75ykc4EzE7la302rFPCTx",
-	"mEvV+bX9a7tFUtp63jPs0MvHKWc+QbLT0lN3Sx1uI+ahUHjJVauWCSxKOWWmteZ626afu6iI8VpCGBmB",
-	"3tZsvLVIrebeOQVTnz5M/xMAAP//UJ/XkUA7AAA=",
+	"H4sIAAAAAAAC/+y9C3PbNvYo/lUw+u1/2v6vZMuPOIkznXsd22mUOrbrR9rdKteFSUhCTQEqAFrRZvzd",
+	"7+AAIEEKlKiHE2e7MzvbWCSBA+C8X/jciPhwxBlhSjb2PzdGWOAhUUTAX534XP+t/xkTGQk6UpSzxn7j",
+	"gkieioig6+vOUaPZIJ/wcJSQxn5ja3uH7D7be94iL17etra2450W3n2219rd3tvb2t16vttutxvNBtWj",
+	"jLAaNJoNhof6Sxo3mg1B/kqpIHFjX4mUNBsyGpAh1gD0uBhi1dhvpCm8qSYj/ZVUgrJ+4+Gh2TjHfVIB",
+	"r36EWDq8JcJN/ldKxCSffYT7pOHPF5MeThPV2N9qNoaU0WE6hH/beSlTpE+EmZiIGXN3FBlKNCIC2TmC",
+	"0xNxMwOEdrMxxJ8sDO12HYiGVErK2SkeVm5K9hICKPxjFGTEhZL75JP+b/jA7EfVRzZ9RBc8ITMg0o+n",
+	"YSExVVysCYYH/bIccSYJoPhrHF+Qv1Iilf4r4kwRBv/Eo1FCI6wh2/xTavA+5zDpN2M97uuDo5uL41+u",
+	"jy+vGs3GkEipD3G/0WH3OKExomyUqsaDD9E/BOk19hv/s5lT3qZ5KjePheDCQFncmdc4RsLC+dBsHHLW",
+	"S2i0HMyHZ6dvTjqHRYCPh5gmCCeC4HiCBOlTqYje0dVhd8CiFsr4hpuIfKJSST3JGy5uaRwTttSa3pxd",
+	"vO4cHR2fFhZ1EEVEShQTRteykhzGh2ajwxQRDCeXRNwTYb5ZBvTO6dXxxenByc3xxcXZRQmLzBRIwhyI",
+	"GMBWXkfluKdcveEpi5dayOnZ1c2bs+vTo8IasiNnXKEeDL76AsKDXnH+HrOJpWe51CKuzs5u3h+c/tNR",
+	"9WVhMYpzNMRsgnqYJiRGCe9ThrBSZDhSsomUmCDcx5ShBCuyjqO6cjMKt6pmY0BwbCX0BVFi0jro6cmm",
+	"2OkliTiLJVIcjTFV6Jb0uCAaSMr6Bs5GgFfmgkSDc81wqgZc0H+T5dDi+vTg+urt2UXnX8dFzDhI1YAw",
+	"ZUdAGQ9ffdMKMD9k48GOaZ4wUh12TxXM63H/keAjIhQlVhWScsxFHNImzBPU4wKpAUGMjBGOIp4y9Qp+",
+	"kHhI0IgnNJogLC07FWadsGtEFkRcxIUgkWq95UKS1q1GKNAOMqUnAwYUgRPC+mrQ2N/afgGqgPv7xZRa",
+	"1GwofkfY9Bqu9M+oJ/gQAKbZfiCiJUFQwcql7O922GYO2MfsC377J4lATOkTvrDSdnqDyacRFUTe0AB4",
+	"lnPDLCihPaLoUEOJpMFpf/dettvZ3Bnuamh7gsjBTcUGnI3wXylBkrJ+QlqpJMh+gNzS8uN5F7eTD2qH",
+	"fXjGT5/HO/TiiPwzbQ/f7n3aTdqf/rr7pR1dbicv+bPrcaP+Ebz79QrhIg1MT00m7wa3P0X0jL7rXP+7",
+	"s3VKO7LDLp5Fh529zt3otw+H715ubGyEpk0lEfNI6FoScYQVrjzf4i42/UOzM4QO/nCAmVaIDW5UkliU",
+	"CkGYuvFJLYTyU0tjZHxTTZ9XtSlwZfoq7drUgkqgBvdKEKxIroxX7lZhkZ99OLefPQttEqjIU5SFhBWe",
+	"+zhSTvkHVhYlVCOHlhfRgER3sy0Cf580BY6Aa+kp/u/vuPXvj/r/2q2XN62P//9++Yd/zOUvAPzM7ZKc",
+	"4cTwCWBmlfuWo+xNjCeyYFm9bHtr3PHNrJ29Z7PNrHyD80067KCYjBI+CeyPh0dbgdOSER8ROctKk4Zd",
+	"f2qN8p+MuDFskkqEE8lRQodUkRjdTuAhHzMivpNI8KRgV/2utylOIyX3BcGAm1SRoQxYTQB8xzzMYcdC",
+	"4En44LLlLHiCVaIi1ixqDisLDDhL/mXbdo+TlLxCVKEIM61P3hIkiBKU3JM405LyMx5hdbOSQKjgtLDG",
+	"GftlDqual2JF+lxMigh5nJBICc5oJBvNBkuTBN8mxJnJU8dcYjH5OHZ25L9QY7xpCnEDnRo0KTMxj0i2",
+	"A+ONBI2KA758ufHypcfIY57eAp7bb63TRxOY4tFd4dutkOIQRmczsRuk+pS0B2PtDDzgCqlmtp+3mrsv",
+	"H/4Rkpoe35gmCH0gEvGeMcm1geC/PoNvNPO/x4IqMpuRzOcd1ZurlZVqLg9qa2G3/uQD9n/snxsRH/oC",
+	"v0LLDW36Oz5g6IiT2Tw9hK7Acn1xYzSmZmDr9c5jlm9+mVu7L2tIzaZdXGgjMzdFiXnwuLTozumHg5PO",
+	"0U3n9Pz6KrRP4DOAj3EcU70SnJwXBq2LBAEwMyOxAFLBpzZvK9wQoV14w0Wfq7nqaYZSxeOyrrI4FtpE",
+	"4T2Qstb8azRXw7/SIqoPsjMcGXmnqvmNINha5cUF/DowmoFGKa010Hys+BW6IyOlLS1YVRpThZQwsOYr",
+	"u6LRHVHof3ZfbG/tG2WRpwoc3EgO+FgCLg9HaoIiXNYWn83Vhkq7YNcxexssUy3uAI4UFzc0YCMcxEPK",
+	"0HjAC6tvNOfFGZqNCHhRfINVwVyJsSItRYck9I1TQbGahuTXATGbTaVMSWy1EvtFo1lzhtAaL0ikvRQ6",
+	"RtM0qqGe5k9FHdL6M9ZZOh3dWLQvUuZ2e2ejvbG1tbPxPPRZjodTjxQWfaKCJ6S5PRzQGMuFD0mj9g3u",
+	"W0fVbPSCETJM8WHKQC+cYAEF5iLlcv6PqxUcH7RME7O05iIBVevLBYeM83sBAxlTNYC/MJCU/p+SCEcK",
+	"RQmmww3UcZp1l91mXhatWrNYcx9BeqkkMeJ6jUxSRe8J0vsEIMmN7pocIhVqd8GhUdy64NkyJbgckWim",
+	"77DOJnKB7GQJwv4Dz/wOu5Ju9M83A8pUeIqRIsY3qc8bK3pLE6om1lScjDTdo5goEsF7zgWYbQjTBu/v",
+	"DQNT5vhxsN4Ufv9Yb5tr7GROJSUm9uYQPd/b20bUfx8JItNEbaAOwxFgDEwktbgREx0WGGgt6jZVXfvC",
+	"jz2cSNJE4wFWRAc99JoNeRsUm5IcAVtcEEmYQmPNsjFzGO+zJz2rI42pQWV6G7ZEi9/DsPO5XGmn9eCh",
+	"fTbLn573AyhTkvYZVqkgGh8HWA6aKGWGJmL9T0Hu+Z3+p6ZWT9HRq7YjZ3Pecp4QzKzACyhOelhwx1lm",
+	"pr2vEYFhyYhHA3/NlKm93UaQv4WkaEeLMgTMctXx/1S0iiHTmDBFe5QI0Nc9qpWNmfp/+cA1enwnkfUX",
+	"Om0/7BQKBHZGOCJIkhEWWg4heE2+QpwlkzBTkQgL4nuGzCdFx4Zv1qGSURcCLr2tkNq5T+qWJJz1NRB1",
+	"pHbO3fTAKzIiowSEnaCBIyC+Vr+4pm5pIczoXGwlYHgtrUzGQU1SDez2J5TdaXq2imT8CgHvijV/AbVP",
+	"IgxxK84qKXi+ykqGNg6qZ5OKjyQac3GnAV1Fcc13zCUYzVdN9SckvrmdzNL1gXsX412Bwee6tfQwc3T5",
+	"fAVafU2wVG6zau9M3fhNWKG1AqigtzrMyVcwV5c90RHuAxPgXg/60rhGOtfStgZPVcSHBf4hU2AUjWZj",
+	"2MM30QAnCWE2Ews8CjeRIMDYcSK9X+FtHusXEx7dkfiGg9/BSsCbmEqNJbFzuNwwrm7uidDyAdzpOdA5",
+	"CHMslfyT9/zfNEnw5rONNvr+t62tV+iEsvQT+vRi72Zv94e5DAl21e1GvWNe1BECvL7MN1d0v1VH9WC2",
+	"7HHBGyHa6e1uzEU3bbe393YqAnt1XC1zAtonvM9TNcPhMjPkfOEHmLWab5QrpHjfMO7clPJE9/oi0SFP",
+	"23vcp9EJZXffpBcsg/6DJrvJovZYKQnCZPNoWTbf4hyq0b+0xTmUnzp0TOO3ybjzJ6f//PW0jX99mf68",
+	"PdqJF7JEg8sj1d6DpTSH8FEeWhUozeh5dTo2HDHjhvufAyqG79SZIbhxZKwiidSASut0cIJWEpPBSsHl",
+	"WLTfl5DsVN7k9tI0xFokEGa4fvCFmdGDaWZnFLKbinM5JeOMwHQCl94FY9azHtULo5w1EdWBg0noUOau",
+	"NmyfFNChZhyi2UhH8cL4CLKPxnNAWDbHfDHz2cHSLAZR7Cb5mDGF3EW8KMjawr6ESVzhaeIelYMfwfyD",
+	"LGm8GNQMvaq4wsn84Kd9EUaVc4cNypMePnQa1nKuz+zzee7PnfAK9FlU8Pt86EwEk08R5CohrNCmzsba",
+	"HPbwJhzt5ItKgRzsgk/yY3iP58g7F88LU9XV2dU50q9onw9mKGXggBUk4vdETJBVevO1777cbu+8DFHw",
+	"ApsNYtZsMQjahXYEQArtxRmNowOb7DnH3479125SkYQcfPyexprnXJxo7NAWM4iZW8HH4Nko+jEEDTpG",
+	"FFakMuMQ8k1g0JGbjUQDTiS6xdGdnlU/i3CS6L/nOx6mVuUAqNquQzv0gthT2GWDPpnmlK1EkJgKPVf9",
+	"bbnUPy80VGkHLLpWL/oc96lRCaDuRFbz27pFP0Xeu3ydTgBUKe/IJIC8uJ8G45h5AikXaMhjkuhYX4L+",
+	"TQSXRk9iXKGYyijhsl70TGNHOrohCe3T2yoX5siAioZ4olOm5IRF2rVI1JhY3cx62GJyTyMig16mR3Qg",
+	"aJ/LTSpnD75EBtN7HL3m/A5d8TQaIFBNaljiVqmwxzi9xXMtdIsZB1ISoU/hMPNZTCMLjYPBVoHHnYon",
+	"HtOcmWNXAiJjthku+zs1Sm8TGrU0PtfaIwOffdUDqs52zGb7GYUc2UTC6ag+5L7qx+8uz06Dr2ShkuBT",
+	"jexvMYsNuczBqzIHK07eDMDsTz9rP5QiUuGvjiA5GF8fRQKwTCNJ/tKZGWApJJl7rlPTzALcR4JLkpjI",
+	"Z8jrBDNeEAnhqZ9JMQe0gIAe6xUV7+crqLDYQPe0FQt1v5zK6MnnboYWEJhpxk5BeqDeU5BPcubxFiFm",
+	"hfBHvk5cufd1sD/8sUagzPlcgCJ6xtv8zV+8HR/+tU3b79vkw5bYfnu3M3kRHb6Mng/30nZ0tTX6NXoR",
+	"DghFSRqTQ8+LHaxZuiMTmedtuBLMAZZNZHKDJhAl1OpCXgCK1NikvmbpfDV2IAflyEJh6gvLiX+j9PZn",
+	"83aumS03z7krXA9NI0Y1x7sgia6NO8dCwYeKDglPA4Ge9zRJqIsyg75OBBlyZtQhhe9IyVJth43VOjEe",
+	"C1sw1CNGeajHj2xMbWy+liC+VGJ8gWfNocHpI68QPDnmH/zSOXp9cP3T63H/3WFfvj/6Zff8+CAYG15F",
+	"dlhxQeN6K8iRaZqTJP2AqXt2eYxw0ueCqsHQyxLwkaD1POhvWcOyNEwz1nXh1T5V23wFbWExEZ9vXHXZ",
+	"0Qm+JQnkhjKXXOoMiITKYsQgoGGXE6/nyN0coJn74hH7XEwtOsBr2AmvcXRHWIwOzjsLmAgz4YWzqxRy",
+	"jyNcxKiz2E54PLMmA1yDQuHzPoDY53eLqBLXMkT1MZWjBE9OF/LuVya1DsBMaBbTqWId4LjFkuztGieO",
+	"V8Tz58Wv5N3B5embf+3eve60PzD6y3G/HhYGgjf1rVV/2cE9yypEprfsNqWJCvl3j0iUYJHXpx2cdyC5",
+	"zAGhf6dKIsFTpZOa8qqsmCRE+ciwoiOhstrJlJs0kfHdA3Rm7iwrqt7mz8uhCldvFGus3D7OdxAE6t/W",
+	"mG8kvyUfjV+HOaPScrlayKnqFchaGwnSo5+m0f0NFVKhaIAFjhQRWagcvmqCu5ckSZalNzLlEuFyw4WI",
+	"twBWturFMtlttd7sasMpF7r+BGVvNJcvRyxia0nnsjYf5HxKhYej2olVJboPw1+kwpUrIUPCwA31SAHO",
+	"KlXMTTvVaGm12kyoM55dyWHP0/n59WQb6EwnrRrjU0sBnhCJ+gIzReIuy8hyiJkuLtLWlQ3YUfUqY8eQ",
+	"OaMrkDAz9c4m3ZX0VJfZgEoCFZMm1XteWoDmARqsytPM6lDDO2seN1eqUi0HSXh0h/5KMVNUFWiqIo5b",
+	"DMeXdXGpnGxblHpmMJz5NbIXNsJ4yGMiq51xLhAJ2XcBX8Zl3rMD3tBMNOF9bVc4PDDFa55F21i6KLUE",
+	"TXhdkNi1nrywPB1JH5PJSeLC1c2sNRusuEwfxvAqjU9o0UwxU2iw5jzBMGt7kyaJ6WdhpWyWBbtYMe/O",
+	"QpmJrpB0A71PpTKVdkNClE1QEvcQBnPf264k+7lO0GVRgqUksokY10U7Q86y182PZk02O6tcElUz/3GV",
+	"xialnJiZSZK+v+HcZDhV03qw0vdQlz+hCU8FouyWf9IEblIyzI9lXFq+EvgCkuJ94/CbzIPUy5hfz1yN",
+	"wTrTzD39RjtYZeQl9F4E0hj/2v7Qvj16+enF8HpvNuO8W0/3qwubXVfTLj60ytHtxOMboBNNWcAb6Cqr",
+	"t9QKE8LJGE9klw14EktEIIsnb99g+MUj28zvMWUKUyZ97U5bATjh/ZTU5+HXjOo0GeF6gTa16COCmPSC",
+	"CbB0GW4QumTHCwA4f9Npny4VZ17jnFUaYCyTN7mY16DYxWOR1MQrrkZaU1s5y+x7LhAuppeZxBRTQUFZ",
+	"/4da6WahvJ8qwI+Z4ElSLXe4Gmn9MJwGZh+i64uOSdNnsSZFXTL1y4VLjwtUBUWCBNTt11iSnW1kHkMC",
+	"7xCzFCeIMFWyj9+9vvz1nztH58dvz3/eOf/tvPz33D2xMDQL6wvt0TWc/bngPVroWDOkzO/isdWs0bKt",
+	"rM0acMwRg7HmimIdU67V421+UvQrK4l07XhEXFq0X9Ns6tI0NiqOqFpIs1yo18tD5RavtSPQTH52QUYJ",
+	"jojMmNZ3ssDW4DxGpoR56UZgU6ZSxbLr9eqZfxpzEvKXOqxmTU0Ifc9HprFNE41pkmgJrEukSfzDejSb",
+	"cP59rXZAVTIveCLBmMYs55r+wnhrlvOwzSgY+06u2xQsZuPPrsuEzAf3qv6FiurK31lO6LkuokpH2KN5",
+	"+wqkkuWkGvCqC4VdE6AZxfwVepo5zJ4zuZcws9fQM8vLM9VfNhFn9ncuTVK//lnWKmGRCqtUVpQjkxjm",
+	"kUgqrJVrU0qdMkUT672EzhvT1cV5GbvdYPtt42MNLJrlxgNsSh7Hl1dtamYdYxepEn1sop9dC5+aoKuc",
+	"R/hzU+a+Ok3PXGGoM8fcJZUq2iqmGPNWDzoklRsWU4nc195kptXK2jnJgvKzxBoW76a3jmqwEAmZ4h1w",
+	"HK1YsXrvOa4CLo+vVblqjKFUUDW5jAZkaJZ0MKI/m+TIYJfbQCek73Xwc2Nj44cscpRVaWVNV6j+3HTE",
+	"z68F+a11cN5p/eynUGGYXW//a4IFEQ6OW/jrjSPEd79eTcmaYu+rUhlTE4qo0Gj2Aja67LUtIcq6LA8w",
+	"uPf7JHaRC/THby0NV+tKYCZHXKh9FHF+R8kfEPmShMVdlheIG2e9fQVRJhXB0OkDkEoibhkEZuin46sm",
+	"ent8cKQ9Sl12dn7VOTu91EjEECM2JhdJ0SuM+Z205UqUadgOLy/etAAF/zA+JcgJAxqHXcx3e6DUSO+1",
+	"vhyDRiR86IewE5D6wmJnHxvFl/o3VNCINI1110+F2a3L44sPncPjm8OTzvHp1WUBFCxpVIbkAVqE9Li7",
+	"wcC2d7IiqyHTkd7tkhSyyKQTVC7NC43pmyiOL696aQJZLHCERQap4aAqkA92T4TJm2lsbbQ32npgPiIM",
+	"j2hjv6E7XOyYLq8DIB2DbwDtpgl/blprVz8dcRnqBDYaJRPATKOqwNcueJp1GjCn7Uzk7DIDIxM3uuyq",
+	"+AsCESORJ0wNJmQN2zqxvfKFiiGwONML3qZ5E6le83hS4yKJeldABFjpQ5FLacFXvvRnu707z5Fv9gku",
+	"o9htt6vAyEbd9O4Rgk9ezv8ku8QH+GU6HGIxyfeucF6NZkPhvnT1fo2P+hMfKWzBaiUyuEG94ERZJ7Po",
+	"W45CBkVM8bS9Q1j4mL37QqzIW1lq1b4/5NGQJ9MFlsGeAjIYEKcDXjWQYdM0fKrGiUvNjUwTqMIha6eZ",
+	"iTA4ALWalwWFxlYp5KILYQk1IA4yr92XqSvOFG6LcyFeMR2CWxmTHFufsixqo0Z1XLAWhmwHouDlLTZM",
+	"lxYChiCKpbZhV0cds4Qw/VbhT95jsRptsoaMhlEU2zJq73ZZdssNBAFdwKEuC3QchJS0SRP64VhIXbtB",
+	"157NbRBU+Ta7THIokSZCGvUFAuejidP79SRa6EHJCxf+yAmVQTzM+1FeZXef1MG/T63xeNzSllwrFQlh",
+	"EY+NLVUP04INRWshWXsxonB2pLEEoUHj1vOdZ3svXu6127arovnhxfN227ZBbLRvX/T2ejuk1Y73cGs3",
+	"2iKtl71nz1s7+Hlv53av9yLa2nJmUWZWQWfAujaw3/Sv3Osv7+C3Ai2HG42Gr0Wb6jC6tAKwNf+T6Wuq",
+	"rNHU2P/9c1F1/v3jw0efuHNQncSeSdXOHSU3jZdqhtaoscQkPRs3Vs68dfA1GnCur0zSeqXzkhd1yS6r",
+	"vFTKCBUzKtFRbZe1hXuKiDEWsQwRZvnGrlUkQ+74r8xY+OJqSNWNZMvqIvlI1ie5DkXEAJlhRWbcVKAc",
+	"mMYzsCw3kpzzCioAVCoY0vdiwSFsIPDbuxZPxiSFTqZdZv0rTXRbMqudnTzLlgZLh8ou07OabAljKhMW",
+	"jziFHpFYuT6ZAIt/0w6AYExnSXSDYYneKjWCRFYHpFWmBcGxBnTatDYddXWKqmmU32W2LOqWx5MQGZzY",
+	"pinr14r8eNh0MlltPC70EHwMCeZ3DYLe56W8ykXzIWcRerCjuKvWnLGTZb+0kbY0ri8PPZev+bjgorWu",
+	"VSsUPeeoL4Ef6vMe/4a+gEiEM0W2fWUvhQI/q94ud27QtMnrGvTl2GywM1Vgydntjo556i7iBSU9lURW",
+	"+8Q3uuzYNZXSX0WlPkgYrC5Iggn0nNKUv5z+oD/amf9R4Q7b3e0aforytapFwWAwxPnTq8WBLQmssCls",
+	"I8xS60sEfanMbQNwUwBAYMUCLl7Y2GVUGpMKSzQmSdKEKrLxgCdZByo8pMnE3Dmg54uR4ryC1ZrKwaV5",
+	"7SqcaRGG63UjfXh4qKUenBiXM0/Xpaj6/vSynprtZBEzmo1PLZwkfNzKfQSO3bknNpW/Nexh8yhHp6Fu",
+	"+9nSRnQ1SlmPDFiCWk/wLvrM+3w6MS0HXChrhoKW2mVzfB9opuujyyyv2EDHOBpkL/WJ9p0qNORSoR0A",
+	"wVwNv/UMDSlLFZFGL2CckS4bD2hCEIWQnmlBjKDIRavLhSuIZeb0N7o5rI1pzabLco8tLnxjK2/wPaaQ",
+	"2WDyg94f/NQ5vDnpnP58c3x68Prk+AhpRUmSCt8NTJq1Yf26TpupXrbL+mpOcgQJOWnyS8uXsgx353+S",
+	"3cO9Hh5tf9aI4be4reLWOXnN9StfkJgQ7/4Q4zf25zFGn/6XKYywcQuZJwR2mQs3oIPIYquxKSvDzlLp",
+	"dCxw/BhRGkZODdvyuLkg0hVbMq5N//3aetx6laur+moUCmtRXWYQraYatQR9LqxEfQWKPsnL3WpTNXDZ",
+	"fihD+SeiCs5WmzUhFRcEbgLRScJT9PUTUTbX+9pof6tZd34+osbN3VZ7q9Xeumq39+F//2o0q2VEpeG1",
+	"nDFVasysk//KPk4/M6sK3Ky/cT3br76gm0ljhebJOuhtW0U/hrrXsMhhM4cfmsU0j6nnBeVQo52PcmtS",
+	"EyFuHgXyDa5tmtw0pkN5HWbxZlZihw4gMAZ/aPULri9z6eaC9gcK4TGe7FvtzJIKlqiAOhmXyht2dxnI",
+	"Qiq9vHQdICmnybt+GBKVU+5Dss4srUyOS2pi0zn+oRJDnxY3Yk7CyRNAVL9sAF3Vxu9gccIXlqizaczC",
+	"ZjMv4y8XJVhOQC2UjbCKgKprF1pSXIH6s7sCp4xDYrzPrQHVVDmplHonVAbFHnyNsLnzRpffkjGRCvWo",
+	"kCokBEG3emsn08zHNg+TsAuhTcxf2TzHfQLtxhoPzfkvE+G9/3FF7A9fsV6r/V7hVqBAUduQqBoEpnCo",
+	"XmGa2C6N2voF3BVaIpnjH2TnWa1PbfplJBV5N54GW8SyjJujYwhAuCiHlhgumRDcCLk3LHdcNY1DjDOC",
+	"KINmfrHr4WgDIZDvbPoiQGQB7rEvGVjgS8gAQZIoGFENBE/7JqRniml7CR8H07xgded5/csjixxGxjdz",
+	"I3i1pUwR+lWjbm6clTPHnrjjty4pWcz3K/zWwt17eFNxNZqf/3gMWv4MJ4I1noqd7AnUjRKXjrrRZRcQ",
+	"lZTF+lWpLxKl2rMniO1qyE0fGyDLiMzIitTlqSvRCo+9Ktna6F4u532MCF25c8vvjec///Km9X77t6PW",
+	"+e7bg9a/LvZOG83G4c6H162XV78et07ePbts/fLin9eNj7VXEm4jE/I2VJ69MwG/WnbHTNKxyZpQQW0Q",
+	"cmhqNqtIaMoIqkdD9ga7ahq60vF43uvVoCK/6hsSrswZdSEfagOdcoUAVhvQMX2exgMiyOzykiFmMdai",
+	"OERRR2YB3xJFBURHNZpmVww+YWGyCGbb8wIsCaLzfJw19FCNsj8RpjGE2MxWwEev9L6sicH15IZ14zsi",
+	"Een1SKRNfYix+NLBGPp5kXnB6+iLpBCimnYEGZ6uwmMLnQvcX/ubBgRbZPD/bbcPzjv7ZTfZ/856FP94",
+	"+fZgyyhWMe1TJX/cM39Bzov40Y7zvw7OO+b3ERGUxz/utM2fZkd/rNGvwLxZp7fBQnRX6u8QshrMofct",
+	"PsQrEMTCyfx16eFSYaEelc/PC+DMTFUo1zpZd1aYxwN5YVYI4Gs3Wj4orAHCkLqgxNrXQHwhkjHxlPc9",
+	"vD7O/vUyT7654NAXkzarx0IOuT5t4PieJpHdMVZhvnMaR5sZLJVOotfE4D06GxHWOUKHnDHIug3chpXw",
+	"sVGHzn8+PN5Al/bysC7Lbw9DU9d1vQrfelW4DazLvOo39wbcUebbMC5lHjIVTJ68NVLMEMYvoNkAeAa8",
+	"bXCXjYXIEFiUvjYszz98JDStvsotgLOFF13ygysTXSoq92yN4dBjIbioin+a6wHUJD/0iKdJjGyPL0Fw",
+	"NCBxCcmNpDBJLJDT0uJzsdsda03uH0JpqzE5SL+TqHPk5bFZ79IGKqxLZ6tQZvKrCl1MvERynBfj2yQA",
+	"zoyLVatlr1DK7pi2quEhJM5Yhc6NobNVuuysc3R4c3B9dXZzfnH2oXPZOTv1atLDRrjhFkWUXn9eQOim",
+	"vf8mBXyzSQFfKFDzH8WzMsFcm205b+Fmj4s+V3Wy+7KsvlIDyNlVjF1WN5VvVuniG4ByHf7v1ZPfirCs",
+	"mgF3ke3hujPgKhLTiqdXB0XMizOqW53EKBYqTRU4F/uFTtemruV8F6g7WqRP6UL1rOpJhTqmalVVdawg",
+	"P33XBXu+RlNKDzfWqdMgPAPVcAj/TZ3vKriCfiOc2fogrUu9ct3zQJdh2Q1q7mXzuQnRyS4Lp59voEPT",
+	"rsMVLM0pVjJFSsXmGwaTGYdaoanCJA2UzcNkMRoLqpRWlRS3Q8pwsiRswWKlr18r8b3Ubz2Y+f7l1Kgr",
+	"s/V25U/fWetRnUF7nyBm0p7p/z4j2g3mlyWzQk8qQNg8Jcqj9DIa2jkep84tkOm3jsq3cmf8Wox16z+/",
+	"+M3mV36z1W/Q2827CjM3lZLJuo2lWV3za4Cma9SGqVSuT36gwx26JT2ua2d10yktwihD3x+/P+ic3Hw4",
+	"vui86Rwe6KZQP4Ln7AcdsvOlCgQG4lVMqOVb4jj6yvjKDC5lQF446csgXbiNl5wuEKNSBe7bko2vl5IV",
+	"AKdOv96vmW0FZ1G54/ODg815YgjuvIptfxGN8bpLl75y0jThjgaUZXoYBALzEsIu+6PgYtxHZiXIdnT7",
+	"A0GnE0ClP7J2c38g04nO1pAZvc92UJO2vh20ymRiPoZfzU4GnVWwktDRriIfM0lyE+OJ1DW5GUv1L05z",
+	"96RN9Yeun2FVBf7aBeXK8MxR74qe5SeXMOIQPkhMiwXaDf1tfqbxg6GshCgTdy4patrKCWPmYhmwnbgy",
+	"n3W3qgGmTYNcQW1eyNG2yFmYjVnPWYzJrf4v24yKV3svJNnsNbsVQix/+NUElwHh2xBW+XateIxzCewI",
+	"fneb87hEZWfJLlh9gmRltsM6Cs2OLHMCc/rSfMiU5+LtcVpBlJKIrDUZaMN5tNXpybLLvHQJnf1gwXXR",
+	"Lu/+faokSXqQts24vS0lj2f0OCRLBL3OlFE5sGf2mCEsd9O3W7l/z/d/Mxi+YJhn7QWjVURURTGb3Lt0",
+	"PEg59lZy0KwZvqd9TTYbHsPb6BMFNqXXplwrnfGrYo4E7/WIkP4F8RJRU86QNQKRVJHKXIUpwngknAzf",
+	"yh5ATvsGcnu4HKMsZQJkl+fPSXPJznG+924G83NAuR7B2dUgdk2mgbMC74LrIWwh3EAH/mCy7KUwzNDi",
+	"5XnnFPqK3lI+JErQaC7/890mj8sG/Zke0X4Ja1K1FKg6CpN92XMdfVleuSjne7zcy8yptKpO4bZyHUzS",
+	"mJkmKpUnTlKJ+pxD4rzOkyHF6xg17VhPhKJDwlM1jzdOEc3jskh3VfcMHnlYYihPUQUtMl5R3MN6iEOH",
+	"1irMECVoyunTL7yKcBpThZTANCkWpKLvszpxuJ4GXDQ/BG29TnHyL1ip2pwiBHCAFeAx915Saa61dBc7",
+	"/JWa4Lh1T0FO0A2NG74Has4lKvVmh77BVDrvcmhyhUWfqEVn/4pVuoUD/6bLdBs5boeaTBSeTlvttIz4",
+	"jljhO6steU1yK+ky76xqr+8YE0EQIxQyibJ+gYyLvFB3WWL1wPl71JTnK/4bo6q7rIMWjn8OvgZ8SVN4",
+	"ixMaOx9xeQrbfHfI70khy8uguHZqjDGFpBdo766yUvPssgzMdBZg1i9a15pP4TrcjftDMPVEk0qhv/Nj",
+	"Orvyib60G3m96mzD29ZqTHOPgz7qimbOs9Bs7q0SLjNTm4D5x4VuiybrxHVbPIEmiBB2cwFqqfgIjbm4",
+	"0zgXxqPqmyQeB5Haj2zE+dyvDgfL37cRS01zf0NElnCJSQ1ELl2dG5Tv5/k7hvmZKmXN3uBO8H17raTm",
+	"fTqUHJlrLSDrnE00OySxdt3m3chz5IWhjMDvsnAspnBT91fMI7BQfI2IzJrLjxv5rocwq/A0mJrgHYhD",
+	"KvhIj1bV2z6OEfa+RT4+KG6Qpqmd1QPblFXAscaoR+BelC7rJbg/hTqW75Wv/qf5ffVUIayqu0+44Lc7",
+	"3sfxlpWneVquMg+5a3nL8lP84gkAX6DTVx3aCfJdP+EgR6cShVQ5QfJv5OZnbVvP1F3zwF9+FlDpiO+g",
+	"NbLuwmeynU2iNhBFRjtd5q3ihw1U5PAFLh5hZqtZDCjB6jEblfVXvaBtln2qryldKEibr3/1OO3jh6y+",
+	"ECYG0GMBTLR5TZUKwQVRgpJ7GB/3KYM0/0TLBt5D2ddBYZ4//MpuNgcm4mNGYuNmI/byrApP15AyUnBy",
+	"ZZdxV9zs+zX9Bnanv7DT4KungE0n5QVarxZemOp0h5PER2JHNNlPH+dnWEKgz7xfpXBkTx9R2zBzPC1V",
+	"w6FlLT3DvPzEsgxzDKq0zabeCKkJRSQJoJnPi+c6s3KOb76w9xhQy+HsVVJguDnFOINyiBnW2TX6kigz",
+	"vu6+Wy3nM6AfNfvKHv6Tl+qPiyz2XGciSnOumJYjEtEejdw4Wt7Z2/mnGrc+wvG2nxJXWd0Ufwz0WFpc",
+	"eSfaOarCj1GqKht/+60oajOPLitxD7hhzvR9rmAeWfvqNWDXUxKZ7acoMgsduP9+XNOi9lzxaiyhKjvn",
+	"ODeds9s8PTNd29lADhUOzaAFdAEzfj27QM//t3dhCnsIizkvARHAAQ4edBff69N7wjzPJUMkptCkxTb2",
+	"VLzLLJPMrM4qN+ahvb1Zjw31UIBgXTYqemgmaMAT6Gs1lCS5DxevG5YGB/6YPFNP8KRsDIPidVBav/lf",
+	"F2bQhSkM2tR0GcHjRdyWQEuMmzTXAa4miNcpTVTLuvVlly3gj7SYv5ieoT9a1AcJaPRf7+O097E+ElWY",
+	"MAdrlL4/EfU4GNF+MvzsS4voNWt9y8p08NpVIFuV8XOY9cPhCflOIu+paeI6SnBEwATyka4yhHINKgE3",
+	"DaH0I2jOl2X9e0PYxGTcZeZuy3Ls8rvijDnDs32FCkpCl+VagoEb8pXmqQvmOk0zdJflveOo0AYfgFFt",
+	"wq2NitavkeQAPikTbkGN5Bu5PumJSh/n1lhQhTFZMUuFvMynIWvv2j75O2SqXkOXmb9ZuGnZtFYXaHKo",
+	"U87NmhtiMn2kmO0TC6y9mPBVlSGIvL6iOqcQT7Lb+TyhgBJTGZkyZUY3IxNi87mlKU8sZctKuMjZu8TJ",
+	"6m+ZNevMZXOrMgxhQq4goKRNVvByGjIlr9rAXepev0UMXD3BkzJwr10/p7nUot90Bi6cEC0mSP6NjN7l",
+	"0ykhqdTv31aRSwn/rh+osyOFLFeL0o8ZXgPMeEqdDVY4ILulFUezQHRMfzIrNLbuc2k/GUbxpIJiqwhV",
+	"d4SFWJgnVWsGwswlOIX7Z0GSZTJVyyv9hJirPjyRxXvolqtBoemMu4RAcxA9jrG/GNedSo0Zt6ANtgZM",
+	"fCzra2Fx2X5a4vI/0fp6LM5rKaeWUPTKfGfcrdaBFjIYyQEXqpXQe624+Nd94ggIFOc9Y6DwSqajERcK",
+	"SYV7vS6ThCDyCUcqmaCxDtmAF0T/WlaLPagyN854wCUxHh6Ia3vvIC6c27HkZLHJgyjB0Z1xsGj92HZQ",
+	"hLmanjPHGzK77FQPYDriRVzEYdd2Xr36NHmAB99XamdXqO+d1R6n8KJGHPFfp0sNPuGTcVlTzh8hXMEV",
+	"ZvpfDKNIeHTHnaAOa9GmNXoP04TEpaupXRlmCrYPZs6UNfWX+SX1FQbytJWZECw0rZ1YsB5XMbezoEhP",
+	"++3r5rB7CNszSrItnCMr7L3PcjYOQL2m8RlYGWFqdot962Vq79wxKFHz2M3o+twvSVbt9JgH76Z5Um0c",
+	"11BOmyTuFm+Ij+BKbQEAEfduc8tm8z1J+GhImELmrUazYS57HCg12t/UXAMnAy7V/ov2i/YmHtHN+61A",
+	"+r9NkTIukKmB5P6m/nTDa+ENw3zMAP4cuHOrcIduPOKUQe6468+hN2saENA2TWKIveDQvm92pBLy4DdZ",
+	"YtP0Z5BylJkgU6Eh0GbygYyb/AH4dKk6tJTy51tOsUscnq6rwGzCGQSMCqmMNhVHf3pP81Ro6b9o8Crz",
+	"errfjfWUTs094SnYTmaIHk2Im+qC4Dh7jOxDW3jNXP9j2zC9CJ/INq9Y9+gHIPYb72FDKl+eFpz7jYNI",
+	"mXQhDm0yLFF4xm15g6wK6ZWew7OpNh4+STrnVTOwW/Ae/GCO09qeGed9ePh/AwA54kt3Bw8BAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -3,45 +3,230 @@
 
 package generated
 
+// BasePath is the path of the spec's first server, under which every route is mounted
+const BasePath = "/api/v1"
+
+// SchemeRequirement names a security scheme and the permissions the
+// credential presented for it must grant
+type SchemeRequirement struct {
+	Scheme string
+	Scopes []string
+}
+
+// SecurityRequirement is met when every scheme in it is satisfied.
+// An empty requirement lets anonymous callers through.
+type SecurityRequirement []SchemeRequirement
+
 // RouteSecurityInfo contains security information for a route
 type RouteSecurityInfo struct {
-	IsPublic       bool
-	RequiredScopes []string
+	IsPublic bool
+	// Security lists alternative requirements, any one of which grants access
+	Security []SecurityRequirement
+	// AllowUnverified lets users without a verified email through (x-allow-unverified)
+	AllowUnverified bool
+	// AllowWithoutMFA stays reachable before mandatory 2FA is set up (x-allow-without-mfa)
+	AllowWithoutMFA bool
+	// Sensitive routes refuse delegated credentials such as API keys (x-sensitive)
+	Sensitive bool
 }
 
 // RouteSecurity defines security requirements for each route
 // Automatically generated from OpenAPI security specifications
 //
 // Rules:
-//   No security field          = PUBLIC (IsPublic: true)
-//   security: - BearerAuth: [] = ANY authenticated user (IsPublic: false, RequiredScopes: [])
-//   security: - BearerAuth: [admin] = ADMIN only (IsPublic: false, RequiredScopes: [admin])
+//
+//	No security field             = top-level security, PUBLIC when there is none
+//	security: []                  = PUBLIC (IsPublic: true)
+//	- BearerAuth: []              = ANY authenticated user
+//	- BearerAuth: [users:read]    = roles granted users:read
+//	- BearerAuth: [...]
+//	- ApiKeyAuth: [...]           = either scheme (OR)
+//	- BearerAuth: []
+//	  ServiceAuth: []             = both schemes (AND)
+//	- {}                          = anonymous callers too
 var RouteSecurity = map[string]map[string]RouteSecurityInfo{
+	"/api/v1/auth/email/change/confirm": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/email/verify": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/email/verify/resend": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/introspect": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "ServiceAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/invitations/accept": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
 	"/api/v1/auth/login": {
-		"POST": {IsPublic: true, RequiredScopes: nil},
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/logout": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: true, AllowWithoutMFA: true, Sensitive: false},
+	},
+	"/api/v1/auth/magic-link": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/magic-link/verify": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
 	},
 	"/api/v1/auth/me": {
-		"GET": {IsPublic: false, RequiredScopes: []string{"user", "admin"}},
+		"GET":   {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"profile:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"profile:read"}}}}, AllowUnverified: true, AllowWithoutMFA: true, Sensitive: false},
+		"PATCH": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: true, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/me/login-history": {
+		"GET": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/me/password": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: true, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/mfa/totp/confirm": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: true, Sensitive: true},
+	},
+	"/api/v1/auth/mfa/totp/disable": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/mfa/totp/enroll": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: true, Sensitive: true},
+	},
+	"/api/v1/auth/mfa/verify": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/oidc/authorize": {
+		"GET": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/oidc/callback": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/password/forgot": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/password/reset": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/refresh": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
 	},
 	"/api/v1/auth/register": {
-		"POST": {IsPublic: true, RequiredScopes: nil},
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/tokens": {
+		"GET":  {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/tokens/{id}": {
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/webauthn/credentials": {
+		"GET": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/webauthn/credentials/{id}": {
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/webauthn/login": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/webauthn/login/options": {
+		"POST": {IsPublic: true, Security: nil, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/auth/webauthn/register": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/auth/webauthn/register/options": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/impersonations": {
+		"GET": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/invitations": {
+		"GET": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/invitations/{id}": {
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/invitations/{id}/resend": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/permissions": {
+		"GET":  {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"roles:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/permissions/{name}": {
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
 	},
 	"/api/v1/products": {
-		"GET": {IsPublic: false, RequiredScopes: []string{}},
-		"POST": {IsPublic: false, RequiredScopes: []string{}},
+		"GET":  {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"products:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"products:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"products:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"products:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
 	},
 	"/api/v1/products/{id}": {
-		"DELETE": {IsPublic: false, RequiredScopes: []string{}},
-		"GET": {IsPublic: false, RequiredScopes: []string{}},
-		"PUT": {IsPublic: false, RequiredScopes: []string{}},
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"products:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"products:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"GET":    {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"products:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"products:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"PUT":    {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"products:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"products:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/roles": {
+		"GET":  {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"roles:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/roles/{name}": {
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+		"GET":    {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"roles:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"PUT":    {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"roles:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
 	},
 	"/api/v1/users": {
-		"GET": {IsPublic: false, RequiredScopes: []string{"admin"}},
-		"POST": {IsPublic: false, RequiredScopes: []string{"admin"}},
+		"GET":  {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
 	},
 	"/api/v1/users/{id}": {
-		"DELETE": {IsPublic: false, RequiredScopes: []string{"admin"}},
-		"GET": {IsPublic: false, RequiredScopes: []string{"admin"}},
-		"PUT": {IsPublic: false, RequiredScopes: []string{"admin"}},
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"GET":    {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:read"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:read"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+		"PUT":    {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+	"/api/v1/users/{id}/impersonate": {
+		"POST": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:impersonate"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: true},
+	},
+	"/api/v1/users/{id}/lockout": {
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
 	},
+	"/api/v1/users/{id}/sessions": {
+		"DELETE": {IsPublic: false, Security: []SecurityRequirement{{{Scheme: "BearerAuth", Scopes: []string{"users:write"}}}, {{Scheme: "ApiKeyAuth", Scopes: []string{"users:write"}}}}, AllowUnverified: false, AllowWithoutMFA: false, Sensitive: false},
+	},
+}
+
+// SecuritySchemeInfo describes a scheme under components.securitySchemes
+type SecuritySchemeInfo struct {
+	// Type is http, apiKey, oauth2, openIdConnect or mutualTLS
+	Type string
+	// Scheme is the HTTP authentication scheme, such as bearer or basic
+	Scheme string
+	// In and Name locate an API key: header, query or cookie, and its name
+	In   string
+	Name string
+}
+
+// SecuritySchemes maps scheme names to their definitions
+var SecuritySchemes = map[string]SecuritySchemeInfo{
+	"ApiKeyAuth":  {Type: "apiKey", Scheme: "", In: "header", Name: "X-API-Key"},
+	"BearerAuth":  {Type: "http", Scheme: "bearer", In: "", Name: ""},
+	"ServiceAuth": {Type: "http", Scheme: "basic", In: "", Name: ""},
+}
+
+// PermissionInfo is a permission declared under x-permissions
+type PermissionInfo struct {
+	Name        string
+	Description string
+}
+
+// Permissions lists the permissions declared by the spec, sorted by name
+var Permissions = []PermissionInfo{
+	{Name: "products:manage", Description: "Update and delete products owned by anyone"},
+	{Name: "products:read", Description: "List and view products"},
+	{Name: "products:write", Description: "Create products, and update and delete your own"},
+	{Name: "profile:read", Description: "Read your own profile with an API key"},
+	{Name: "roles:read", Description: "List roles and permissions"},
+	{Name: "roles:write", Description: "Manage roles and permissions"},
+	{Name: "users:impersonate", Description: "Act as another user"},
+	{Name: "users:read", Description: "List and view users, invitations and impersonations"},
+	{Name: "users:write", Description: "Invite, update and delete users and manage their sessions"},
 }
@@ -2,23 +2,52 @@ package handlers
 
 import (
 	"backend/internal/generated"
+	"backend/internal/handlers/mapper"
 	"backend/internal/service"
+	"backend/internal/session"
+	"backend/pkg/oidc"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
-
-	openapi_types "github.com/oapi-codegen/runtime/types"
+	"gorm.io/gorm"
 )
 
 type AuthHandler struct {
 	service service.AuthService
+	cookies session.Options
 }
 
-func NewAuthHandler(service service.AuthService) *AuthHandler {
+func NewAuthHandler(service service.AuthService, cookies session.Options) *AuthHandler {
 	return &AuthHandler{
 		service: service,
+		cookies: cookies,
+	}
+}
+
+// respondWithTokens sends the tokens in the body, or to browser clients
+// that asked for cookies, in HttpOnly cookies with the body's copies blanked
+func (h *AuthHandler) respondWithTokens(c *gin.Context, status int, response *generated.AuthResponse) {
+	if !h.cookies.Requested(c) {
+		c.JSON(status, response)
+		return
+	}
+
+	ttl := time.Duration(response.ExpiresIn) * time.Second
+	if err := h.cookies.SetTokens(c, response.Token, ttl, response.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to start session",
+		})
+		return
 	}
+
+	body := *response
+	body.Token = ""
+	body.RefreshToken = ""
+	c.JSON(status, body)
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -33,13 +62,23 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	response, err := h.service.Register(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusAccepted, generated.RegistrationPendingResponse{
+				Message: "Check your inbox to verify your email address",
+			})
+			return
+		}
+		if body, ok := PasswordPolicyError(err); ok {
+			c.JSON(http.StatusBadRequest, body)
+			return
+		}
 		c.JSON(http.StatusBadRequest, generated.Error{
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, response)
+	h.respondWithTokens(c, http.StatusCreated, response)
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -52,19 +91,695 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.Login(c.Request.Context(), &req)
+	response, challenge, err := h.service.Login(c.Request.Context(), &req, GetClientInfo(c))
+	if err != nil {
+		var locked *service.LockedOutError
+		if errors.As(err, &locked) {
+			c.Header("Retry-After", retryAfterSeconds(locked.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
+
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req generated.RefreshRequest
+
+	// Cookie clients send no body; the refresh token comes from its cookie
+	if h.cookies.Requested(c) && c.Request.ContentLength <= 0 {
+		req.RefreshToken = session.Cookie(c, session.RefreshTokenCookie)
+		if req.RefreshToken == "" {
+			c.JSON(http.StatusUnauthorized, generated.Error{
+				Message: service.ErrInvalidRefreshToken.Error(),
+			})
+			return
+		}
+		if !session.ValidCSRF(c) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: "invalid CSRF token",
+			})
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) ||
+			errors.Is(err, service.ErrRefreshTokenReused) ||
+			errors.Is(err, service.ErrAccountInactive) {
+			c.JSON(http.StatusUnauthorized, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to refresh token",
+		})
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := GetClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing token claims",
+		})
+		return
+	}
+
+	// The body is optional
+	var req generated.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	// The middleware has checked the CSRF token if the cookie was used
+	if h.cookies.Enabled && req.RefreshToken == nil {
+		if refresh := session.Cookie(c, session.RefreshTokenCookie); refresh != "" {
+			req.RefreshToken = &refresh
+		}
+	}
+
+	if err := h.service.Logout(c.Request.Context(), claims, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to logout",
+		})
+		return
+	}
+
+	if h.cookies.Enabled && session.HasCookies(c) {
+		h.cookies.Clear(c)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req generated.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ForgotPassword(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to request password reset",
+		})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req generated.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), &req); err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if body, ok := PasswordPolicyError(err); ok {
+			c.JSON(http.StatusBadRequest, body)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to reset password",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req generated.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.VerifyEmail(c.Request.Context(), &req); err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to verify email",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) ResendVerificationEmail(c *gin.Context) {
+	var req generated.ResendVerificationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ResendVerification(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to send verification email",
+		})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+func (h *AuthHandler) VerifyMfa(c *gin.Context) {
+	var req generated.MfaVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.VerifyMFA(c.Request.Context(), &req, GetClientInfo(c))
+	if err != nil {
+		var locked *service.LockedOutError
+		if errors.As(err, &locked) {
+			c.Header("Retry-After", retryAfterSeconds(locked.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidMFAChallenge) || errors.Is(err, service.ErrInvalidMFACode) {
+			c.JSON(http.StatusUnauthorized, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to verify code",
+		})
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
+
+func (h *AuthHandler) StartOidcLogin(c *gin.Context) {
+	response, err := h.service.StartOIDCLogin(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCDisabled) {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, oidc.ErrDiscovery) {
+			c.JSON(http.StatusBadGateway, generated.Error{
+				Message: "Identity provider is unavailable",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to start single sign-on",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) CompleteOidcLogin(c *gin.Context) {
+	var req generated.OidcCallbackRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, challenge, err := h.service.CompleteOIDCLogin(c.Request.Context(), &req, GetClientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOIDCDisabled):
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrInvalidOIDCState), errors.Is(err, service.ErrOIDCLoginFailed):
+			c.JSON(http.StatusUnauthorized, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrOIDCEmailNotVerified), errors.Is(err, service.ErrOIDCNoAccount),
+			errors.Is(err, service.ErrAccountInactive):
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, oidc.ErrDiscovery):
+			c.JSON(http.StatusBadGateway, generated.Error{
+				Message: "Identity provider is unavailable",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, generated.Error{
+				Message: "Failed to complete single sign-on",
+			})
+		}
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
+
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req generated.MagicLinkRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.RequestMagicLink(c.Request.Context(), &req); err != nil {
+		var locked *service.LockedOutError
+		var limited *service.RateLimitedError
+		switch {
+		case errors.Is(err, service.ErrMagicLinkDisabled):
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.As(err, &locked):
+			c.Header("Retry-After", retryAfterSeconds(locked.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.As(err, &limited):
+			c.Header("Retry-After", retryAfterSeconds(limited.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, generated.Error{
+				Message: "Failed to request login link",
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+func (h *AuthHandler) RedeemMagicLink(c *gin.Context) {
+	var req generated.MagicLinkVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, challenge, err := h.service.RedeemMagicLink(c.Request.Context(), &req, GetClientInfo(c))
+	if err != nil {
+		var locked *service.LockedOutError
+		switch {
+		case errors.Is(err, service.ErrMagicLinkDisabled):
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrInvalidMagicLink):
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrAccountInactive):
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.As(err, &locked):
+			c.Header("Retry-After", retryAfterSeconds(locked.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, generated.Error{
+				Message: "Failed to log in with link",
+			})
+		}
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
+
+func (h *AuthHandler) StartPasskeyRegistration(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	options, err := h.service.StartPasskeyRegistration(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrWebAuthnDisabled) {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to start passkey registration",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+func (h *AuthHandler) FinishPasskeyRegistration(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	var req generated.PasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	credential, err := h.service.FinishPasskeyRegistration(c.Request.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWebAuthnDisabled):
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrInvalidPasskeyCeremony):
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrPasskeyRegistered):
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, generated.Error{
+				Message: "Failed to register passkey",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": mapper.ToGeneratedPasskey(credential),
+	})
+}
+
+func (h *AuthHandler) StartPasskeyLogin(c *gin.Context) {
+	options, err := h.service.StartPasskeyLogin(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrWebAuthnDisabled) {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to start passkey login",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+func (h *AuthHandler) FinishPasskeyLogin(c *gin.Context) {
+	var req generated.PasskeyAssertionCredential
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response, err := h.service.FinishPasskeyLogin(c.Request.Context(), &req, GetClientInfo(c))
+	if err != nil {
+		var locked *service.LockedOutError
+		switch {
+		case errors.Is(err, service.ErrWebAuthnDisabled):
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrInvalidPasskeyCeremony):
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrPasskeyLoginFailed):
+			c.JSON(http.StatusUnauthorized, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.Is(err, service.ErrAccountInactive), errors.Is(err, service.ErrEmailNotVerified):
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+		case errors.As(err, &locked):
+			c.Header("Retry-After", retryAfterSeconds(locked.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, generated.Error{
+				Message: "Failed to log in with passkey",
+			})
+		}
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
+
+func (h *AuthHandler) ListPasskeys(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	credentials, err := h.service.ListPasskeys(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch passkeys",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedPasskeys(credentials),
+	})
+}
+
+func (h *AuthHandler) DeletePasskey(c *gin.Context, id generated.IdParam) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	if err := h.service.DeletePasskey(c.Request.Context(), userID, id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Passkey not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to delete passkey",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) EnrollTotp(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	response, err := h.service.EnrollTOTP(c.Request.Context(), userID)
 	if err != nil {
+		if errors.Is(err, service.ErrMFAAlreadyEnabled) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to start enrollment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) ConfirmTotp(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	var req generated.TotpCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
 			Message: err.Error(),
 		})
 		return
 	}
 
+	response, err := h.service.ConfirmTOTP(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMFACode) ||
+			errors.Is(err, service.ErrMFAAlreadyEnabled) ||
+			errors.Is(err, service.ErrMFANotEnrolled) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to enable two-factor authentication",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *AuthHandler) DisableTotp(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	var req generated.TotpCodeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.DisableTOTP(c.Request.Context(), userID, &req); err != nil {
+		if errors.Is(err, service.ErrMFARequired) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidMFACode) || errors.Is(err, service.ErrMFANotEnrolled) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to disable two-factor authentication",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
-	userIDVal, ok := c.Get("user_id")
+	userID, ok := GetUserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, generated.Error{
 			Message: "missing user_id",
@@ -72,29 +787,198 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
-	emailVal, ok := c.Get("email")
+	user, err := h.service.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusUnauthorized, generated.Error{
+				Message: "user no longer exists",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch profile",
+		})
+		return
+	}
+
+	profile := mapper.ToGeneratedProfile(user)
+	if actorID, ok := GetActorID(c); ok {
+		profile.ImpersonatedBy = &actorID
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *AuthHandler) UpdateCurrentUser(c *gin.Context) {
+	userID, ok := GetUserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, generated.Error{
-			Message: "missing email",
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	var req generated.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	user, err := h.service.UpdateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		var locked *service.LockedOutError
+		if errors.As(err, &locked) {
+			c.Header("Retry-After", retryAfterSeconds(locked.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidName) || errors.Is(err, service.ErrCurrentPasswordRequired) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCurrentPassword) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to update profile",
 		})
 		return
 	}
 
-	roleVal, ok := c.Get("role")
+	c.JSON(http.StatusOK, mapper.ToGeneratedProfile(user))
+}
+
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, ok := GetUserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, generated.Error{
-			Message: "missing role",
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	var req generated.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), userID, &req); err != nil {
+		var locked *service.LockedOutError
+		if errors.As(err, &locked) {
+			c.Header("Retry-After", retryAfterSeconds(locked.RetryAfter))
+			c.JSON(http.StatusTooManyRequests, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCurrentPassword) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if policyErr, ok := PasswordPolicyError(err); ok {
+			c.JSON(http.StatusBadRequest, policyErr)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to change password",
 		})
 		return
 	}
 
-	userID := uuid.MustParse(userIDVal.(string))
-	userEmail := openapi_types.Email(emailVal.(string))
-	userRole := roleVal.(string)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	var req generated.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, generated.MeResponse{
-		UserId: &userID,
-		Email:  &userEmail,
-		Role:   &userRole,
+	if err := h.service.ConfirmEmailChange(c.Request.Context(), &req); err != nil {
+		if errors.Is(err, service.ErrInvalidEmailChangeToken) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to confirm email change",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) GetLoginHistory(c *gin.Context, params generated.GetLoginHistoryParams) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	page := 1
+	perPage := 10
+
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.PerPage != nil {
+		perPage = *params.PerPage
+	}
+
+	attempts, total, err := h.service.LoginHistory(c.Request.Context(), userID, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch login history",
+		})
+		return
+	}
+
+	totalInt := int(total)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedLoginAttempts(attempts),
+		"meta": generated.Meta{
+			Page:    &page,
+			PerPage: &perPage,
+			Total:   &totalInt,
+		},
 	})
 }
+
+// retryAfterSeconds rounds up so clients never retry too early
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"backend/internal/generated"
+	"backend/internal/service"
+	"backend/internal/session"
+	jwt "backend/pkg"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeAuthService records the refresh and logout calls it gets
+type fakeAuthService struct {
+	service.AuthService
+	refreshed []string
+	loggedOut []*generated.LogoutRequest
+}
+
+func (s *fakeAuthService) Refresh(ctx context.Context, req *generated.RefreshRequest) (*generated.AuthResponse, error) {
+	s.refreshed = append(s.refreshed, req.RefreshToken)
+	if req.RefreshToken != "refresh-1" {
+		return nil, service.ErrInvalidRefreshToken
+	}
+	return &generated.AuthResponse{
+		Token:        "access-2",
+		RefreshToken: "refresh-2",
+		ExpiresIn:    900,
+		User:         generated.UserData{Email: "jane@example.com"},
+	}, nil
+}
+
+func (s *fakeAuthService) Logout(ctx context.Context, claims *jwt.Claims, req *generated.LogoutRequest) error {
+	s.loggedOut = append(s.loggedOut, req)
+	return nil
+}
+
+var testCookies = session.Options{Enabled: true, SameSite: http.SameSiteLaxMode, RefreshTTL: time.Hour}
+
+func newAuthRouter(auth service.AuthService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewAuthHandler(auth, testCookies)
+	r := gin.New()
+	r.POST("/auth/refresh", h.RefreshToken)
+	r.POST("/auth/logout", func(c *gin.Context) {
+		c.Set("claims", &jwt.Claims{UserID: "user"})
+	}, h.Logout)
+	return r
+}
+
+func cookieRequest(path, body string, csrf string, cookies ...*http.Cookie) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set(session.TransportHeader, session.TransportCookie)
+	if csrf != "" {
+		req.Header.Set(session.CSRFHeader, csrf)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func responseCookies(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	return cookies
+}
+
+var (
+	refreshCookie = &http.Cookie{Name: session.RefreshTokenCookie, Value: "refresh-1"}
+	csrfCookie    = &http.Cookie{Name: session.CSRFCookie, Value: "csrf-1"}
+)
+
+func TestRefreshFromCookie(t *testing.T) {
+	auth := &fakeAuthService{}
+	r := newAuthRouter(auth)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, cookieRequest("/auth/refresh", "", "csrf-1", refreshCookie, csrfCookie))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var body generated.AuthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Token != "" || body.RefreshToken != "" || body.ExpiresIn != 900 {
+		t.Fatalf("tokens leaked into the body: %+v", body)
+	}
+	cookies := responseCookies(w)
+	if cookies[session.AccessTokenCookie].Value != "access-2" || cookies[session.RefreshTokenCookie].Value != "refresh-2" {
+		t.Fatalf("cookies %v", cookies)
+	}
+	// The CSRF token rotates with the session
+	if csrf := cookies[session.CSRFCookie]; csrf == nil || csrf.Value == "" || csrf.Value == "csrf-1" {
+		t.Fatalf("CSRF cookie %v", csrf)
+	}
+}
+
+func TestRefreshFromCookieRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		csrf    string
+		cookies []*http.Cookie
+		status  int
+	}{
+		{"no CSRF header", "", []*http.Cookie{refreshCookie, csrfCookie}, http.StatusForbidden},
+		{"wrong CSRF header", "csrf-2", []*http.Cookie{refreshCookie, csrfCookie}, http.StatusForbidden},
+		{"no CSRF cookie", "csrf-1", []*http.Cookie{refreshCookie}, http.StatusForbidden},
+		{"no refresh cookie", "csrf-1", []*http.Cookie{csrfCookie}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &fakeAuthService{}
+			w := httptest.NewRecorder()
+			newAuthRouter(auth).ServeHTTP(w, cookieRequest("/auth/refresh", "", tt.csrf, tt.cookies...))
+			if w.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if len(auth.refreshed) != 0 {
+				t.Fatal("the refresh token was used")
+			}
+			if len(responseCookies(w)) != 0 {
+				t.Fatal("cookies were set")
+			}
+		})
+	}
+}
+
+func TestRefreshWithBodyIgnoresCookie(t *testing.T) {
+	auth := &fakeAuthService{}
+	w := httptest.NewRecorder()
+	// A body means the client holds the token itself, so no CSRF check
+	newAuthRouter(auth).ServeHTTP(w, cookieRequest("/auth/refresh", `{"refresh_token":"refresh-1"}`, "", &http.Cookie{Name: session.RefreshTokenCookie, Value: "other"}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if len(auth.refreshed) != 1 || auth.refreshed[0] != "refresh-1" {
+		t.Fatalf("refreshed %v", auth.refreshed)
+	}
+}
+
+func TestLogoutClearsCookies(t *testing.T) {
+	auth := &fakeAuthService{}
+	w := httptest.NewRecorder()
+	newAuthRouter(auth).ServeHTTP(w, cookieRequest("/auth/logout", "", "csrf-1",
+		&http.Cookie{Name: session.AccessTokenCookie, Value: "access-1"}, refreshCookie, csrfCookie))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+
+	// The refresh token from the cookie is revoked with the session
+	if len(auth.loggedOut) != 1 || auth.loggedOut[0].RefreshToken == nil || *auth.loggedOut[0].RefreshToken != "refresh-1" {
+		t.Fatalf("logged out with %+v", auth.loggedOut)
+	}
+	cookies := responseCookies(w)
+	for _, name := range []string{session.AccessTokenCookie, session.RefreshTokenCookie, session.CSRFCookie} {
+		cookie, ok := cookies[name]
+		if !ok || cookie.Value != "" || cookie.MaxAge >= 0 {
+			t.Fatalf("%s not cleared: %v", name, cookie)
+		}
+	}
+}
@@ -1,26 +1,48 @@
 package handlers
 
 import (
+	"backend/internal/generated"
 	"backend/internal/service"
+	"backend/internal/session"
+	jwt "backend/pkg"
+	"backend/pkg/password"
+	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type CombinedHandler struct {
 	*UserHandler
 	*ProductHandler
 	*AuthHandler
+	*TokenHandler
+	*InvitationHandler
+	*ImpersonationHandler
+	*IntrospectionHandler
+	*RoleHandler
 }
 
 func NewCombinedHandler(
 	userService service.UserService,
 	productService service.ProductService,
 	authService service.AuthService,
+	tokenService service.PersonalAccessTokenService,
+	invitationService service.InvitationService,
+	impersonationService service.ImpersonationService,
+	roleService service.RoleService,
+	tokens service.TokenService,
+	cookies session.Options,
 ) *CombinedHandler {
 	return &CombinedHandler{
-		UserHandler:    NewUserHandler(userService),
-		ProductHandler: NewProductHandler(productService),
-		AuthHandler:    NewAuthHandler(authService),
+		UserHandler:          NewUserHandler(userService, invitationService),
+		ProductHandler:       NewProductHandler(productService),
+		AuthHandler:          NewAuthHandler(authService, cookies),
+		TokenHandler:         NewTokenHandler(tokenService),
+		InvitationHandler:    NewInvitationHandler(invitationService),
+		ImpersonationHandler: NewImpersonationHandler(impersonationService),
+		IntrospectionHandler: NewIntrospectionHandler(tokens),
+		RoleHandler:          NewRoleHandler(roleService),
 	}
 }
 
@@ -38,6 +60,62 @@ func GetUserContext(c *gin.Context) (userID, email, role string) {
 	return
 }
 
+// Helper method to get the validated token claims from middleware
+func GetClaims(c *gin.Context) (*jwt.Claims, bool) {
+	if v, exists := c.Get("claims"); exists {
+		claims, ok := v.(*jwt.Claims)
+		return claims, ok
+	}
+	return nil, false
+}
+
+// Helper method to get the authenticated user's ID
+func GetUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, _, _ := GetUserContext(c)
+	id, err := uuid.Parse(userID)
+	return id, err == nil
+}
+
+// Helper method to get the principal authenticated by middleware
+func GetPrincipal(c *gin.Context) *service.Principal {
+	if v, exists := c.Get("principal"); exists {
+		principal, _ := v.(*service.Principal)
+		return principal
+	}
+	return nil
+}
+
+// Helper method to get the admin behind an impersonated session
+func GetActorID(c *gin.Context) (uuid.UUID, bool) {
+	actorID := c.GetString("actor_id")
+	if actorID == "" {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(actorID)
+	return id, err == nil
+}
+
+// Helper method to describe the client for login history
+func GetClientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// Helper method to describe a rejected password with every broken rule
+func PasswordPolicyError(err error) (generated.Error, bool) {
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return generated.Error{}, false
+	}
+	details := map[string][]string{"password": policyErr.Violations}
+	return generated.Error{
+		Message: err.Error(),
+		Errors:  &details,
+	}, true
+}
+
 // Helper method to check if user is authenticated
 func IsAuthenticated(c *gin.Context) bool {
 	_, exists := c.Get("user_id")
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"backend/internal/generated"
+	"backend/internal/handlers/mapper"
+	"backend/internal/repository"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ImpersonationHandler struct {
+	service service.ImpersonationService
+}
+
+func NewImpersonationHandler(service service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{service: service}
+}
+
+func (h *ImpersonationHandler) ImpersonateUser(c *gin.Context, id generated.IdParam) {
+	actorID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	var req generated.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	impersonation, token, err := h.service.Start(c.Request.Context(), actorID, id, req.Reason, GetClientInfo(c))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "User not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrImpersonationReasonRequired) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrCannotImpersonateSelf) || errors.Is(err, service.ErrCannotImpersonateAdmin) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrImpersonationTargetInactive) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to impersonate user",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, generated.ImpersonationResponse{
+		Token:         token,
+		ExpiresIn:     int(time.Until(impersonation.ExpiresAt).Round(time.Second).Seconds()),
+		Impersonation: mapper.ToGeneratedImpersonation(impersonation),
+	})
+}
+
+func (h *ImpersonationHandler) ListImpersonations(c *gin.Context, params generated.ListImpersonationsParams) {
+	page := 1
+	perPage := 10
+
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.PerPage != nil {
+		perPage = *params.PerPage
+	}
+
+	var filter repository.ImpersonationFilter
+	if params.ActorId != nil {
+		filter.ActorID = *params.ActorId
+	}
+	if params.TargetId != nil {
+		filter.TargetID = *params.TargetId
+	}
+
+	impersonations, total, err := h.service.List(c.Request.Context(), filter, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch impersonations",
+		})
+		return
+	}
+
+	totalInt := int(total)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedImpersonations(impersonations),
+		"meta": generated.Meta{
+			Page:    &page,
+			PerPage: &perPage,
+			Total:   &totalInt,
+		},
+	})
+}
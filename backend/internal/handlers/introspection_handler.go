@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"backend/internal/generated"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+type IntrospectionHandler struct {
+	tokens service.TokenService
+}
+
+func NewIntrospectionHandler(tokens service.TokenService) *IntrospectionHandler {
+	return &IntrospectionHandler{tokens: tokens}
+}
+
+// IntrospectToken answers with active=false and nothing else for any
+// token that would be refused, so callers learn no more than that
+func (h *IntrospectionHandler) IntrospectToken(c *gin.Context) {
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: "token is required",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+
+	principal, err := h.tokens.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) ||
+			errors.Is(err, service.ErrTokenRevoked) ||
+			errors.Is(err, service.ErrAccountInactive) {
+			c.JSON(http.StatusOK, generated.IntrospectionResponse{Active: false})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "failed to introspect token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toIntrospectionResponse(principal))
+}
+
+func toIntrospectionResponse(principal *service.Principal) generated.IntrospectionResponse {
+	sub, _ := uuid.Parse(principal.UserID)
+	username := openapi_types.Email(principal.Email)
+	exp := principal.ExpiresAt.Unix()
+
+	response := generated.IntrospectionResponse{
+		Active:   true,
+		Sub:      &sub,
+		Username: &username,
+		Role:     &principal.Role,
+		Exp:      &exp,
+	}
+
+	if principal.IsPersonalAccessToken() {
+		tokenType := generated.IntrospectionResponseTokenTypePersonalAccessToken
+		scope := strings.Join(principal.Scopes, " ")
+		response.TokenType = &tokenType
+		response.Scope = &scope
+		return response
+	}
+
+	tokenType := generated.IntrospectionResponseTokenTypeAccessToken
+	iat := principal.Claims.IssuedAt.Unix()
+	response.TokenType = &tokenType
+	response.Iat = &iat
+	response.Jti = &principal.Claims.ID
+
+	if principal.IsImpersonated() {
+		actor, _ := uuid.Parse(principal.ActorID)
+		response.Act = &struct {
+			Sub openapi_types.UUID `json:"sub"`
+		}{Sub: actor}
+	}
+
+	return response
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"backend/internal/generated"
+	"backend/internal/handlers/mapper"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type InvitationHandler struct {
+	service service.InvitationService
+}
+
+func NewInvitationHandler(service service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: service}
+}
+
+func (h *InvitationHandler) ListInvitations(c *gin.Context, params generated.ListInvitationsParams) {
+	page := 1
+	perPage := 10
+
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.PerPage != nil {
+		perPage = *params.PerPage
+	}
+
+	invitations, total, err := h.service.ListPending(c.Request.Context(), page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch invitations",
+		})
+		return
+	}
+
+	totalInt := int(total)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedInvitations(invitations),
+		"meta": generated.Meta{
+			Page:    &page,
+			PerPage: &perPage,
+			Total:   &totalInt,
+		},
+	})
+}
+
+func (h *InvitationHandler) ResendInvitation(c *gin.Context, id generated.IdParam) {
+	invitation, err := h.service.Resend(c.Request.Context(), id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Invitation not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvitationAccepted) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to resend invitation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedInvitation(invitation),
+	})
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context, id generated.IdParam) {
+	if err := h.service.Revoke(c.Request.Context(), id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Invitation not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvitationAccepted) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to revoke invitation",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req generated.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Accept(c.Request.Context(), &req); err != nil {
+		if errors.Is(err, service.ErrInvalidInvitation) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if body, ok := PasswordPolicyError(err); ok {
+			c.JSON(http.StatusBadRequest, body)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to accept invitation",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
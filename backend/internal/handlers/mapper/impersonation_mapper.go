@@ -0,0 +1,27 @@
+package mapper
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+)
+
+func ToGeneratedImpersonation(impersonation *models.Impersonation) generated.Impersonation {
+	return generated.Impersonation{
+		Id:        impersonation.ID,
+		ActorId:   impersonation.ActorID,
+		TargetId:  impersonation.TargetID,
+		Reason:    impersonation.Reason,
+		IpAddress: &impersonation.IPAddress,
+		UserAgent: &impersonation.UserAgent,
+		ExpiresAt: impersonation.ExpiresAt,
+		CreatedAt: impersonation.CreatedAt,
+	}
+}
+
+func ToGeneratedImpersonations(impersonations []models.Impersonation) []generated.Impersonation {
+	result := make([]generated.Impersonation, len(impersonations))
+	for i := range impersonations {
+		result[i] = ToGeneratedImpersonation(&impersonations[i])
+	}
+	return result
+}
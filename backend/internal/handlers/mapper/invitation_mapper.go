@@ -0,0 +1,26 @@
+package mapper
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+)
+
+func ToGeneratedInvitation(invitation *models.Invitation) generated.Invitation {
+	return generated.Invitation{
+		Id:        invitation.ID,
+		User:      ToGeneratedUser(&invitation.User),
+		InvitedBy: invitation.InvitedBy,
+		ExpiresAt: invitation.ExpiresAt,
+		Expired:   invitation.IsExpired(),
+		SentAt:    invitation.SentAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
+
+func ToGeneratedInvitations(invitations []models.Invitation) []generated.Invitation {
+	result := make([]generated.Invitation, len(invitations))
+	for i := range invitations {
+		result[i] = ToGeneratedInvitation(&invitations[i])
+	}
+	return result
+}
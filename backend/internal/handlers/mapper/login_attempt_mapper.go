@@ -0,0 +1,24 @@
+package mapper
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+)
+
+func ToGeneratedLoginAttempt(attempt *models.LoginAttempt) generated.LoginAttempt {
+	return generated.LoginAttempt{
+		Id:        attempt.ID,
+		IpAddress: &attempt.IPAddress,
+		UserAgent: &attempt.UserAgent,
+		Outcome:   generated.LoginAttemptOutcome(attempt.Outcome),
+		CreatedAt: attempt.CreatedAt,
+	}
+}
+
+func ToGeneratedLoginAttempts(attempts []models.LoginAttempt) []generated.LoginAttempt {
+	result := make([]generated.LoginAttempt, len(attempts))
+	for i := range attempts {
+		result[i] = ToGeneratedLoginAttempt(&attempts[i])
+	}
+	return result
+}
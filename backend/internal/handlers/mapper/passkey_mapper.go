@@ -0,0 +1,25 @@
+package mapper
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+)
+
+func ToGeneratedPasskey(credential *models.WebAuthnCredential) generated.Passkey {
+	return generated.Passkey{
+		Id:             credential.ID,
+		Name:           credential.Name,
+		Aaguid:         credential.AAGUID,
+		BackupEligible: credential.BackupEligible,
+		LastUsedAt:     credential.LastUsedAt,
+		CreatedAt:      credential.CreatedAt,
+	}
+}
+
+func ToGeneratedPasskeys(credentials []models.WebAuthnCredential) []generated.Passkey {
+	result := make([]generated.Passkey, len(credentials))
+	for i := range credentials {
+		result[i] = ToGeneratedPasskey(&credentials[i])
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package mapper
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+)
+
+func ToGeneratedPersonalAccessToken(token *models.PersonalAccessToken) generated.PersonalAccessToken {
+	return generated.PersonalAccessToken{
+		Id:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.ScopeList(),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
+
+func ToGeneratedPersonalAccessTokens(tokens []models.PersonalAccessToken) []generated.PersonalAccessToken {
+	result := make([]generated.PersonalAccessToken, len(tokens))
+	for i := range tokens {
+		result[i] = ToGeneratedPersonalAccessToken(&tokens[i])
+	}
+	return result
+}
@@ -13,6 +13,7 @@ func ToGeneratedProduct(product *models.Product) generated.Product {
 		Price:       product.Price,
 		Stock:       product.Stock,
 		Category:    product.Category,
+		OwnerId:     product.OwnerID,
 		CreatedAt:   &product.CreatedAt,
 		UpdatedAt:   &product.UpdatedAt,
 	}
@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+)
+
+func ToGeneratedRole(role *models.Role) generated.Role {
+	return generated.Role{
+		Name:        role.Name,
+		Description: role.Description,
+		Builtin:     role.Builtin,
+		Permissions: role.PermissionNames(),
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func ToGeneratedRoles(roles []models.Role) []generated.Role {
+	result := make([]generated.Role, len(roles))
+	for i := range roles {
+		result[i] = ToGeneratedRole(&roles[i])
+	}
+	return result
+}
+
+func ToGeneratedPermission(permission *models.Permission) generated.Permission {
+	return generated.Permission{
+		Name:        permission.Name,
+		Description: permission.Description,
+		Builtin:     permission.Builtin,
+		CreatedAt:   permission.CreatedAt,
+	}
+}
+
+func ToGeneratedPermissions(permissions []models.Permission) []generated.Permission {
+	result := make([]generated.Permission, len(permissions))
+	for i := range permissions {
+		result[i] = ToGeneratedPermission(&permissions[i])
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package mapper
+
+import (
+	"backend/internal/models"
+	"backend/pkg/scim"
+)
+
+// ToSCIMUser renders a user as a core User resource. baseURL is the
+// /scim/v2 root used for meta.location.
+func ToSCIMUser(user *models.User, baseURL string) scim.User {
+	active := user.IsActive
+
+	return scim.User{
+		Schemas:     []string{scim.UserSchema},
+		ID:          user.ID.String(),
+		ExternalID:  user.ExternalID,
+		UserName:    user.Email,
+		Name:        &scim.Name{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails: []scim.Email{
+			{Value: user.Email, Type: "work", Primary: true},
+		},
+		Active: &active,
+		Meta: &scim.Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     baseURL + "/Users/" + user.ID.String(),
+		},
+	}
+}
+
+func ToSCIMUsers(users []models.User, baseURL string) []scim.User {
+	result := make([]scim.User, len(users))
+	for i := range users {
+		result[i] = ToSCIMUser(&users[i], baseURL)
+	}
+	return result
+}
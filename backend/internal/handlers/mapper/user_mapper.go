@@ -8,16 +8,19 @@ import (
 )
 
 func ToGeneratedUser(user *models.User) generated.User {
-	role := generated.UserRole(user.Role)
+	role := user.Role
+	status := generated.UserStatus(user.Status)
 
 	return generated.User{
-		Id:        user.ID,
-		Name:      user.Name,
-		Email:     types_generated.Email(user.Email),
-		Role:      &role,
-		IsActive:  &user.IsActive,
-		CreatedAt: &user.CreatedAt,
-		UpdatedAt: &user.UpdatedAt,
+		Id:              user.ID,
+		Name:            user.Name,
+		Email:           types_generated.Email(user.Email),
+		Role:            &role,
+		IsActive:        &user.IsActive,
+		Status:          &status,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       &user.CreatedAt,
+		UpdatedAt:       &user.UpdatedAt,
 	}
 }
 
@@ -28,3 +31,24 @@ func ToGeneratedUsers(users []models.User) []generated.User {
 	}
 	return result
 }
+
+func ToGeneratedProfile(user *models.User) generated.MeResponse {
+	var pendingEmail *types_generated.Email
+	if user.PendingEmail != "" {
+		email := types_generated.Email(user.PendingEmail)
+		pendingEmail = &email
+	}
+
+	return generated.MeResponse{
+		UserId:        user.ID,
+		Name:          user.Name,
+		Email:         types_generated.Email(user.Email),
+		Role:          user.Role,
+		IsActive:      user.IsActive,
+		EmailVerified: user.IsEmailVerified(),
+		MfaEnabled:    user.IsMFAEnabled(),
+		PendingEmail:  pendingEmail,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
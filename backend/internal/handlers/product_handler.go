@@ -5,9 +5,11 @@ import (
 	"backend/internal/handlers/mapper"
 	"backend/internal/models"
 	"backend/internal/service"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -30,7 +32,19 @@ func (h *ProductHandler) ListProducts(c *gin.Context, params generated.ListProdu
 		perPage = *params.PerPage
 	}
 
-	products, total, err := h.service.ListProducts(c.Request.Context(), page, perPage)
+	var ownerID *uuid.UUID
+	if params.Mine != nil && *params.Mine {
+		userID, ok := GetUserID(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, generated.Error{
+				Message: "Unauthorized",
+			})
+			return
+		}
+		ownerID = &userID
+	}
+
+	products, total, err := h.service.ListProducts(c.Request.Context(), page, perPage, ownerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, generated.Error{
 			Message: "Failed to fetch products",
@@ -67,6 +81,9 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		Stock:       req.Stock,
 		Category:    req.Category,
 	}
+	if userID, ok := GetUserID(c); ok {
+		product.OwnerID = &userID
+	}
 
 	if err := h.service.CreateProduct(c.Request.Context(), product); err != nil {
 		c.JSON(http.StatusInternalServerError, generated.Error{
@@ -118,13 +135,19 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context, id generated.IdParam) {
 		Category:    req.Category,
 	}
 
-	if err := h.service.UpdateProduct(c.Request.Context(), id, product); err != nil {
+	if err := h.service.UpdateProduct(c.Request.Context(), GetPrincipal(c), id, product); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, generated.Error{
 				Message: "Product not found",
 			})
 			return
 		}
+		if errors.Is(err, service.ErrProductForbidden) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, generated.Error{
 			Message: "Failed to update product",
 		})
@@ -137,13 +160,19 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context, id generated.IdParam) {
 }
 
 func (h *ProductHandler) DeleteProduct(c *gin.Context, id generated.IdParam) {
-	if err := h.service.DeleteProduct(c.Request.Context(), id); err != nil {
+	if err := h.service.DeleteProduct(c.Request.Context(), GetPrincipal(c), id); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, generated.Error{
 				Message: "Product not found",
 			})
 			return
 		}
+		if errors.Is(err, service.ErrProductForbidden) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, generated.Error{
 			Message: "Failed to delete product",
 		})
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/internal/service"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fakeProductService refuses every change and records list filters
+type fakeProductService struct {
+	service.ProductService
+	listedOwners []*uuid.UUID
+}
+
+func (s *fakeProductService) ListProducts(ctx context.Context, page, perPage int, ownerID *uuid.UUID) ([]models.Product, int64, error) {
+	s.listedOwners = append(s.listedOwners, ownerID)
+	return nil, 0, nil
+}
+
+func (s *fakeProductService) UpdateProduct(ctx context.Context, principal *service.Principal, id generated.IdParam, product *models.Product) error {
+	return service.ErrProductForbidden
+}
+
+func (s *fakeProductService) DeleteProduct(ctx context.Context, principal *service.Principal, id generated.IdParam) error {
+	return service.ErrProductForbidden
+}
+
+func newProductRouter(products service.ProductService, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewProductHandler(products)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+			c.Set("principal", &service.Principal{UserID: userID, Role: models.RoleUser})
+		}
+	})
+	r.GET("/products", func(c *gin.Context) {
+		mine := c.Query("mine") == "true"
+		h.ListProducts(c, generated.ListProductsParams{Mine: &mine})
+	})
+	r.PUT("/products/:id", func(c *gin.Context) {
+		h.UpdateProduct(c, uuid.MustParse(c.Param("id")))
+	})
+	r.DELETE("/products/:id", func(c *gin.Context) {
+		h.DeleteProduct(c, uuid.MustParse(c.Param("id")))
+	})
+	return r
+}
+
+func TestListMyProducts(t *testing.T) {
+	userID := uuid.New()
+	products := &fakeProductService{}
+	r := newProductRouter(products, userID.String())
+
+	for _, query := range []string{"?mine=true", "", "?mine=false"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", query, w.Code)
+		}
+	}
+	if len(products.listedOwners) != 3 || products.listedOwners[0] == nil || *products.listedOwners[0] != userID {
+		t.Fatalf("mine=true listed owner %v, want %s", products.listedOwners[0], userID)
+	}
+	if products.listedOwners[1] != nil || products.listedOwners[2] != nil {
+		t.Fatal("a list without mine=true was filtered")
+	}
+
+	// mine needs a signed-in user
+	w := httptest.NewRecorder()
+	newProductRouter(products, "").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products?mine=true", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous mine=true: status %d, want 401", w.Code)
+	}
+}
+
+func TestProductChangesRefusedWithForbidden(t *testing.T) {
+	r := newProductRouter(&fakeProductService{}, uuid.NewString())
+	path := "/products/" + uuid.NewString()
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"name":"Lamp","price":10,"stock":1}`)),
+		httptest.NewRequest(http.MethodDelete, path, nil),
+	} {
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), service.ErrProductForbidden.Error()) {
+			t.Fatalf("%s: status %d %s, want 403", req.Method, w.Code, w.Body)
+		}
+	}
+}
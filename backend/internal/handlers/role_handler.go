@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"backend/internal/generated"
+	"backend/internal/handlers/mapper"
+	"backend/internal/models"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RoleHandler struct {
+	service service.RoleService
+}
+
+func NewRoleHandler(service service.RoleService) *RoleHandler {
+	return &RoleHandler{service: service}
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.service.ListRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch roles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedRoles(roles),
+	})
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req generated.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	role := &models.Role{Name: req.Name}
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+	var permissions []string
+	if req.Permissions != nil {
+		permissions = *req.Permissions
+	}
+
+	if err := h.service.CreateRole(c.Request.Context(), GetPrincipal(c), role, permissions); err != nil {
+		if errors.Is(err, service.ErrInvalidRoleName) || errors.Is(err, service.ErrUnknownPermission) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPermissionNotGrantable) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrRoleExists) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to create role",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": mapper.ToGeneratedRole(role),
+	})
+}
+
+func (h *RoleHandler) GetRole(c *gin.Context, name generated.RoleNameParam) {
+	role, err := h.service.GetRole(c.Request.Context(), name)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Role not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch role",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedRole(role),
+	})
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context, name generated.RoleNameParam) {
+	var req generated.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	// nil leaves the permissions alone, an empty list removes them all
+	var permissions []string
+	if req.Permissions != nil {
+		permissions = append([]string{}, *req.Permissions...)
+	}
+
+	role, err := h.service.UpdateRole(c.Request.Context(), GetPrincipal(c), name, req.Description, permissions)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Role not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrUnknownPermission) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPermissionNotGrantable) || errors.Is(err, service.ErrCannotChangeOwnRole) {
+			c.JSON(http.StatusForbidden, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrAdminRolePermissions) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to update role",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedRole(role),
+	})
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context, name generated.RoleNameParam) {
+	if err := h.service.DeleteRole(c.Request.Context(), name); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Role not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrBuiltinRole) || errors.Is(err, service.ErrRoleInUse) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to delete role",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.service.ListPermissions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch permissions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedPermissions(permissions),
+	})
+}
+
+func (h *RoleHandler) CreatePermission(c *gin.Context) {
+	var req generated.CreatePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	permission := &models.Permission{Name: req.Name}
+	if req.Description != nil {
+		permission.Description = *req.Description
+	}
+
+	if err := h.service.CreatePermission(c.Request.Context(), permission); err != nil {
+		if errors.Is(err, service.ErrInvalidPermissionName) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrPermissionExists) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to create permission",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": mapper.ToGeneratedPermission(permission),
+	})
+}
+
+func (h *RoleHandler) DeletePermission(c *gin.Context, name generated.PermissionNameParam) {
+	if err := h.service.DeletePermission(c.Request.Context(), name); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Permission not found",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrBuiltinPermission) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to delete permission",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"backend/internal/handlers/mapper"
+	"backend/internal/service"
+	"backend/pkg/scim"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	scimDefaultCount = 100
+	scimMaxCount     = 200
+)
+
+// SCIMHandler serves /scim/v2 for identity providers. It sits outside the
+// OpenAPI contract since SCIM defines its own schemas and error format.
+type SCIMHandler struct {
+	service service.SCIMService
+}
+
+func NewSCIMHandler(service service.SCIMService) *SCIMHandler {
+	return &SCIMHandler{service: service}
+}
+
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	startIndex, err := scimIntParam(c, "startIndex", 1)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	count, err := scimIntParam(c, "count", scimDefaultCount)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	// Out of range values are clamped as RFC 7644 section 3.4.2.4 asks
+	startIndex = max(startIndex, 1)
+	count = min(max(count, 0), scimMaxCount)
+
+	users, total, err := h.service.List(c.Request.Context(), c.Query("filter"), startIndex, count)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	resources := mapper.ToSCIMUsers(users, scimBaseURL(c))
+	h.respond(c, http.StatusOK, scim.NewListResponse(resources, total, startIndex, len(resources)))
+}
+
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	id, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, mapper.ToSCIMUser(user, scimBaseURL(c)))
+}
+
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var resource scim.User
+	if !h.bind(c, &resource) {
+		return
+	}
+
+	user, err := h.service.Create(c.Request.Context(), &resource)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	resp := mapper.ToSCIMUser(user, scimBaseURL(c))
+	c.Header("Location", resp.Meta.Location)
+	h.respond(c, http.StatusCreated, resp)
+}
+
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	id, ok := h.userID(c)
+	if !ok {
+		return
+	}
+	var resource scim.User
+	if !h.bind(c, &resource) {
+		return
+	}
+
+	user, err := h.service.Replace(c.Request.Context(), id, &resource)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, mapper.ToSCIMUser(user, scimBaseURL(c)))
+}
+
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	id, ok := h.userID(c)
+	if !ok {
+		return
+	}
+	var req scim.PatchRequest
+	if !h.bind(c, &req) {
+		return
+	}
+
+	user, err := h.service.Patch(c.Request.Context(), id, &req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, mapper.ToSCIMUser(user, scimBaseURL(c)))
+}
+
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	id, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ServiceProviderConfig tells clients which optional features we support
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	h.respond(c, http.StatusOK, gin.H{
+		"schemas":        []string{scim.ServiceProviderConfigSchema},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": scimMaxCount},
+		"changePassword": gin.H{"supported": false},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "The SCIM_TOKEN configured on the server",
+			"primary":     true,
+		}},
+		"meta": gin.H{
+			"resourceType": "ServiceProviderConfig",
+			"location":     scimBaseURL(c) + "/ServiceProviderConfig",
+		},
+	})
+}
+
+func (h *SCIMHandler) userID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		// Ids we could not have issued belong to no user
+		h.respondError(c, gorm.ErrRecordNotFound)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// bind accepts both application/scim+json and application/json bodies
+func (h *SCIMHandler) bind(c *gin.Context, v interface{}) bool {
+	if err := json.NewDecoder(c.Request.Body).Decode(v); err != nil {
+		h.respondError(c, scim.NewError(http.StatusBadRequest, scim.ErrInvalidSyntax, "invalid request body"))
+		return false
+	}
+	return true
+}
+
+func (h *SCIMHandler) respond(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", scim.ContentType)
+	c.JSON(status, body)
+}
+
+func (h *SCIMHandler) respondError(c *gin.Context, err error) {
+	var scimErr *scim.Error
+	switch {
+	case errors.As(err, &scimErr):
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		scimErr = scim.NewError(http.StatusNotFound, "", "user not found")
+	case errors.Is(err, service.ErrEmailTaken):
+		scimErr = scim.NewError(http.StatusConflict, scim.ErrUniqueness, "userName is already taken")
+	default:
+		scimErr = scim.NewError(http.StatusInternalServerError, "", "internal error")
+	}
+	h.respond(c, scimErr.StatusCode(), scimErr)
+}
+
+func scimIntParam(c *gin.Context, name string, fallback int) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, name+" must be an integer")
+	}
+	return value, nil
+}
+
+func scimBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + "/scim/v2"
+}
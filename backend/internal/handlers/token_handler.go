@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"backend/internal/generated"
+	"backend/internal/handlers/mapper"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TokenHandler struct {
+	service service.PersonalAccessTokenService
+}
+
+func NewTokenHandler(service service.PersonalAccessTokenService) *TokenHandler {
+	return &TokenHandler{service: service}
+}
+
+func (h *TokenHandler) ListPersonalAccessTokens(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	tokens, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to fetch tokens",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": mapper.ToGeneratedPersonalAccessTokens(tokens),
+	})
+}
+
+func (h *TokenHandler) CreatePersonalAccessToken(c *gin.Context) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	var req generated.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	token, raw, err := h.service.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidScope) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to create token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, generated.CreatePersonalAccessTokenResponse{
+		Token: raw,
+		Data:  mapper.ToGeneratedPersonalAccessToken(token),
+	})
+}
+
+func (h *TokenHandler) RevokePersonalAccessToken(c *gin.Context, id generated.IdParam) {
+	userID, ok := GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, generated.Error{
+			Message: "missing user_id",
+		})
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), userID, id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "Token not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to revoke token",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -5,6 +5,7 @@ import (
 	"backend/internal/handlers/mapper"
 	"backend/internal/models"
 	"backend/internal/service"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,12 +13,14 @@ import (
 )
 
 type UserHandler struct {
-	service service.UserService
+	service     service.UserService
+	invitations service.InvitationService
 }
 
-func NewUserHandler(service service.UserService) *UserHandler {
+func NewUserHandler(service service.UserService, invitations service.InvitationService) *UserHandler {
 	return &UserHandler{
-		service: service,
+		service:     service,
+		invitations: invitations,
 	}
 }
 
@@ -68,10 +71,32 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		Role:     "user",
 		IsActive: true,
 	}
+	if req.Role != nil {
+		user.Role = string(*req.Role)
+		if err := h.service.CheckRoleAssignment(c.Request.Context(), GetPrincipal(c), nil, user.Role); err != nil {
+			h.respondRoleError(c, err)
+			return
+		}
+	}
 
-	if err := h.service.CreateUser(c.Request.Context(), user); err != nil {
+	// The admin is recorded as the inviter when known
+	invitedBy, _ := GetUserID(c)
+
+	if _, err := h.invitations.Invite(c.Request.Context(), user, invitedBy); err != nil {
+		if errors.Is(err, service.ErrEmailTaken) {
+			c.JSON(http.StatusConflict, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrUnknownRole) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, generated.Error{
-			Message: err.Error(),
+			Message: "Failed to invite user",
 		})
 		return
 	}
@@ -134,6 +159,10 @@ func (h *UserHandler) UpdateUser(c *gin.Context, id generated.IdParam) {
 		existing.Email = string(*req.Email)
 	}
 	if req.Role != nil {
+		if err := h.service.CheckRoleAssignment(c.Request.Context(), GetPrincipal(c), existing, string(*req.Role)); err != nil {
+			h.respondRoleError(c, err)
+			return
+		}
 		existing.Role = string(*req.Role)
 	}
 	if req.IsActive != nil {
@@ -141,6 +170,12 @@ func (h *UserHandler) UpdateUser(c *gin.Context, id generated.IdParam) {
 	}
 
 	if err := h.service.UpdateUser(c.Request.Context(), id, existing); err != nil {
+		if errors.Is(err, service.ErrUnknownRole) {
+			c.JSON(http.StatusBadRequest, generated.Error{
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, generated.Error{
 			Message: "Failed to update user",
 		})
@@ -168,3 +203,55 @@ func (h *UserHandler) DeleteUser(c *gin.Context, id generated.IdParam) {
 
 	c.Status(http.StatusNoContent)
 }
+
+func (h *UserHandler) RevokeUserSessions(c *gin.Context, id generated.IdParam) {
+	if err := h.service.RevokeSessions(c.Request.Context(), id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "User not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to revoke sessions",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) ClearUserLockout(c *gin.Context, id generated.IdParam) {
+	if err := h.service.ClearLockout(c.Request.Context(), id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, generated.Error{
+				Message: "User not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, generated.Error{
+			Message: "Failed to clear lockout",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) respondRoleError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrUnknownRole) {
+		c.JSON(http.StatusBadRequest, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrRoleNotAssignable) || errors.Is(err, service.ErrCannotChangeOwnRole) {
+		c.JSON(http.StatusForbidden, generated.Error{
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, generated.Error{
+		Message: "Failed to check role",
+	})
+}
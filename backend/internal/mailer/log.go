@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// LogMailer writes every message to a file or stdout instead of sending
+// it, for local development and tests
+type LogMailer struct {
+	from string
+	out  io.Writer
+	mu   sync.Mutex
+}
+
+// NewLogMailer appends messages to path, or writes to stdout if path is empty
+func NewLogMailer(from, path string) (*LogMailer, error) {
+	if path == "" {
+		return &LogMailer{from: from, out: os.Stdout}, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mail log: %w", err)
+	}
+
+	return &LogMailer{from: from, out: f}, nil
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.out, "----- mail -----\n%s\n----- end mail -----\n", buildMessage(m.from, msg))
+	return err
+}
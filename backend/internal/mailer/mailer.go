@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"backend/internal/config"
+	"context"
+	"fmt"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers plain text transactional email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg), nil
+	case "log":
+		return NewLogMailer(cfg.From, cfg.LogFile)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
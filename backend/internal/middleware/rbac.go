@@ -3,22 +3,51 @@ package middleware
 
 import (
 	"backend/internal/generated"
-	jwt "backend/pkg"
+	"backend/internal/models"
+	"backend/internal/service"
+	"backend/internal/session"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// SecurityOptions tunes OpenAPISecurityMiddleware
+type SecurityOptions struct {
+	// RequireVerifiedEmail rejects users without a verified email on
+	// protected routes, except those marked x-allow-unverified
+	RequireVerifiedEmail bool
+	// MFARequiredRoles are confined to routes marked x-allow-without-mfa
+	// until they have enrolled in two-factor authentication
+	MFARequiredRoles []string
+	// ServiceClients maps service client ids to secrets for routes that
+	// declare ServiceAuth
+	ServiceClients map[string]string
+	// SessionCookies accepts the access token from its cookie when no
+	// Authorization header is sent
+	SessionCookies bool
+}
+
 // OpenAPISecurityMiddleware enforces security rules from OpenAPI spec
-// Uses auto-generated RouteSecurity map from contracts/openapi.yaml
-func OpenAPISecurityMiddleware() gin.HandlerFunc {
+// Uses auto-generated RouteSecurity map from contracts/openapi.yaml. A
+// request must satisfy one of the route's security requirements, and
+// every scheme within it; scopes are checked against the caller's role.
+// Operations without rules are denied.
+func OpenAPISecurityMiddleware(tokens service.TokenService, permissions service.PermissionResolver, opts SecurityOptions) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		path := c.Request.URL.Path
-		method := c.Request.Method
-
-		// Get security info from generated map
-		secInfo := getRouteSecurityInfo(path, method)
+		// Get security info for the route Gin matched
+		secInfo, ok := getRouteSecurityInfo(c.FullPath(), c.Request.Method)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, generated.Error{
+				Message: "no security rules are defined for this operation",
+			})
+			return
+		}
 
 		// Public endpoint - skip auth
 		if secInfo.IsPublic {
@@ -26,105 +55,377 @@ func OpenAPISecurityMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Protected endpoint - validate JWT
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, generated.Error{
-				Message: "authorization required",
+		check := &securityCheck{
+			c:           c,
+			tokens:      tokens,
+			permissions: permissions,
+			opts:        opts,
+			results:     make(map[string]schemeResult),
+		}
+
+		var match *requirementMatch
+		var failure *securityFailure
+		anonymous := false
+		for _, req := range secInfo.Security {
+			// {} = anonymous callers are allowed if nothing else matches
+			if len(req) == 0 {
+				anonymous = true
+				continue
+			}
+
+			m, f := check.satisfy(req)
+			if f == nil {
+				match = m
+				break
+			}
+			if f.status == http.StatusInternalServerError {
+				c.AbortWithStatusJSON(f.status, generated.Error{Message: f.message})
+				return
+			}
+			if failure == nil || f.rank() > failure.rank() {
+				failure = f
+			}
+		}
+
+		if match == nil {
+			if anonymous {
+				c.Next()
+				return
+			}
+			if failure == nil {
+				failure = &securityFailure{status: http.StatusUnauthorized, message: "authorization required"}
+			}
+			if failure.challenge != "" {
+				c.Header("WWW-Authenticate", failure.challenge)
+			}
+			c.AbortWithStatusJSON(failure.status, generated.Error{
+				Message: failure.message,
 			})
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, generated.Error{
-				Message: "invalid authorization format",
+		if match.serviceClient != "" {
+			c.Set("service_client", match.serviceClient)
+		}
+
+		principal := match.principal
+		if principal == nil {
+			c.Next()
+			return
+		}
+
+		// Set user context from current account state
+		if principal.Claims != nil {
+			c.Set("claims", principal.Claims)
+		}
+		c.Set("principal", principal)
+		c.Set("user_id", principal.UserID)
+		c.Set("email", principal.Email)
+		c.Set("role", principal.Role)
+		if principal.IsImpersonated() {
+			c.Set("actor_id", principal.ActorID)
+		}
+
+		if opts.RequireVerifiedEmail && !principal.EmailVerified && !secInfo.AllowUnverified {
+			c.AbortWithStatusJSON(http.StatusForbidden, generated.Error{
+				Message: service.ErrEmailNotVerified.Error(),
 			})
 			return
 		}
 
-		claims, err := jwt.ParseToken(parts[1])
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, generated.Error{
-				Message: "invalid token",
+		if !principal.MFAEnabled && !secInfo.AllowWithoutMFA && slices.Contains(opts.MFARequiredRoles, principal.Role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, generated.Error{
+				Message: service.ErrMFARequired.Error(),
 			})
 			return
 		}
 
-		// Set user context
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+		if secInfo.Sensitive && principal.IsPersonalAccessToken() {
+			c.AbortWithStatusJSON(http.StatusForbidden, generated.Error{
+				Message: "this operation is not available to personal access tokens",
+			})
+			return
+		}
 
-		// Empty scopes = any authenticated user is allowed
-		if len(secInfo.RequiredScopes) == 0 {
-			c.Next()
+		if secInfo.Sensitive && principal.IsImpersonated() {
+			c.AbortWithStatusJSON(http.StatusForbidden, generated.Error{
+				Message: "this operation is not available while impersonating a user",
+			})
 			return
 		}
 
-		// Check if user role matches required scopes
-		for _, scope := range secInfo.RequiredScopes {
-			if claims.Role == scope {
-				c.Next()
-				return
+		c.Next()
+	}
+}
+
+// securityFailure is why a request did not satisfy a requirement
+type securityFailure struct {
+	status  int
+	message string
+	// missing is set when no credential was sent for the scheme
+	missing bool
+	// challenge is sent as WWW-Authenticate
+	challenge string
+}
+
+// rank orders failures so the response explains the closest miss: a
+// permission problem, then a rejected credential, then a missing one
+func (f *securityFailure) rank() int {
+	switch {
+	case f.status == http.StatusForbidden:
+		return 2
+	case !f.missing:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// requirementMatch is who satisfied a requirement
+type requirementMatch struct {
+	principal     *service.Principal
+	serviceClient string
+}
+
+// schemeResult is the outcome of authenticating one scheme
+type schemeResult struct {
+	principal     *service.Principal
+	serviceClient string
+	failure       *securityFailure
+}
+
+// securityCheck evaluates requirements for one request, authenticating
+// each scheme at most once
+type securityCheck struct {
+	c           *gin.Context
+	tokens      service.TokenService
+	permissions service.PermissionResolver
+	opts        SecurityOptions
+	results     map[string]schemeResult
+}
+
+// satisfy checks every scheme of a requirement, including its scopes
+func (s *securityCheck) satisfy(req generated.SecurityRequirement) (*requirementMatch, *securityFailure) {
+	match := &requirementMatch{}
+	for _, scheme := range req {
+		result := s.authenticate(scheme.Scheme)
+		if result.failure != nil {
+			return nil, result.failure
+		}
+
+		if result.serviceClient != "" {
+			// Service clients hold no permissions; generate-rbac refuses
+			// such specs, this keeps a hand-edited map from failing open
+			if len(scheme.Scopes) > 0 {
+				return nil, &securityFailure{status: http.StatusForbidden, message: "insufficient permissions"}
 			}
+			match.serviceClient = result.serviceClient
+			continue
+		}
+
+		if match.principal != nil && match.principal.UserID != result.principal.UserID {
+			return nil, &securityFailure{status: http.StatusUnauthorized, message: "credentials belong to different users"}
 		}
+		if match.principal == nil {
+			match.principal = result.principal
+		}
+
+		// Empty scopes = any authenticated user is allowed
+		allowed, err := s.permissions.Authorize(s.c.Request.Context(), result.principal, scheme.Scopes)
+		if err != nil {
+			return nil, &securityFailure{status: http.StatusInternalServerError, message: "failed to resolve permissions"}
+		}
+		if !allowed {
+			return nil, &securityFailure{status: http.StatusForbidden, message: "insufficient permissions"}
+		}
+	}
+	return match, nil
+}
+
+// authenticate checks the credential for a scheme by its type. Types the
+// API does not implement never match.
+func (s *securityCheck) authenticate(name string) schemeResult {
+	if result, ok := s.results[name]; ok {
+		return result
+	}
+
+	var result schemeResult
+	info := generated.SecuritySchemes[name]
+	switch {
+	case info.Type == "http" && info.Scheme == "basic":
+		result = s.authenticateService()
+	case info.Type == "http" && info.Scheme == "bearer",
+		info.Type == "oauth2", info.Type == "openIdConnect":
+		result = s.authenticateBearer()
+	case info.Type == "apiKey":
+		result = s.authenticateAPIKey(info)
+	default:
+		result = schemeResult{failure: &securityFailure{
+			status:  http.StatusUnauthorized,
+			message: "unsupported security scheme " + name,
+		}}
+	}
 
-		c.AbortWithStatusJSON(http.StatusForbidden, generated.Error{
-			Message: "insufficient permissions",
-		})
+	s.results[name] = result
+	return result
+}
+
+// authenticateService checks HTTP Basic service client credentials
+func (s *securityCheck) authenticateService() schemeResult {
+	clientID, ok := authenticateServiceClient(s.c, s.opts.ServiceClients)
+	if !ok {
+		return schemeResult{failure: &securityFailure{
+			status:    http.StatusUnauthorized,
+			message:   "invalid service client credentials",
+			challenge: `Basic realm="service"`,
+		}}
 	}
+	return schemeResult{serviceClient: clientID}
 }
 
-// getRouteSecurityInfo retrieves security info from generated.RouteSecurity
-func getRouteSecurityInfo(path, method string) generated.RouteSecurityInfo {
-	// Check exact path match
-	if methods, ok := generated.RouteSecurity[path]; ok {
-		if secInfo, ok := methods[method]; ok {
-			return secInfo
+// authenticateBearer reads the token from the Authorization header or,
+// when enabled, from the session cookie
+func (s *securityCheck) authenticateBearer() schemeResult {
+	if authHeader := s.c.GetHeader("Authorization"); authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return schemeResult{failure: &securityFailure{
+				status:  http.StatusUnauthorized,
+				message: "invalid authorization format",
+			}}
 		}
+		return s.authenticateToken(parts[1])
 	}
 
-	// Check path with dynamic parameter (e.g., /api/v1/users/123)
-	for routePath, methods := range generated.RouteSecurity {
-		if matchDynamicRoute(path, routePath) {
-			if secInfo, ok := methods[method]; ok {
-				return secInfo
+	if s.opts.SessionCookies {
+		if token := session.Cookie(s.c, session.AccessTokenCookie); token != "" {
+			// Browsers attach cookies to forged cross-site requests too
+			if !session.SafeMethod(s.c.Request.Method) && !session.ValidCSRF(s.c) {
+				return schemeResult{failure: &securityFailure{
+					status:  http.StatusForbidden,
+					message: "invalid CSRF token",
+				}}
 			}
+			return s.authenticateToken(token)
 		}
 	}
 
-	// Default: require authentication but no specific role
-	return generated.RouteSecurityInfo{
-		IsPublic:       false,
-		RequiredScopes: []string{},
+	return schemeResult{failure: &securityFailure{
+		status:  http.StatusUnauthorized,
+		message: "authorization required",
+		missing: true,
+	}}
+}
+
+// authenticateAPIKey reads a personal access token from where the scheme
+// puts it
+func (s *securityCheck) authenticateAPIKey(info generated.SecuritySchemeInfo) schemeResult {
+	var apiKey string
+	switch info.In {
+	case "header":
+		apiKey = s.c.GetHeader(info.Name)
+	case "query":
+		apiKey = s.c.Query(info.Name)
+	case "cookie":
+		apiKey = session.Cookie(s.c, info.Name)
+	}
+
+	if apiKey == "" {
+		return schemeResult{failure: &securityFailure{
+			status:  http.StatusUnauthorized,
+			message: "authorization required",
+			missing: true,
+		}}
+	}
+	if !strings.HasPrefix(apiKey, models.PersonalAccessTokenPrefix) {
+		return schemeResult{failure: &securityFailure{
+			status:  http.StatusUnauthorized,
+			message: "invalid API key",
+		}}
+	}
+	return s.authenticateToken(apiKey)
+}
+
+// authenticateToken validates a JWT or personal access token
+func (s *securityCheck) authenticateToken(rawToken string) schemeResult {
+	principal, err := s.tokens.Authenticate(s.c.Request.Context(), rawToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) ||
+			errors.Is(err, service.ErrTokenRevoked) ||
+			errors.Is(err, service.ErrAccountInactive) {
+			return schemeResult{failure: &securityFailure{
+				status:  http.StatusUnauthorized,
+				message: err.Error(),
+			}}
+		}
+		return schemeResult{failure: &securityFailure{
+			status:  http.StatusInternalServerError,
+			message: "failed to validate token",
+		}}
 	}
+	return schemeResult{principal: principal}
 }
 
-// matchDynamicRoute checks if actual path matches route pattern
-// Example: /api/v1/users/123 matches /api/v1/users/{id}
-func matchDynamicRoute(actualPath, routePattern string) bool {
-	if !strings.Contains(routePattern, "{") {
-		return false
+// authenticateServiceClient checks HTTP Basic credentials against the
+// configured service clients
+func authenticateServiceClient(c *gin.Context, clients map[string]string) (string, bool) {
+	clientID, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		return "", false
+	}
+	expected, ok := clients[clientID]
+	if !ok {
+		return "", false
+	}
+	// Compare digests so the comparison time does not depend on length
+	got, want := sha256.Sum256([]byte(secret)), sha256.Sum256([]byte(expected))
+	if subtle.ConstantTimeCompare(got[:], want[:]) != 1 {
+		return "", false
 	}
+	return clientID, true
+}
 
-	actualParts := strings.Split(actualPath, "/")
-	patternParts := strings.Split(routePattern, "/")
+// routeSecurity is generated.RouteSecurity keyed by Gin route pattern,
+// /users/:id rather than /users/{id}, to match c.FullPath()
+var routeSecurity = ginRouteSecurity(generated.RouteSecurity)
 
-	if len(actualParts) != len(patternParts) {
-		return false
+func ginRouteSecurity(routes map[string]map[string]generated.RouteSecurityInfo) map[string]map[string]generated.RouteSecurityInfo {
+	result := make(map[string]map[string]generated.RouteSecurityInfo, len(routes))
+	for path, methods := range routes {
+		result[ginPath(path)] = methods
 	}
+	return result
+}
 
-	for i, patternPart := range patternParts {
-		// {id}, {productId}, etc. match any value
-		if strings.HasPrefix(patternPart, "{") && strings.HasSuffix(patternPart, "}") {
-			continue
+// ginPath converts an OpenAPI path template to a Gin route pattern
+func ginPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			parts[i] = ":" + strings.TrimSuffix(strings.TrimPrefix(part, "{"), "}")
 		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// getRouteSecurityInfo looks up the rules for a matched route pattern
+func getRouteSecurityInfo(fullPath, method string) (generated.RouteSecurityInfo, bool) {
+	secInfo, ok := routeSecurity[fullPath][method]
+	return secInfo, ok
+}
 
-		if actualParts[i] != patternPart {
-			return false
+// CheckRouteSecurity fails when any of the routes has no security rules,
+// since OpenAPISecurityMiddleware would deny every request to it
+func CheckRouteSecurity(routes gin.RoutesInfo) error {
+	var missing []string
+	for _, route := range routes {
+		if _, ok := getRouteSecurityInfo(route.Path, route.Method); !ok {
+			missing = append(missing, route.Method+" "+route.Path)
 		}
 	}
-
-	return true
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("routes without security rules: %s", strings.Join(missing, ", "))
+	}
+	return nil
 }
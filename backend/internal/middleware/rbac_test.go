@@ -0,0 +1,295 @@
+package middleware
+
+import (
+	"backend/internal/generated"
+	"backend/internal/service"
+	"backend/internal/session"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeTokens knows a fixed set of raw tokens
+type fakeTokens struct {
+	service.TokenService
+	principals map[string]*service.Principal
+}
+
+func (f *fakeTokens) Authenticate(ctx context.Context, rawToken string) (*service.Principal, error) {
+	principal, ok := f.principals[rawToken]
+	if !ok {
+		return nil, service.ErrInvalidToken
+	}
+	copied := *principal
+	return &copied, nil
+}
+
+// fakePermissions grants each role a fixed list of permissions
+type fakePermissions map[string][]string
+
+func (p fakePermissions) RolePermissions(ctx context.Context, role string) ([]string, error) {
+	return p[role], nil
+}
+
+func (p fakePermissions) Authorize(ctx context.Context, principal *service.Principal, required []string) (bool, error) {
+	for _, permission := range required {
+		if !slices.Contains(p[principal.Role], permission) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+var testTokens = &fakeTokens{principals: map[string]*service.Principal{
+	"admin-token":  {UserID: "admin", Role: "admin"},
+	"user-token":   {UserID: "user", Role: "user"},
+	"pat_admin":    {UserID: "admin", Role: "admin", PersonalAccessTokenID: "pat-1"},
+	"pat_user":     {UserID: "user", Role: "user", PersonalAccessTokenID: "pat-3"},
+	"pat_somebody": {UserID: "somebody", Role: "admin", PersonalAccessTokenID: "pat-2"},
+	"cookie-token": {UserID: "cookie-user", Role: "admin"},
+}}
+
+var testPermissions = fakePermissions{
+	"admin": {"items:read", "items:write"},
+	"user":  {"items:read"},
+}
+
+// useRoutes swaps the generated rules for the test's own
+func useRoutes(t *testing.T, routes map[string]map[string]generated.RouteSecurityInfo) {
+	t.Helper()
+	saved := routeSecurity
+	routeSecurity = ginRouteSecurity(routes)
+	t.Cleanup(func() { routeSecurity = saved })
+}
+
+func requires(requirements ...generated.SecurityRequirement) generated.RouteSecurityInfo {
+	return generated.RouteSecurityInfo{Security: requirements}
+}
+
+func scheme(name string, scopes ...string) generated.SchemeRequirement {
+	return generated.SchemeRequirement{Scheme: name, Scopes: scopes}
+}
+
+// newSecuredRouter answers 200 with who the middleware let through
+func newSecuredRouter(opts SecurityOptions, paths ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(OpenAPISecurityMiddleware(testTokens, testPermissions, opts))
+	for _, path := range paths {
+		r.Any(path, func(c *gin.Context) {
+			c.String(http.StatusOK, "user=%s service=%s", c.GetString("user_id"), c.GetString("service_client"))
+		})
+	}
+	return r
+}
+
+type requestOption func(*http.Request)
+
+func bearer(token string) requestOption {
+	return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+}
+
+func apiKey(key string) requestOption {
+	return func(r *http.Request) { r.Header.Set("X-API-Key", key) }
+}
+
+func serviceClient(id, secret string) requestOption {
+	return func(r *http.Request) { r.SetBasicAuth(id, secret) }
+}
+
+func cookie(name, value string) requestOption {
+	return func(r *http.Request) { r.AddCookie(&http.Cookie{Name: name, Value: value}) }
+}
+
+func header(name, value string) requestOption {
+	return func(r *http.Request) { r.Header.Set(name, value) }
+}
+
+func serve(r *gin.Engine, method, path string, opts ...requestOption) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for _, opt := range opts {
+		opt(req)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSecurityRequirements(t *testing.T) {
+	useRoutes(t, map[string]map[string]generated.RouteSecurityInfo{
+		"/items": {
+			// Either scheme
+			"GET": requires(
+				generated.SecurityRequirement{scheme("BearerAuth", "items:read")},
+				generated.SecurityRequirement{scheme("ApiKeyAuth", "items:read")},
+			),
+			// Both schemes
+			"POST":  requires(generated.SecurityRequirement{scheme("ServiceAuth"), scheme("ApiKeyAuth", "items:write")}),
+			"PATCH": requires(generated.SecurityRequirement{scheme("BearerAuth", "items:write"), scheme("ApiKeyAuth")}),
+			// Service clients hold no permissions
+			"PUT": requires(generated.SecurityRequirement{scheme("ServiceAuth", "items:write")}),
+		},
+		"/items/{id}": {
+			// Authenticated if possible, anonymous otherwise
+			"GET": requires(generated.SecurityRequirement{}, generated.SecurityRequirement{scheme("BearerAuth")}),
+			// security: [] overriding a default
+			"HEAD": {IsPublic: true},
+		},
+	})
+	r := newSecuredRouter(SecurityOptions{ServiceClients: map[string]string{"billing": "s3cret"}}, "/items", "/items/:id")
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		opts      []requestOption
+		status    int
+		body      string
+		challenge string
+	}{
+		{"or: bearer alone", "GET", "/items", []requestOption{bearer("user-token")}, 200, "user=user service=", ""},
+		{"or: api key alone", "GET", "/items", []requestOption{apiKey("pat_admin")}, 200, "user=admin service=", ""},
+		{"or: nothing", "GET", "/items", nil, 401, "authorization required", ""},
+		{"or: rejected credential beats a missing one", "GET", "/items", []requestOption{bearer("forged")}, 401, service.ErrInvalidToken.Error(), ""},
+		{"or: api key without the pat prefix", "GET", "/items", []requestOption{apiKey("admin-token")}, 401, "invalid API key", ""},
+		{"or: malformed authorization header", "GET", "/items", []requestOption{func(r *http.Request) { r.Header.Set("Authorization", "Token x") }}, 401, "invalid authorization format", ""},
+
+		{"and: both schemes", "POST", "/items", []requestOption{serviceClient("billing", "s3cret"), apiKey("pat_admin")}, 200, "user=admin service=billing", ""},
+		{"and: api key alone", "POST", "/items", []requestOption{apiKey("pat_admin")}, 401, "invalid service client credentials", `Basic realm="service"`},
+		{"and: wrong service secret", "POST", "/items", []requestOption{serviceClient("billing", "guess"), apiKey("pat_admin")}, 401, "invalid service client credentials", `Basic realm="service"`},
+		{"and: service client alone", "POST", "/items", []requestOption{serviceClient("billing", "s3cret")}, 401, "authorization required", ""},
+		{"and: api key lacks the scope", "POST", "/items", []requestOption{serviceClient("billing", "s3cret"), apiKey("pat_user")}, 403, "insufficient permissions", ""},
+		{"and: same user twice", "PATCH", "/items", []requestOption{bearer("admin-token"), apiKey("pat_admin")}, 200, "user=admin service=", ""},
+		{"and: credentials of different users", "PATCH", "/items", []requestOption{bearer("admin-token"), apiKey("pat_somebody")}, 401, "credentials belong to different users", ""},
+
+		{"service client refused when scopes are required", "PUT", "/items", []requestOption{serviceClient("billing", "s3cret")}, 403, "insufficient permissions", ""},
+
+		{"{}: anonymous", "GET", "/items/42", nil, 200, "user= service=", ""},
+		{"{}: authenticated when possible", "GET", "/items/42", []requestOption{bearer("user-token")}, 200, "user=user service=", ""},
+		{"{}: a rejected credential falls back to anonymous", "GET", "/items/42", []requestOption{bearer("forged")}, 200, "user= service=", ""},
+
+		{"security: [] ignores credentials", "HEAD", "/items/42", []requestOption{bearer("forged")}, 200, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, tt.method, tt.path, tt.opts...)
+			if w.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tt.body) {
+				t.Fatalf("body %q, want it to contain %q", w.Body, tt.body)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Fatalf("WWW-Authenticate %q, want %q", got, tt.challenge)
+			}
+		})
+	}
+}
+
+func TestSessionCookies(t *testing.T) {
+	useRoutes(t, map[string]map[string]generated.RouteSecurityInfo{
+		"/items": {
+			"GET":    requires(generated.SecurityRequirement{scheme("BearerAuth", "items:read")}),
+			"POST":   requires(generated.SecurityRequirement{scheme("BearerAuth", "items:write")}),
+			"DELETE": requires(generated.SecurityRequirement{scheme("BearerAuth", "items:write")}),
+		},
+	})
+	r := newSecuredRouter(SecurityOptions{SessionCookies: true}, "/items")
+	signedIn := []requestOption{cookie(session.AccessTokenCookie, "cookie-token"), cookie(session.CSRFCookie, "csrf-1")}
+	with := func(opts ...requestOption) []requestOption {
+		return append(append([]requestOption{}, signedIn...), opts...)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		opts   []requestOption
+		status int
+		body   string
+	}{
+		{"safe method needs no CSRF token", "GET", signedIn, 200, "user=cookie-user"},
+		{"unsafe method without CSRF token", "POST", signedIn, 403, "invalid CSRF token"},
+		{"unsafe method with the wrong CSRF token", "DELETE", with(header(session.CSRFHeader, "csrf-2")), 403, "invalid CSRF token"},
+		{"CSRF header without its cookie", "POST", []requestOption{cookie(session.AccessTokenCookie, "cookie-token"), header(session.CSRFHeader, "csrf-1")}, 403, "invalid CSRF token"},
+		{"unsafe method with the CSRF token", "POST", with(header(session.CSRFHeader, "csrf-1")), 200, "user=cookie-user"},
+		{"authorization header wins over the cookie", "GET", with(bearer("user-token")), 200, "user=user"},
+		{"authorization header needs no CSRF token", "POST", with(bearer("admin-token")), 200, "user=admin"},
+		{"a bad authorization header does not fall back to the cookie", "GET", with(bearer("forged")), 401, service.ErrInvalidToken.Error()},
+		{"rejected cookie token", "GET", []requestOption{cookie(session.AccessTokenCookie, "forged")}, 401, service.ErrInvalidToken.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, tt.method, "/items", tt.opts...)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.body) {
+				t.Fatalf("status %d %s, want %d %q", w.Code, w.Body, tt.status, tt.body)
+			}
+		})
+	}
+
+	// Without the option the cookie is not a credential
+	r = newSecuredRouter(SecurityOptions{}, "/items")
+	if w := serve(r, "GET", "/items", signedIn...); w.Code != http.StatusUnauthorized {
+		t.Fatalf("cookies disabled: status %d, want 401", w.Code)
+	}
+}
+
+func TestSecurityFailurePrecedence(t *testing.T) {
+	useRoutes(t, map[string]map[string]generated.RouteSecurityInfo{
+		"/reports": {
+			"GET": requires(
+				generated.SecurityRequirement{scheme("ServiceAuth")},
+				generated.SecurityRequirement{scheme("BearerAuth", "items:write")},
+			),
+		},
+	})
+	r := newSecuredRouter(SecurityOptions{ServiceClients: map[string]string{"billing": "s3cret"}}, "/reports")
+
+	// A missing permission explains more than a missing service credential
+	w := serve(r, "GET", "/reports", bearer("user-token"))
+	if w.Code != http.StatusForbidden || w.Header().Get("WWW-Authenticate") != "" {
+		t.Fatalf("status %d, WWW-Authenticate %q: %s", w.Code, w.Header().Get("WWW-Authenticate"), w.Body)
+	}
+
+	// With nothing sent, the service client answer carries the Basic challenge
+	w = serve(r, "GET", "/reports")
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != `Basic realm="service"` {
+		t.Fatalf("status %d, WWW-Authenticate %q: %s", w.Code, w.Header().Get("WWW-Authenticate"), w.Body)
+	}
+}
+
+func TestRoutesWithoutRulesAreDenied(t *testing.T) {
+	useRoutes(t, map[string]map[string]generated.RouteSecurityInfo{
+		"/items": {"GET": {IsPublic: true}},
+	})
+	r := newSecuredRouter(SecurityOptions{}, "/items", "/unlisted")
+
+	if w := serve(r, "GET", "/items"); w.Code != http.StatusOK {
+		t.Fatalf("listed route: status %d", w.Code)
+	}
+	if w := serve(r, "GET", "/unlisted", bearer("admin-token")); w.Code != http.StatusForbidden {
+		t.Fatalf("unlisted route: status %d, want 403", w.Code)
+	}
+	// A listed path with an unlisted method is denied too
+	if w := serve(r, "DELETE", "/items", bearer("admin-token")); w.Code != http.StatusForbidden {
+		t.Fatalf("unlisted method: status %d, want 403", w.Code)
+	}
+
+	err := CheckRouteSecurity(r.Routes())
+	if err == nil {
+		t.Fatal("CheckRouteSecurity accepted routes without rules")
+	}
+	if !strings.Contains(err.Error(), "GET /unlisted") || strings.Contains(err.Error(), "GET /items") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestGeneratedRoutesUseGinPatterns(t *testing.T) {
+	if got := ginPath("/users/{id}/sessions/{sessionId}"); got != "/users/:id/sessions/:sessionId" {
+		t.Fatalf("ginPath = %s", got)
+	}
+}
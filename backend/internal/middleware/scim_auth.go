@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"backend/pkg/scim"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMAuth admits requests bearing the SCIM provisioning token. It is a
+// shared secret held by the identity provider, not a user session.
+func SCIMAuth(token string) gin.HandlerFunc {
+	expected := sha256.Sum256([]byte(token))
+
+	return func(c *gin.Context) {
+		presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		// Hashing first keeps the comparison constant time whatever the length
+		digest := sha256.Sum256([]byte(presented))
+		if !ok || subtle.ConstantTimeCompare(digest[:], expected[:]) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="scim"`)
+			c.Header("Content-Type", scim.ContentType)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, scim.NewError(http.StatusUnauthorized, "", "invalid SCIM token"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Impersonation records an admin acting as another user. The ID is also
+// the jti of the token that was issued, so the record identifies it.
+type Impersonation struct {
+	BaseUUID
+	ActorID   uuid.UUID `gorm:"type:uuid;not null;index" json:"actor_id"`
+	TargetID  uuid.UUID `gorm:"type:uuid;not null;index" json:"target_id"`
+	Reason    string    `gorm:"type:varchar(500);not null" json:"reason"`
+	IPAddress string    `gorm:"type:varchar(64)" json:"ip_address"`
+	UserAgent string    `gorm:"type:varchar(512)" json:"user_agent"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (Impersonation) TableName() string {
+	return "impersonations"
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation lets an admin-created user set their own password. The link
+// carries a signed token; only the hash of the latest one is stored, so
+// resending voids earlier links. Revoking deletes the pending user, which
+// takes the invitation with it.
+type Invitation struct {
+	BaseUUID
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	User       User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user"`
+	InvitedBy  *uuid.UUID `gorm:"type:uuid" json:"invited_by,omitempty"`
+	TokenHash  string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	SentAt     time.Time  `gorm:"not null" json:"sent_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Invitation) TableName() string {
+	return "invitations"
+}
+
+func (i *Invitation) IsAccepted() bool {
+	return i.AcceptedAt != nil
+}
+
+func (i *Invitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LoginOutcomeSuccess            = "success"
+	LoginOutcomeMFAChallenge       = "mfa_challenge"
+	LoginOutcomeInvalidCredentials = "invalid_credentials"
+	LoginOutcomeInvalidMFACode     = "invalid_mfa_code"
+	LoginOutcomeLockedOut          = "locked_out"
+	LoginOutcomeAccountDisabled    = "account_disabled"
+	LoginOutcomeEmailNotVerified   = "email_not_verified"
+)
+
+// LoginAttempt records one login or MFA step. UserID is empty when the
+// email did not match an account.
+type LoginAttempt struct {
+	BaseUUID
+	UserID    *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Email     string     `gorm:"type:varchar(255);not null;index" json:"email"`
+	IPAddress string     `gorm:"type:varchar(64)" json:"ip_address"`
+	UserAgent string     `gorm:"type:varchar(512)" json:"user_agent"`
+	Outcome   string     `gorm:"type:varchar(50);not null" json:"outcome"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeMFAChallenge      = "mfa_challenge"
+	TokenPurposeEmailChange       = "email_change"
+	TokenPurposeInvitation        = "invitation"
+	TokenPurposeMagicLink         = "magic_link"
+)
+
+// OneTimeToken is a hashed, expiring, single-use token sent to a user by
+// email. Purpose keeps tokens for different flows from being interchangeable.
+type OneTimeToken struct {
+	BaseUUID
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Purpose   string     `gorm:"type:varchar(50);not null;index" json:"purpose"`
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (OneTimeToken) TableName() string {
+	return "one_time_tokens"
+}
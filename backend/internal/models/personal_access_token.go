@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token so it can
+// be told apart from a JWT and spotted by secret scanners
+const PersonalAccessTokenPrefix = "pat_"
+
+// PersonalAccessToken is a long-lived, scoped credential for machine
+// clients. Only the hash is stored; the token itself is shown once.
+type PersonalAccessToken struct {
+	BaseUUID
+	UserID uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name   string    `gorm:"type:varchar(100);not null" json:"name"`
+	// TokenPrefix is the start of the token, kept to help users identify it
+	TokenPrefix string `gorm:"type:varchar(16);not null" json:"token_prefix"`
+	TokenHash   string `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	// Scopes is a space separated list, as in OAuth 2.0
+	Scopes     string     `gorm:"type:text;not null" json:"scopes"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+func (t *PersonalAccessToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
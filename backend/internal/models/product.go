@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Product struct {
@@ -11,6 +13,7 @@ type Product struct {
 	Price       float64    `gorm:"type:decimal(10,2);not null" json:"price"`
 	Stock       int        `gorm:"not null;default:0" json:"stock"`
 	Category    *string    `gorm:"type:varchar(100)" json:"category"`
+	OwnerID     *uuid.UUID `gorm:"type:uuid;index" json:"owner_id"`
 	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   *time.Time `gorm:"index" json:"deleted_at,omitempty"`
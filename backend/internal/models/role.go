@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Built-in roles, created on startup. Admin always holds every
+// permission so it cannot be locked out; user is given to new accounts.
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+	RoleGuest = "guest"
+)
+
+// Role is referenced from users by name, so the name never changes
+type Role struct {
+	BaseUUID
+	Name        string       `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"`
+	Description string       `gorm:"type:varchar(255)" json:"description"`
+	Builtin     bool         `gorm:"not null;default:false" json:"builtin"`
+	Permissions []Permission `gorm:"many2many:role_permissions;constraint:OnDelete:CASCADE" json:"permissions"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+// PermissionNames lists the names of the role's permissions
+func (r *Role) PermissionNames() []string {
+	names := make([]string, len(r.Permissions))
+	for i, permission := range r.Permissions {
+		names[i] = permission.Name
+	}
+	return names
+}
+
+// Permission is a "resource:action" name. Builtin ones are declared by
+// the API contract and checked on its routes; others are for clients.
+type Permission struct {
+	BaseUUID
+	Name        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Description string    `gorm:"type:varchar(255)" json:"description"`
+	Builtin     bool      `gorm:"not null;default:false" json:"builtin"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
@@ -1,37 +1,73 @@
 package models
 
 import (
+	"backend/pkg/password"
 	"time"
+)
 
-	"golang.org/x/crypto/bcrypt"
+const (
+	UserStatusActive = "active"
+	// UserStatusPending users were invited and have not set a password yet
+	UserStatusPending = "pending"
 )
 
 type User struct {
 	BaseUUID
-	Name      string     `gorm:"type:varchar(255);not null" json:"name"`
-	Email     string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Password  string     `gorm:"type:varchar(255);not null" json:"-"`
-	Role      string     `gorm:"type:varchar(50);default:'user'" json:"role"`
-	IsActive  bool       `gorm:"default:true" json:"is_active"`
-	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt *time.Time `gorm:"index" json:"deleted_at,omitempty"`
+	Name     string `gorm:"type:varchar(255);not null" json:"name"`
+	Email    string `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Password string `gorm:"type:varchar(255);not null" json:"-"`
+	Role     string `gorm:"type:varchar(50);default:'user'" json:"role"`
+	IsActive bool   `gorm:"default:true" json:"is_active"`
+	Status   string `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	// ExternalID is the account's id in the directory that provisions it
+	// over SCIM
+	ExternalID      string     `gorm:"type:varchar(255);index" json:"external_id,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// PendingEmail replaces Email once confirmed from a link sent to it
+	PendingEmail string `gorm:"type:varchar(255)" json:"pending_email,omitempty"`
+	// TOTPSecret is set on enrollment and only takes effect once
+	// TOTPEnabledAt is set by a confirmed code
+	TOTPSecret    string     `gorm:"type:varchar(64)" json:"-"`
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at"`
+	// TOTPLastStep is the last accepted time step, so a code cannot be replayed
+	TOTPLastStep int64      `gorm:"not null;default:0" json:"-"`
+	TokenVersion int        `gorm:"not null;default:0" json:"token_version"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    *time.Time `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (User) TableName() string {
 	return "users"
 }
 
-func (u *User) HashPassword(password string) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsPending() bool {
+	return u.Status == UserStatusPending
+}
+
+func (u *User) IsMFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+func (u *User) HashPassword(plain string) error {
+	hashed, err := password.Hash(plain)
 	if err != nil {
 		return err
 	}
-	u.Password = string(hashedPassword)
+	u.Password = hashed
 	return nil
 }
 
-func (u *User) CheckPassword(password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
-	return err == nil
+// CheckPassword accepts argon2id and legacy bcrypt hashes
+func (u *User) CheckPassword(plain string) bool {
+	return password.Verify(plain, u.Password)
+}
+
+// PasswordNeedsRehash reports a bcrypt hash or outdated argon2 parameters
+func (u *User) PasswordNeedsRehash() bool {
+	return u.Password != "" && password.NeedsRehash(u.Password)
 }
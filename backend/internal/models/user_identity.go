@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect
+// provider. The issuer and subject pair is the provider's stable id for
+// that account; the email is only what it reported at link time.
+type UserIdentity struct {
+	BaseUUID
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Issuer      string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject" json:"issuer"`
+	Subject     string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_issuer_subject" json:"subject"`
+	Email       string     `gorm:"type:varchar(255)" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
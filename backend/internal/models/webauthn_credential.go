@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebAuthnCredential is a passkey registered by a user. The public key is
+// kept COSE encoded as the authenticator sent it. SignCount is the last
+// signature counter seen, used to spot cloned authenticators.
+type WebAuthnCredential struct {
+	BaseUUID
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name         string    `gorm:"type:varchar(100);not null" json:"name"`
+	CredentialID []byte    `gorm:"type:bytea;uniqueIndex;not null" json:"-"`
+	PublicKey    []byte    `gorm:"type:bytea;not null" json:"-"`
+	SignCount    int64     `gorm:"not null;default:0" json:"-"`
+	// AAGUID identifies the authenticator model; zero when not disclosed
+	AAGUID uuid.UUID `gorm:"type:uuid" json:"aaguid"`
+	// BackupEligible passkeys may be synced between the user's devices
+	BackupEligible bool       `gorm:"not null;default:false" json:"backup_eligible"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImpersonationFilter narrows the audit trail; zero values match all
+type ImpersonationFilter struct {
+	ActorID  uuid.UUID
+	TargetID uuid.UUID
+}
+
+type ImpersonationRepository interface {
+	Create(ctx context.Context, impersonation *models.Impersonation) error
+	FindAll(ctx context.Context, filter ImpersonationFilter, page, perPage int) ([]models.Impersonation, int64, error)
+}
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+func (r *impersonationRepository) Create(ctx context.Context, impersonation *models.Impersonation) error {
+	return r.db.WithContext(ctx).Create(impersonation).Error
+}
+
+// FindAll returns matching records, newest first
+func (r *impersonationRepository) FindAll(ctx context.Context, filter ImpersonationFilter, page, perPage int) ([]models.Impersonation, int64, error) {
+	var impersonations []models.Impersonation
+	var total int64
+
+	offset := (page - 1) * perPage
+
+	if err := r.filtered(ctx, filter).Model(&models.Impersonation{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.filtered(ctx, filter).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(perPage).
+		Find(&impersonations).Error
+
+	return impersonations, total, err
+}
+
+func (r *impersonationRepository) filtered(ctx context.Context, filter ImpersonationFilter) *gorm.DB {
+	query := r.db.WithContext(ctx)
+	if filter.ActorID != uuid.Nil {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != uuid.Nil {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	return query
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Invitation, error)
+	FindByHash(ctx context.Context, hash string) (*models.Invitation, error)
+	FindPending(ctx context.Context, page, perPage int) ([]models.Invitation, int64, error)
+	Update(ctx context.Context, invitation *models.Invitation) error
+	MarkAccepted(ctx context.Context, id uuid.UUID, hash string) (bool, error)
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+// Create and Update never write the preloaded user
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(invitation).Error
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.WithContext(ctx).Preload("User").First(&invitation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByHash(ctx context.Context, hash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.WithContext(ctx).Preload("User").Where("token_hash = ?", hash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPending returns invitations not accepted yet, expired ones
+// included, newest first
+func (r *invitationRepository) FindPending(ctx context.Context, page, perPage int) ([]models.Invitation, int64, error) {
+	var invitations []models.Invitation
+	var total int64
+
+	offset := (page - 1) * perPage
+
+	if err := r.db.WithContext(ctx).Model(&models.Invitation{}).Where("accepted_at IS NULL").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("accepted_at IS NULL").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(perPage).
+		Find(&invitations).Error
+
+	return invitations, total, err
+}
+
+func (r *invitationRepository) Update(ctx context.Context, invitation *models.Invitation) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(invitation).Error
+}
+
+// MarkAccepted consumes an unexpired invitation if hash is still its latest
+// token. It reports false when the link was resent, used or expired.
+func (r *invitationRepository) MarkAccepted(ctx context.Context, id uuid.UUID, hash string) (bool, error) {
+	now := time.Now().UTC()
+	result := r.db.WithContext(ctx).
+		Model(&models.Invitation{}).
+		Where("id = ? AND token_hash = ? AND accepted_at IS NULL AND expires_at > ?", id, hash, now).
+		Update("accepted_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *models.LoginAttempt) error
+	FindByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]models.LoginAttempt, int64, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(ctx context.Context, attempt *models.LoginAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+// FindByUser returns the user's attempts, newest first
+func (r *loginAttemptRepository) FindByUser(ctx context.Context, userID uuid.UUID, page, perPage int) ([]models.LoginAttempt, int64, error) {
+	var attempts []models.LoginAttempt
+	var total int64
+
+	offset := (page - 1) * perPage
+
+	if err := r.db.WithContext(ctx).Model(&models.LoginAttempt{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(perPage).
+		Find(&attempts).Error
+
+	return attempts, total, err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OneTimeTokenRepository interface {
+	Create(ctx context.Context, token *models.OneTimeToken) error
+	FindByHash(ctx context.Context, purpose, hash string) (*models.OneTimeToken, error)
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error
+}
+
+type oneTimeTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewOneTimeTokenRepository(db *gorm.DB) OneTimeTokenRepository {
+	return &oneTimeTokenRepository{db: db}
+}
+
+func (r *oneTimeTokenRepository) Create(ctx context.Context, token *models.OneTimeToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *oneTimeTokenRepository) FindByHash(ctx context.Context, purpose, hash string) (*models.OneTimeToken, error) {
+	var token models.OneTimeToken
+	err := r.db.WithContext(ctx).
+		Where("purpose = ? AND token_hash = ?", purpose, hash).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes an unused, unexpired token. It reports false when the
+// token was already used or expired in the meantime.
+func (r *oneTimeTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := time.Now().UTC()
+	result := r.db.WithContext(ctx).
+		Model(&models.OneTimeToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateForUser consumes every outstanding token of one purpose
+func (r *oneTimeTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OneTimeToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now().UTC()).Error
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type PermissionRepository interface {
+	Create(ctx context.Context, permission *models.Permission) error
+	FindAll(ctx context.Context) ([]models.Permission, error)
+	FindByName(ctx context.Context, name string) (*models.Permission, error)
+	FindByNames(ctx context.Context, names []string) ([]models.Permission, error)
+	Update(ctx context.Context, permission *models.Permission) error
+	Delete(ctx context.Context, permission *models.Permission) error
+}
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) Create(ctx context.Context, permission *models.Permission) error {
+	return r.db.WithContext(ctx).Create(permission).Error
+}
+
+func (r *permissionRepository) FindAll(ctx context.Context) ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *permissionRepository) FindByName(ctx context.Context, name string) (*models.Permission, error) {
+	var permission models.Permission
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&permission).Error
+	if err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *permissionRepository) FindByNames(ctx context.Context, names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.db.WithContext(ctx).Where("name IN ?", names).Order("name ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *permissionRepository) Update(ctx context.Context, permission *models.Permission) error {
+	return r.db.WithContext(ctx).Save(permission).Error
+}
+
+// Delete removes the permission and takes it away from every role
+func (r *permissionRepository) Delete(ctx context.Context, permission *models.Permission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE permission_id = ?", permission.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(permission).Error
+	})
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token *models.PersonalAccessToken) error
+	FindByHash(ctx context.Context, hash string) (*models.PersonalAccessToken, error)
+	FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error)
+	FindByIDForUser(ctx context.Context, id, userID uuid.UUID) (*models.PersonalAccessToken, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *personalAccessTokenRepository) FindByHash(ctx context.Context, hash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindActiveByUser returns unrevoked, unexpired tokens, newest first
+func (r *personalAccessTokenRepository) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now().UTC()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *personalAccessTokenRepository) FindByIDForUser(ctx context.Context, id, userID uuid.UUID) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *personalAccessTokenRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+func (r *personalAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+func (r *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now().UTC()).Error
+}
@@ -5,13 +5,15 @@ import (
 	"backend/internal/models"
 	"context"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type ProductRepository interface {
 	Create(ctx context.Context, product *models.Product) error
 	FindByID(ctx context.Context, id generated.IdParam) (*models.Product, error)
-	FindAll(ctx context.Context, page, perPage int) ([]models.Product, int64, error)
+	// FindAll lists products, only those owned by ownerID when it is set
+	FindAll(ctx context.Context, page, perPage int, ownerID *uuid.UUID) ([]models.Product, int64, error)
 	Update(ctx context.Context, product *models.Product) error
 	Delete(ctx context.Context, id generated.IdParam) error
 }
@@ -37,17 +39,22 @@ func (r *productRepository) FindByID(ctx context.Context, id generated.IdParam)
 	return &product, nil
 }
 
-func (r *productRepository) FindAll(ctx context.Context, page, perPage int) ([]models.Product, int64, error) {
+func (r *productRepository) FindAll(ctx context.Context, page, perPage int, ownerID *uuid.UUID) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
 	offset := (page - 1) * perPage
 
-	if err := r.db.WithContext(ctx).Model(&models.Product{}).Count(&total).Error; err != nil {
+	query := r.db.WithContext(ctx).Model(&models.Product{})
+	if ownerID != nil {
+		query = query.Where("owner_id = ?", ownerID.String())
+	}
+
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.WithContext(ctx).
+	err := query.
 		Offset(offset).
 		Limit(perPage).
 		Find(&products).Error
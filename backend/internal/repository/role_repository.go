@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Create(ctx context.Context, role *models.Role) error
+	FindAll(ctx context.Context) ([]models.Role, error)
+	FindByName(ctx context.Context, name string) (*models.Role, error)
+	UpdateDescription(ctx context.Context, role *models.Role) error
+	ReplacePermissions(ctx context.Context, role *models.Role, permissions []models.Permission) error
+	CountUsers(ctx context.Context, name string) (int64, error)
+	Delete(ctx context.Context, role *models.Role) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// Create also assigns the role's permissions, which must exist
+func (r *roleRepository) Create(ctx context.Context, role *models.Role) error {
+	return r.db.WithContext(ctx).Omit("Permissions.*").Create(role).Error
+}
+
+func (r *roleRepository) FindAll(ctx context.Context) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.WithContext(ctx).Preload("Permissions", orderByName).Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	err := r.db.WithContext(ctx).Preload("Permissions", orderByName).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) UpdateDescription(ctx context.Context, role *models.Role) error {
+	return r.db.WithContext(ctx).Model(role).Update("description", role.Description).Error
+}
+
+func (r *roleRepository) ReplacePermissions(ctx context.Context, role *models.Role, permissions []models.Permission) error {
+	return r.db.WithContext(ctx).Model(role).Association("Permissions").Replace(permissions)
+}
+
+func (r *roleRepository) CountUsers(ctx context.Context, name string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
+
+// Delete removes the role with its permission assignments
+func (r *roleRepository) Delete(ctx context.Context, role *models.Role) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(role).Error
+	})
+}
+
+func orderByName(db *gorm.DB) *gorm.DB {
+	return db.Order("name ASC")
+}
@@ -5,7 +5,9 @@ import (
 	"backend/internal/models"
 	"context"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -13,7 +15,11 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id generated.IdParam) (*models.User, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	FindAll(ctx context.Context, page, perPage int) ([]models.User, int64, error)
+	// FindMatching returns users matching cond, oldest first, and the
+	// total number of matches
+	FindMatching(ctx context.Context, cond clause.Expression, offset, limit int) ([]models.User, int64, error)
 	Update(ctx context.Context, user *models.User) error
+	AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
 	Delete(ctx context.Context, id generated.IdParam) error
 }
 
@@ -65,10 +71,52 @@ func (r *userRepository) FindAll(ctx context.Context, page, perPage int) ([]mode
 	return users, total, err
 }
 
+func (r *userRepository) FindMatching(ctx context.Context, cond clause.Expression, offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	matching := func(db *gorm.DB) *gorm.DB {
+		if cond != nil {
+			return db.Where(cond)
+		}
+		return db
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Scopes(matching).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if limit == 0 {
+		return users, total, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Scopes(matching).
+		Order("created_at ASC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error
+
+	return users, total, err
+}
+
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// AdvanceTOTPStep records step as the last accepted TOTP step. It reports
+// false when that step or a later one was already accepted, so concurrent
+// requests with the same code cannot both pass.
+func (r *userRepository) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id generated.IdParam) error {
 	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error
 }
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"backend/internal/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebAuthnCredentialRepository interface {
+	Create(ctx context.Context, credential *models.WebAuthnCredential) error
+	FindByCredentialID(ctx context.Context, credentialID []byte) (*models.WebAuthnCredential, error)
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]models.WebAuthnCredential, error)
+	FindByIDForUser(ctx context.Context, id, userID uuid.UUID) (*models.WebAuthnCredential, error)
+	// RecordUse stores the new signature counter and the time of use
+	RecordUse(ctx context.Context, id uuid.UUID, signCount uint32) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type webAuthnCredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewWebAuthnCredentialRepository(db *gorm.DB) WebAuthnCredentialRepository {
+	return &webAuthnCredentialRepository{db: db}
+}
+
+func (r *webAuthnCredentialRepository) Create(ctx context.Context, credential *models.WebAuthnCredential) error {
+	return r.db.WithContext(ctx).Create(credential).Error
+}
+
+func (r *webAuthnCredentialRepository) FindByCredentialID(ctx context.Context, credentialID []byte) (*models.WebAuthnCredential, error) {
+	var credential models.WebAuthnCredential
+	err := r.db.WithContext(ctx).
+		Where("credential_id = ?", credentialID).
+		First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// FindByUser returns the user's passkeys, oldest first
+func (r *webAuthnCredentialRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]models.WebAuthnCredential, error) {
+	var credentials []models.WebAuthnCredential
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&credentials).Error
+	return credentials, err
+}
+
+func (r *webAuthnCredentialRepository) FindByIDForUser(ctx context.Context, id, userID uuid.UUID) (*models.WebAuthnCredential, error) {
+	var credential models.WebAuthnCredential
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (r *webAuthnCredentialRepository) RecordUse(ctx context.Context, id uuid.UUID, signCount uint32) error {
+	return r.db.WithContext(ctx).
+		Model(&models.WebAuthnCredential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sign_count":   int64(signCount),
+			"last_used_at": time.Now().UTC(),
+		}).Error
+}
+
+func (r *webAuthnCredentialRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.WebAuthnCredential{}, "id = ?", id).Error
+}
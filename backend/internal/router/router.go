@@ -4,22 +4,43 @@ import (
 	"backend/internal/generated"
 	"backend/internal/handlers"
 	"backend/internal/middleware"
+	"backend/internal/service"
+	jwt "backend/pkg"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
-	handler *handlers.CombinedHandler
+	handler     *handlers.CombinedHandler
+	tokens      service.TokenService
+	permissions service.PermissionResolver
+	security    middleware.SecurityOptions
+	corsOrigins []string
+	scim        *handlers.SCIMHandler
+	scimToken   string
 }
 
-func New(handler *handlers.CombinedHandler) *Router {
+func New(handler *handlers.CombinedHandler, tokens service.TokenService, permissions service.PermissionResolver, security middleware.SecurityOptions, corsOrigins []string) *Router {
 	return &Router{
-		handler: handler,
+		handler:     handler,
+		tokens:      tokens,
+		permissions: permissions,
+		security:    security,
+		corsOrigins: corsOrigins,
 	}
 }
 
-func (r *Router) Setup(isDevelopment bool) *gin.Engine {
+// WithSCIM serves SCIM 2.0 provisioning at /scim/v2 for callers bearing token
+func (r *Router) WithSCIM(handler *handlers.SCIMHandler, token string) *Router {
+	r.scim = handler
+	r.scimToken = token
+	return r
+}
+
+// Setup builds the engine and fails if an API route has no security rules
+func (r *Router) Setup(isDevelopment bool) (*gin.Engine, error) {
 	if !isDevelopment {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -28,7 +49,7 @@ func (r *Router) Setup(isDevelopment bool) *gin.Engine {
 
 	// Global middleware
 	router.Use(middleware.Recovery())
-	router.Use(middleware.CORS())
+	router.Use(middleware.CORS(r.corsOrigins))
 	router.Use(middleware.RequestID())
 	router.Use(gin.Logger())
 	router.Use(middleware.RateLimit(100, time.Minute))
@@ -37,17 +58,55 @@ func (r *Router) Setup(isDevelopment bool) *gin.Engine {
 	router.GET("/health", r.healthCheck)
 	router.GET("/", r.welcome)
 
-	// API v1 group with RBAC middleware
-	v1 := router.Group("/api/v1")
+	// Public signing keys so other services can verify our tokens
+	router.GET("/.well-known/jwks.json", r.jwks)
+
+	// API v1 group with RBAC middleware, mounted at the spec's server path
+	v1 := router.Group(generated.BasePath)
 
 	// Apply OpenAPI-based RBAC middleware
-	v1.Use(middleware.OpenAPISecurityMiddleware())
+	v1.Use(middleware.OpenAPISecurityMiddleware(r.tokens, r.permissions, r.security))
 
 	// Register oapi-codegen generated handlers
 	// Security is now handled by OpenAPISecurityMiddleware
+	existing := router.Routes()
 	generated.RegisterHandlers(v1, r.handler)
 
-	return router
+	// The middleware denies operations missing from the spec, so catch
+	// them here rather than on the first request
+	if err := middleware.CheckRouteSecurity(addedRoutes(existing, router.Routes())); err != nil {
+		return nil, err
+	}
+
+	// SCIM has its own media type and errors, so it lives outside /api/v1
+	if r.scim != nil {
+		scim := router.Group("/scim/v2", middleware.SCIMAuth(r.scimToken))
+		scim.GET("/ServiceProviderConfig", r.scim.ServiceProviderConfig)
+		scim.GET("/Users", r.scim.ListUsers)
+		scim.POST("/Users", r.scim.CreateUser)
+		scim.GET("/Users/:id", r.scim.GetUser)
+		scim.PUT("/Users/:id", r.scim.ReplaceUser)
+		scim.PATCH("/Users/:id", r.scim.PatchUser)
+		scim.DELETE("/Users/:id", r.scim.DeleteUser)
+	}
+
+	return router, nil
+}
+
+// addedRoutes lists the routes in after that are not in before
+func addedRoutes(before, after gin.RoutesInfo) gin.RoutesInfo {
+	seen := make(map[string]bool, len(before))
+	for _, route := range before {
+		seen[route.Method+" "+route.Path] = true
+	}
+
+	var added gin.RoutesInfo
+	for _, route := range after {
+		if !seen[route.Method+" "+route.Path] {
+			added = append(added, route)
+		}
+	}
+	return added
 }
 
 func (r *Router) healthCheck(c *gin.Context) {
@@ -57,15 +116,30 @@ func (r *Router) healthCheck(c *gin.Context) {
 	})
 }
 
+func (r *Router) jwks(c *gin.Context) {
+	set, err := jwt.PublicJWKS()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, generated.Error{
+			Message: "signing keys unavailable",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
+
 func (r *Router) welcome(c *gin.Context) {
 	c.JSON(200, gin.H{
 		"message": "Welcome to Backend API",
 		"version": "1.0.0",
 		"endpoints": gin.H{
 			"health":   "/health",
-			"auth":     "/api/v1/auth",
-			"users":    "/api/v1/users",
-			"products": "/api/v1/products",
+			"jwks":     "/.well-known/jwks.json",
+			"auth":     generated.BasePath + "/auth",
+			"users":    generated.BasePath + "/users",
+			"products": generated.BasePath + "/products",
+			"roles":    generated.BasePath + "/roles",
 		},
 	})
 }
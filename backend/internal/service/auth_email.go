@@ -0,0 +1,103 @@
+package service
+
+import (
+	"backend/internal/generated"
+	"backend/internal/mailer"
+	"backend/internal/models"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailNotVerified         = errors.New("email address is not verified")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+)
+
+func (s *authService) VerifyEmail(ctx context.Context, req *generated.VerifyEmailRequest) error {
+	claims, err := jwt.ParseActionToken(req.Token, models.TokenPurposeEmailVerification)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	// A link sent to a previous address must not verify the current one
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return ErrInvalidVerificationToken
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	return s.markEmailVerified(ctx, user)
+}
+
+func (s *authService) markEmailVerified(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+func (s *authService) ResendVerification(ctx context.Context, req *generated.ResendVerificationRequest) error {
+	user, err := s.userRepo.FindByEmail(ctx, string(req.Email))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if !user.IsActive || user.IsPending() || user.IsEmailVerified() {
+		return nil
+	}
+
+	return s.sendVerification(user)
+}
+
+// sendVerification mails a signed link bound to the user's current address
+func (s *authService) sendVerification(user *models.User) error {
+	token, err := jwt.GenerateActionToken(jwt.ActionParams{
+		Purpose: models.TokenPurposeEmailVerification,
+		Subject: user.ID.String(),
+		Email:   user.Email,
+		TTL:     s.opts.EmailVerificationTTL,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.opts.AppURL, url.QueryEscape(token))
+	go s.sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s\n\nIf you did not create an account, you can ignore this email.\n",
+			user.Name, s.opts.EmailVerificationTTL, link,
+		),
+	})
+
+	return nil
+}
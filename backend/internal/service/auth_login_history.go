@@ -0,0 +1,60 @@
+package service
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// maxUserAgentLength matches the user_agent columns of login_attempts and
+// impersonations
+const maxUserAgentLength = 512
+
+// ClientInfo identifies where a login attempt came from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// completeLogin runs once every factor has been checked
+func (s *authService) completeLogin(ctx context.Context, user *models.User, client ClientInfo) (*generated.AuthResponse, error) {
+	if err := s.throttle.Reset(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	response, err := s.issueTokens(ctx, user, uuid.Must(uuid.NewV7()))
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeSuccess)
+	return response, nil
+}
+
+// recordAttempt writes the login history. A failed write is logged but
+// never blocks the login itself.
+func (s *authService) recordAttempt(ctx context.Context, user *models.User, email string, client ClientInfo, outcome string) {
+	attempt := &models.LoginAttempt{
+		Email:     email,
+		IPAddress: client.IP,
+		UserAgent: client.UserAgent,
+		Outcome:   outcome,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	if runes := []rune(attempt.UserAgent); len(runes) > maxUserAgentLength {
+		attempt.UserAgent = string(runes[:maxUserAgentLength])
+	}
+
+	if err := s.loginAttemptRepo.Create(ctx, attempt); err != nil {
+		log.Printf("Warning: failed to record login attempt: %v", err)
+	}
+}
+
+func (s *authService) LoginHistory(ctx context.Context, userID uuid.UUID, page, perPage int) ([]models.LoginAttempt, int64, error) {
+	return s.loginAttemptRepo.FindByUser(ctx, userID, page, perPage)
+}
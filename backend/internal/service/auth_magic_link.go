@@ -0,0 +1,210 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/mailer"
+	"backend/internal/models"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// magicLinkRequestLimit links may be requested per address every
+	// magicLinkRequestWindow
+	magicLinkRequestLimit  = 3
+	magicLinkRequestWindow = 15 * time.Minute
+)
+
+var (
+	ErrMagicLinkDisabled = errors.New("login links are not enabled")
+	ErrInvalidMagicLink  = errors.New("invalid or expired login link")
+)
+
+// RateLimitedError rejects a request made before RetryAfter has passed
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return "too many requests, try again later"
+}
+
+// magicLinkRequests counts the links requested for one address in the
+// current window
+type magicLinkRequests struct {
+	Count        int       `json:"count"`
+	WindowEndsAt time.Time `json:"window_ends_at"`
+}
+
+func magicLinkRequestsKey(email string) string {
+	return fmt.Sprintf("auth:magic_link:requests:%s", strings.ToLower(strings.TrimSpace(email)))
+}
+
+// RequestMagicLink mails a signed login link whose hash is stored as a
+// one-time token, so it works once. Like ForgotPassword it answers the
+// same way for unknown addresses.
+func (s *authService) RequestMagicLink(ctx context.Context, req *generated.MagicLinkRequest) error {
+	if !s.opts.MagicLinkEnabled {
+		return ErrMagicLinkDisabled
+	}
+
+	email := string(req.Email)
+
+	// A locked address gets no links, but requesting one is not a failed
+	// login: anyone can ask for a link to any address, so counting them
+	// would let strangers lock accounts without knowing the password
+	if err := s.throttle.Check(ctx, email); err != nil {
+		return err
+	}
+	if err := s.limitMagicLinkRequests(ctx, email); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if !user.IsActive || user.IsPending() {
+		return nil
+	}
+
+	// Only the most recent link is valid
+	if err := s.oneTimeTokenRepo.InvalidateForUser(ctx, user.ID, models.TokenPurposeMagicLink); err != nil {
+		return err
+	}
+
+	token, err := jwt.GenerateActionToken(jwt.ActionParams{
+		Purpose: models.TokenPurposeMagicLink,
+		Subject: user.ID.String(),
+		Email:   user.Email,
+		TTL:     s.opts.MagicLinkTTL,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.oneTimeTokenRepo.Create(ctx, &models.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeMagicLink,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.opts.MagicLinkTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/magic-link?token=%s", s.opts.AppURL, url.QueryEscape(token))
+	go s.sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to log in. It expires in %s and works once.\n\n%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			user.Name, s.opts.MagicLinkTTL, link,
+		),
+	})
+
+	return nil
+}
+
+// limitMagicLinkRequests keeps links from being used to flood a mailbox.
+// Like the login throttle it is read-modify-write, so concurrent requests
+// may be counted once.
+func (s *authService) limitMagicLinkRequests(ctx context.Context, email string) error {
+	key := magicLinkRequestsKey(email)
+	var state magicLinkRequests
+	if err := s.store.Get(ctx, key, &state); err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		return err
+	}
+
+	now := time.Now()
+	if !now.Before(state.WindowEndsAt) {
+		state = magicLinkRequests{WindowEndsAt: now.Add(magicLinkRequestWindow)}
+	}
+	if state.Count >= magicLinkRequestLimit {
+		return &RateLimitedError{RetryAfter: state.WindowEndsAt.Sub(now)}
+	}
+
+	state.Count++
+	return s.store.Set(ctx, key, state, state.WindowEndsAt.Sub(now))
+}
+
+// RedeemMagicLink answers like Login: tokens, or an MFA challenge
+func (s *authService) RedeemMagicLink(ctx context.Context, req *generated.MagicLinkVerifyRequest, client ClientInfo) (*generated.AuthResponse, *generated.MfaChallengeResponse, error) {
+	if !s.opts.MagicLinkEnabled {
+		return nil, nil, ErrMagicLinkDisabled
+	}
+
+	claims, err := jwt.ParseActionToken(req.Token, models.TokenPurposeMagicLink)
+	if err != nil {
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	stored, err := s.oneTimeTokenRepo.FindByHash(ctx, models.TokenPurposeMagicLink, hashToken(req.Token))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, ErrInvalidMagicLink
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, ErrInvalidMagicLink
+		}
+		return nil, nil, err
+	}
+
+	// The link was mailed to the address the account had at the time
+	if claims.Subject != user.ID.String() || !strings.EqualFold(claims.Email, user.Email) {
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	if err := s.throttle.Check(ctx, user.Email); err != nil {
+		var locked *LockedOutError
+		if errors.As(err, &locked) {
+			s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeLockedOut)
+		}
+		return nil, nil, err
+	}
+
+	used, err := s.oneTimeTokenRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !used {
+		return nil, nil, ErrInvalidMagicLink
+	}
+
+	if !user.IsActive || user.IsPending() {
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeAccountDisabled)
+		return nil, nil, ErrAccountInactive
+	}
+
+	// Opening the link proves the address
+	if !user.IsEmailVerified() {
+		if err := s.markEmailVerified(ctx, user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if user.IsMFAEnabled() {
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeMFAChallenge)
+		challenge, err := s.mfaChallenge(user)
+		return nil, challenge, err
+	}
+
+	response, err := s.completeLogin(ctx, user, client)
+	return response, nil, err
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestMagicLinkRequestsDoNotLockOutLogin(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryCache()
+	throttle := NewLoginThrottle(store, LoginThrottleOptions{MaxAttempts: 2, LockoutDuration: time.Hour})
+	s := &authService{
+		userRepo: newFakeUserRepo(),
+		throttle: throttle,
+		store:    store,
+		opts:     AuthOptions{MagicLinkEnabled: true, MagicLinkTTL: time.Minute},
+	}
+	req := &generated.MagicLinkRequest{Email: openapi_types.Email("Jane@Example.com")}
+
+	for i := 0; i < magicLinkRequestLimit; i++ {
+		if err := s.RequestMagicLink(ctx, req); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+
+	var limited *RateLimitedError
+	if err := s.RequestMagicLink(ctx, req); !errors.As(err, &limited) {
+		t.Fatalf("err = %v, want RateLimitedError", err)
+	}
+	if limited.RetryAfter <= 0 || limited.RetryAfter > magicLinkRequestWindow {
+		t.Fatalf("RetryAfter = %s", limited.RetryAfter)
+	}
+
+	// The limit is per address and ignores case
+	other := &generated.MagicLinkRequest{Email: openapi_types.Email("john@example.com")}
+	if err := s.RequestMagicLink(ctx, other); err != nil {
+		t.Fatalf("another address: %v", err)
+	}
+	if err := s.RequestMagicLink(ctx, &generated.MagicLinkRequest{Email: "jane@example.com"}); !errors.As(err, &limited) {
+		t.Fatalf("err = %v, want RateLimitedError", err)
+	}
+
+	// None of it counts as a failed login
+	if err := throttle.Check(ctx, "jane@example.com"); err != nil {
+		t.Fatalf("link requests locked the login: %v", err)
+	}
+}
+
+func TestMagicLinkRequestsWindowExpires(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryCache()
+	s := &authService{store: store}
+
+	// A window that has ended starts over
+	if err := store.Set(ctx, magicLinkRequestsKey("jane@example.com"), magicLinkRequests{
+		Count:        magicLinkRequestLimit,
+		WindowEndsAt: time.Now().Add(-time.Second),
+	}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.limitMagicLinkRequests(ctx, "jane@example.com"); err != nil {
+		t.Fatalf("limit survived its window: %v", err)
+	}
+}
+
+func TestMagicLinkRequestsRespectLockout(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryCache()
+	throttle := NewLoginThrottle(store, LoginThrottleOptions{MaxAttempts: 1, LockoutDuration: time.Hour})
+	s := &authService{
+		userRepo: newFakeUserRepo(),
+		throttle: throttle,
+		store:    store,
+		opts:     AuthOptions{MagicLinkEnabled: true},
+	}
+	if err := throttle.RecordFailure(ctx, "jane@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	var locked *LockedOutError
+	if err := s.RequestMagicLink(ctx, &generated.MagicLinkRequest{Email: "jane@example.com"}); !errors.As(err, &locked) {
+		t.Fatalf("err = %v, want LockedOutError", err)
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	jwt "backend/pkg"
+	"backend/pkg/totp"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	mfaChallengeTTL = 5 * time.Minute
+	// maxMFAAttempts bounds code guesses per challenge; a new challenge
+	// needs the password again
+	maxMFAAttempts    = 5
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrInvalidMFAChallenge = errors.New("invalid or expired MFA challenge")
+	ErrInvalidMFACode      = errors.New("invalid authentication code")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled      = errors.New("two-factor authentication enrollment has not been started")
+	ErrMFARequired         = errors.New("two-factor authentication is required for your role")
+)
+
+func mfaAttemptsKey(jti string) string {
+	return fmt.Sprintf("auth:mfa:attempts:%s", jti)
+}
+
+// mfaChallenge is what Login returns instead of tokens once the password
+// checked out for a user with TOTP enabled
+func (s *authService) mfaChallenge(user *models.User) (*generated.MfaChallengeResponse, error) {
+	token, err := jwt.GenerateActionToken(jwt.ActionParams{
+		Purpose: models.TokenPurposeMFAChallenge,
+		Subject: user.ID.String(),
+		Email:   user.Email,
+		TTL:     mfaChallengeTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.MfaChallengeResponse{
+		MfaToken:  token,
+		ExpiresIn: int(mfaChallengeTTL.Seconds()),
+	}, nil
+}
+
+func (s *authService) VerifyMFA(ctx context.Context, req *generated.MfaVerifyRequest, client ClientInfo) (*generated.AuthResponse, error) {
+	claims, err := jwt.ParseActionToken(req.MfaToken, models.TokenPurposeMFAChallenge)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	attemptsKey := mfaAttemptsKey(claims.ID)
+	var attempts int
+	if err := s.store.Get(ctx, attemptsKey, &attempts); err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, err
+	}
+	if attempts >= maxMFAAttempts {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+
+	if !user.IsActive || !user.IsMFAEnabled() {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	// Code guesses count against the same per-account budget as passwords,
+	// otherwise fresh challenges would allow unlimited guessing
+	if err := s.throttle.Check(ctx, user.Email); err != nil {
+		var locked *LockedOutError
+		if errors.As(err, &locked) {
+			s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeLockedOut)
+		}
+		return nil, err
+	}
+
+	// Entries only need to outlive the challenge itself
+	ttl := time.Until(claims.ExpiresAt.Time)
+
+	ok, err := s.checkSecondFactor(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.store.Set(ctx, attemptsKey, attempts+1, ttl); err != nil {
+			return nil, err
+		}
+		if err := s.throttle.RecordFailure(ctx, user.Email); err != nil {
+			return nil, err
+		}
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeInvalidMFACode)
+		return nil, ErrInvalidMFACode
+	}
+
+	// A challenge is good for one login only
+	if err := s.store.Set(ctx, attemptsKey, maxMFAAttempts, ttl); err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+func (s *authService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (*generated.TotpEnrollResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// Restarting enrollment replaces a secret that was never confirmed
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &generated.TotpEnrollResponse{
+		Secret:     secret,
+		OtpauthUrl: totp.URL(s.opts.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *authService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, req *generated.TotpCodeRequest) (*generated.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, strings.TrimSpace(req.Code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(ctx, user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	user.TOTPLastStep = step
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.tokens.InvalidatePrincipal(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return &generated.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *authService) DisableTOTP(ctx context.Context, userID uuid.UUID, req *generated.TotpCodeRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(s.opts.MFARequiredRoles, user.Role) {
+		return ErrMFARequired
+	}
+	if !user.IsMFAEnabled() {
+		return ErrMFANotEnrolled
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabledAt = nil
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.recoveryCodeRepo.DeleteForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+// checkSecondFactor accepts a TOTP code once per time step, or consumes
+// a recovery code
+func (s *authService) checkSecondFactor(ctx context.Context, user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if step, ok := totp.Validate(user.TOTPSecret, code, time.Now()); ok {
+		advanced, err := s.userRepo.AdvanceTOTPStep(ctx, user.ID, step)
+		if err != nil || !advanced {
+			return false, err
+		}
+		user.TOTPLastStep = step
+		return true, nil
+	}
+
+	return s.recoveryCodeRepo.Use(ctx, user.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// generateRecoveryCodes returns codes formatted for humans (XXXX-XXXX-XXXX-XXXX,
+// 80 random bits each) and the hashes to store
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := base32.StdEncoding.EncodeToString(b)
+
+		codes[i] = fmt.Sprintf("%s-%s-%s-%s", raw[0:4], raw[4:8], raw[8:12], raw[12:16])
+		hashes[i] = hashToken(raw)
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts codes typed in lower case or without dashes
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
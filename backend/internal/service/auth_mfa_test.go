@@ -0,0 +1,162 @@
+package service
+
+import (
+	"backend/internal/models"
+	"backend/pkg/totp"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newMFATestService(t *testing.T) (*authService, *models.User) {
+	t.Helper()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	user := activeUser()
+	user.TOTPSecret = secret
+	user.TOTPEnabledAt = &now
+
+	return &authService{
+		userRepo:         newFakeUserRepo(user),
+		recoveryCodeRepo: &fakeRecoveryCodeRepo{},
+	}, user
+}
+
+func TestCheckSecondFactorRefusesReplayedTOTPCode(t *testing.T) {
+	ctx := context.Background()
+	s, user := newMFATestService(t)
+
+	code, err := totp.Code(user.TOTPSecret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user, code)
+	if err != nil || !ok {
+		t.Fatalf("first use: got %v, %v", ok, err)
+	}
+
+	stored, err := s.userRepo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.TOTPLastStep == 0 {
+		t.Fatal("accepted step was not stored")
+	}
+
+	// The same code within its step is a replay
+	ok, err = s.checkSecondFactor(ctx, stored, code)
+	if err != nil || ok {
+		t.Fatalf("replay: got %v, %v", ok, err)
+	}
+}
+
+func TestCheckSecondFactorAcceptsConcurrentCodeOnce(t *testing.T) {
+	ctx := context.Background()
+	s, user := newMFATestService(t)
+
+	code, err := totp.Code(user.TOTPSecret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every request loaded the user before any of them accepted the code
+	loaded := make([]models.User, 8)
+	for i := range loaded {
+		loaded[i] = *user
+	}
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	for i := range loaded {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := s.checkSecondFactor(ctx, &loaded[i], code)
+			if err != nil {
+				t.Error(err)
+			}
+			if ok {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if accepted.Load() != 1 {
+		t.Fatalf("code accepted %d times, want once", accepted.Load())
+	}
+}
+
+func TestCheckSecondFactorKeepsOtherColumns(t *testing.T) {
+	ctx := context.Background()
+	s, user := newMFATestService(t)
+	loaded := *user
+
+	// Changed by another request after this one loaded the user
+	renamed := *user
+	renamed.Name = "Jane Roe"
+	if err := s.userRepo.Update(ctx, &renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := totp.Code(user.TOTPSecret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.checkSecondFactor(ctx, &loaded, code); err != nil || !ok {
+		t.Fatalf("got %v, %v", ok, err)
+	}
+
+	stored, err := s.userRepo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "Jane Roe" || stored.TOTPLastStep == 0 {
+		t.Fatalf("stored name %q, step %d", stored.Name, stored.TOTPLastStep)
+	}
+}
+
+func TestRecoveryCodesAreSingleUse(t *testing.T) {
+	ctx := context.Background()
+	s, user := newMFATestService(t)
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), recoveryCodeCount)
+	}
+	if err := s.recoveryCodeRepo.ReplaceForUser(ctx, user.ID, hashes); err != nil {
+		t.Fatal(err)
+	}
+
+	// Codes are accepted as typed by people: lower case, without dashes
+	typed := strings.ToLower(strings.ReplaceAll(codes[0], "-", ""))
+	ok, err := s.checkSecondFactor(ctx, user, typed)
+	if err != nil || !ok {
+		t.Fatalf("first use: got %v, %v", ok, err)
+	}
+
+	ok, err = s.checkSecondFactor(ctx, user, codes[0])
+	if err != nil || ok {
+		t.Fatalf("second use: got %v, %v", ok, err)
+	}
+
+	// Other codes stay valid
+	ok, err = s.checkSecondFactor(ctx, user, codes[1])
+	if err != nil || !ok {
+		t.Fatalf("another code: got %v, %v", ok, err)
+	}
+
+	ok, err = s.checkSecondFactor(ctx, user, "AAAA-BBBB-CCCC-DDDD")
+	if err != nil || ok {
+		t.Fatalf("unknown code: got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/pkg/oidc"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// oidcStateTTL bounds how long a user may take at the provider
+const oidcStateTTL = 10 * time.Minute
+
+var (
+	ErrOIDCDisabled         = errors.New("single sign-on is not enabled")
+	ErrInvalidOIDCState     = errors.New("invalid or expired sign-in request")
+	ErrOIDCLoginFailed      = errors.New("single sign-on failed")
+	ErrOIDCEmailNotVerified = errors.New("the identity provider has not verified this email address")
+	ErrOIDCNoAccount        = errors.New("no account exists for this email address")
+)
+
+// oidcFlow is kept server side between StartOIDCLogin and the callback
+type oidcFlow struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+func oidcStateKey(state string) string {
+	return fmt.Sprintf("auth:oidc:state:%s", state)
+}
+
+func (s *authService) StartOIDCLogin(ctx context.Context) (*generated.OidcAuthorizationResponse, error) {
+	if s.sso == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	req, err := s.sso.NewAuthRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := oidcFlow{Nonce: req.Nonce, CodeVerifier: req.CodeVerifier}
+	if err := s.store.Set(ctx, oidcStateKey(req.State), flow, oidcStateTTL); err != nil {
+		return nil, err
+	}
+
+	return &generated.OidcAuthorizationResponse{
+		AuthorizationUrl: req.URL,
+		State:            req.State,
+	}, nil
+}
+
+func (s *authService) CompleteOIDCLogin(ctx context.Context, req *generated.OidcCallbackRequest, client ClientInfo) (*generated.AuthResponse, *generated.MfaChallengeResponse, error) {
+	if s.sso == nil {
+		return nil, nil, ErrOIDCDisabled
+	}
+
+	// Each state is good for one callback
+	key := oidcStateKey(req.State)
+	var flow oidcFlow
+	if err := s.store.Get(ctx, key, &flow); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, nil, ErrInvalidOIDCState
+		}
+		return nil, nil, err
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		return nil, nil, err
+	}
+
+	idToken, err := s.sso.Exchange(ctx, req.Code, flow.CodeVerifier, flow.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrExchange) || errors.Is(err, oidc.ErrInvalidIDToken) {
+			log.Printf("Warning: oidc login rejected: %v", err)
+			return nil, nil, ErrOIDCLoginFailed
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.resolveIdentity(ctx, idToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Invited users join through the invitation link
+	if !user.IsActive || user.IsPending() {
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeAccountDisabled)
+		return nil, nil, ErrAccountInactive
+	}
+
+	// The provider stands in for the password only; a second factor
+	// enrolled here is still required
+	if user.IsMFAEnabled() {
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeMFAChallenge)
+		challenge, err := s.mfaChallenge(user)
+		return nil, challenge, err
+	}
+
+	response, err := s.completeLogin(ctx, user, client)
+	return response, nil, err
+}
+
+// resolveIdentity finds the user behind an ID token: by a linked identity
+// first, then by verified email, and finally by creating an account when
+// auto provisioning is on
+func (s *authService) resolveIdentity(ctx context.Context, idToken *oidc.IDToken) (*models.User, error) {
+	identity, err := s.identityRepo.FindBySubject(ctx, idToken.Issuer, idToken.Subject)
+	if err == nil {
+		if err := s.identityRepo.TouchLastLogin(ctx, identity.ID); err != nil {
+			return nil, err
+		}
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOIDCNoAccount
+		}
+		return user, err
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	// Linking on an unverified email would let anyone who can set that
+	// claim at their provider take over the account
+	email := strings.TrimSpace(idToken.Email)
+	if email == "" || !idToken.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if err := s.claimUnverifiedAccount(ctx, user); err != nil {
+			return nil, err
+		}
+	case err == gorm.ErrRecordNotFound:
+		if !s.opts.OIDCAutoProvision {
+			return nil, ErrOIDCNoAccount
+		}
+		if user, err = s.provisionUser(ctx, idToken, email); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.identityRepo.Create(ctx, &models.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      idToken.Issuer,
+		Subject:     idToken.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// claimUnverifiedAccount handles linking to an account whose owner never
+// proved the address. Whoever registered it may not be the person the
+// provider vouches for, so their password and sessions are dropped.
+func (s *authService) claimUnverifiedAccount(ctx context.Context, user *models.User) error {
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	user.Password = ""
+	user.TokenVersion++
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.tokens.RevokeUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+// provisionUser creates an account without a password; the user can set
+// one later through the password reset flow
+func (s *authService) provisionUser(ctx context.Context, idToken *oidc.IDToken, email string) (*models.User, error) {
+	name := strings.TrimSpace(idToken.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Name:            name,
+		Email:           email,
+		Role:            "user",
+		IsActive:        true,
+		EmailVerifiedAt: &now,
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
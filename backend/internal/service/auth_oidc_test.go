@@ -0,0 +1,150 @@
+package service
+
+import (
+	"backend/internal/models"
+	"backend/pkg/oidc"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func newOIDCTestService(users ...*models.User) (*authService, *fakeUserRepo, *fakeIdentityRepo, *fakeTokenService) {
+	userRepo := newFakeUserRepo(users...)
+	identities := &fakeIdentityRepo{}
+	tokens := &fakeTokenService{}
+	return &authService{
+		userRepo:     userRepo,
+		identityRepo: identities,
+		tokens:       tokens,
+	}, userRepo, identities, tokens
+}
+
+func verifiedIDToken() *oidc.IDToken {
+	return &oidc.IDToken{
+		Issuer:        testIssuer,
+		Subject:       "user-123",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	}
+}
+
+func TestResolveIdentityLinksExistingAccount(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	verifiedAt := time.Now().Add(-time.Hour)
+	user.EmailVerifiedAt = &verifiedAt
+	user.Password = "hash"
+	s, userRepo, identities, tokens := newOIDCTestService(user)
+
+	got, err := s.resolveIdentity(ctx, verifiedIDToken())
+	if err != nil {
+		t.Fatalf("resolveIdentity: %v", err)
+	}
+	if got.ID != user.ID {
+		t.Fatalf("resolved user %s, want %s", got.ID, user.ID)
+	}
+
+	identity, err := identities.FindBySubject(ctx, testIssuer, "user-123")
+	if err != nil {
+		t.Fatalf("identity was not linked: %v", err)
+	}
+	if identity.UserID != user.ID {
+		t.Fatalf("identity linked to %s, want %s", identity.UserID, user.ID)
+	}
+
+	// The owner had proved the address, so nothing of theirs is dropped
+	stored, _ := userRepo.FindByID(ctx, user.ID)
+	if stored.Password != "hash" || len(tokens.sessionsRevoked) != 0 {
+		t.Fatal("linking to a verified account must keep its password and sessions")
+	}
+
+	// Later logins go through the link even when the email changes
+	idToken := verifiedIDToken()
+	idToken.Email = "jane@new-domain.example.com"
+	idToken.EmailVerified = false
+	if got, err := s.resolveIdentity(ctx, idToken); err != nil || got.ID != user.ID {
+		t.Fatalf("linked login: user %v, err %v", got, err)
+	}
+	if len(identities.identities) != 1 {
+		t.Fatalf("%d identities, want 1", len(identities.identities))
+	}
+}
+
+func TestResolveIdentityClaimsUnverifiedAccount(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	user.Password = "hash"
+	s, userRepo, _, tokens := newOIDCTestService(user)
+
+	if _, err := s.resolveIdentity(ctx, verifiedIDToken()); err != nil {
+		t.Fatalf("resolveIdentity: %v", err)
+	}
+
+	stored, _ := userRepo.FindByID(ctx, user.ID)
+	if !stored.IsEmailVerified() {
+		t.Fatal("email was not marked verified")
+	}
+	if stored.Password != "" {
+		t.Fatal("password of the unverified registration was kept")
+	}
+	if stored.TokenVersion != user.TokenVersion+1 {
+		t.Fatal("token version was not bumped")
+	}
+	if len(tokens.sessionsRevoked) != 1 || tokens.sessionsRevoked[0] != user.ID {
+		t.Fatalf("sessions revoked for %v, want %s", tokens.sessionsRevoked, user.ID)
+	}
+}
+
+func TestResolveIdentityRequiresVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	s, userRepo, identities, _ := newOIDCTestService(user)
+	s.opts.OIDCAutoProvision = true
+
+	for _, edit := range []func(*oidc.IDToken){
+		func(tok *oidc.IDToken) { tok.EmailVerified = false },
+		func(tok *oidc.IDToken) { tok.Email = "  " },
+	} {
+		idToken := verifiedIDToken()
+		edit(idToken)
+		if _, err := s.resolveIdentity(ctx, idToken); !errors.Is(err, ErrOIDCEmailNotVerified) {
+			t.Fatalf("err = %v, want ErrOIDCEmailNotVerified", err)
+		}
+	}
+
+	if len(identities.identities) != 0 || len(userRepo.users) != 1 {
+		t.Fatal("an unverified email must not link or provision an account")
+	}
+}
+
+func TestResolveIdentityWithoutAccount(t *testing.T) {
+	ctx := context.Background()
+	s, userRepo, identities, _ := newOIDCTestService()
+
+	idToken := verifiedIDToken()
+	if _, err := s.resolveIdentity(ctx, idToken); !errors.Is(err, ErrOIDCNoAccount) {
+		t.Fatalf("err = %v, want ErrOIDCNoAccount", err)
+	}
+
+	s.opts.OIDCAutoProvision = true
+	user, err := s.resolveIdentity(ctx, idToken)
+	if err != nil {
+		t.Fatalf("resolveIdentity: %v", err)
+	}
+	if user.Email != idToken.Email || user.Name != idToken.Name || user.Password != "" {
+		t.Fatalf("provisioned %+v", user)
+	}
+	if !user.IsActive || !user.IsEmailVerified() || user.Role != models.RoleUser {
+		t.Fatalf("provisioned user is not an active, verified user: %+v", user)
+	}
+	if _, err := userRepo.FindByEmail(ctx, idToken.Email); err != nil {
+		t.Fatalf("user was not stored: %v", err)
+	}
+	if len(identities.identities) != 1 {
+		t.Fatalf("%d identities, want 1", len(identities.identities))
+	}
+}
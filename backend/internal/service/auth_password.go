@@ -0,0 +1,137 @@
+package service
+
+import (
+	"backend/internal/generated"
+	"backend/internal/mailer"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+func (s *authService) ForgotPassword(ctx context.Context, req *generated.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(ctx, string(req.Email))
+	if err != nil {
+		// Unknown addresses look exactly like known ones to the caller
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Invited users set their first password from the invitation
+	if !user.IsActive || user.IsPending() {
+		return nil
+	}
+
+	// Only the most recent link is valid
+	if err := s.oneTimeTokenRepo.InvalidateForUser(ctx, user.ID, models.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.oneTimeTokenRepo.Create(ctx, &models.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposePasswordReset,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.opts.PasswordResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.opts.AppURL, url.QueryEscape(token))
+	go s.sendMail(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password. It expires in %s and works once.\n\n%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			user.Name, s.opts.PasswordResetTTL, link,
+		),
+	})
+
+	return nil
+}
+
+func (s *authService) ResetPassword(ctx context.Context, req *generated.ResetPasswordRequest) error {
+	stored, err := s.oneTimeTokenRepo.FindByHash(ctx, models.TokenPurposePasswordReset, hashToken(req.Token))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// Check the policy first so a rejected password does not burn the link
+	if err := s.opts.PasswordPolicy.Validate(req.Password, user.Email, user.Name); err != nil {
+		return err
+	}
+
+	used, err := s.oneTimeTokenRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	return s.setPassword(ctx, user, req.Password)
+}
+
+// setPassword is the single place passwords change. It logs the user out
+// everywhere and voids any reset links still in flight.
+func (s *authService) setPassword(ctx context.Context, user *models.User, password string) error {
+	if err := user.HashPassword(password); err != nil {
+		return err
+	}
+	user.TokenVersion++
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.oneTimeTokenRepo.InvalidateForUser(ctx, user.ID, models.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	if err := s.tokens.RevokeUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+// sendMail delivers in the background so response time does not reveal
+// whether an account exists
+func (s *authService) sendMail(msg mailer.Message) {
+	deliverMail(s.mailer, msg)
+}
+
+// deliverMail is meant to run in its own goroutine, detached from the
+// request context
+func deliverMail(m mailer.Mailer, msg mailer.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := m.Send(ctx, msg); err != nil {
+		log.Printf("Warning: failed to send %q mail: %v", msg.Subject, err)
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"backend/internal/generated"
+	"backend/internal/mailer"
+	"backend/internal/models"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	minNameLength = 2
+	maxNameLength = 100
+)
+
+var (
+	ErrInvalidCurrentPassword  = errors.New("current password is incorrect")
+	ErrCurrentPasswordRequired = errors.New("current password is required to change the email address")
+	ErrEmailTaken              = errors.New("email already registered")
+	ErrInvalidName             = fmt.Errorf("name must be between %d and %d characters", minNameLength, maxNameLength)
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+)
+
+func (s *authService) GetProfile(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	return s.userRepo.FindByID(ctx, userID)
+}
+
+// UpdateProfile applies a new name immediately. A new email only becomes
+// pending until it is confirmed from the link mailed to it.
+func (s *authService) UpdateProfile(ctx context.Context, userID uuid.UUID, req *generated.UpdateProfileRequest) (*models.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var newEmail string
+	if req.Email != nil {
+		newEmail = strings.TrimSpace(string(*req.Email))
+	}
+
+	// Asking for the current address back cancels a pending change
+	if newEmail != "" && strings.EqualFold(newEmail, user.Email) {
+		user.PendingEmail = ""
+		newEmail = ""
+	}
+
+	if newEmail != "" {
+		if req.CurrentPassword == nil || *req.CurrentPassword == "" {
+			return nil, ErrCurrentPasswordRequired
+		}
+		if err := s.checkCurrentPassword(ctx, user, *req.CurrentPassword); err != nil {
+			return nil, err
+		}
+		if err := s.ensureEmailAvailable(ctx, newEmail, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if n := utf8.RuneCountInString(name); n < minNameLength || n > maxNameLength {
+			return nil, ErrInvalidName
+		}
+		user.Name = name
+	}
+
+	if newEmail != "" {
+		user.PendingEmail = newEmail
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if newEmail != "" {
+		if err := s.sendEmailChange(user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+// ChangePassword logs out every session, the caller's included
+func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, req *generated.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCurrentPassword(ctx, user, req.CurrentPassword); err != nil {
+		return err
+	}
+
+	if err := s.opts.PasswordPolicy.Validate(req.NewPassword, user.Email, user.Name); err != nil {
+		return err
+	}
+
+	return s.setPassword(ctx, user, req.NewPassword)
+}
+
+func (s *authService) ConfirmEmailChange(ctx context.Context, req *generated.VerifyEmailRequest) error {
+	claims, err := jwt.ParseActionToken(req.Token, models.TokenPurposeEmailChange)
+	if err != nil {
+		return ErrInvalidEmailChangeToken
+	}
+
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return ErrInvalidEmailChangeToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrInvalidEmailChangeToken
+		}
+		return err
+	}
+
+	// Only the latest requested address can be confirmed, and only once
+	if user.PendingEmail == "" || !strings.EqualFold(user.PendingEmail, claims.Email) {
+		return ErrInvalidEmailChangeToken
+	}
+
+	// Someone may have registered the address since the change was requested
+	if err := s.ensureEmailAvailable(ctx, user.PendingEmail, user.ID); err != nil {
+		return err
+	}
+
+	oldEmail := user.Email
+	now := time.Now()
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// Reset links went to the old address
+	if err := s.oneTimeTokenRepo.InvalidateForUser(ctx, user.ID, models.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	go s.sendMail(mailer.Message{
+		To:      oldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email address of your account was changed to %s. If you did not do this, contact support right away.\n",
+			user.Name, user.Email,
+		),
+	})
+
+	return s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+// checkCurrentPassword shares the login throttle, so a stolen session
+// cannot be used to guess the password
+func (s *authService) checkCurrentPassword(ctx context.Context, user *models.User, plain string) error {
+	if err := s.throttle.Check(ctx, user.Email); err != nil {
+		return err
+	}
+
+	if !user.CheckPassword(plain) {
+		if err := s.throttle.RecordFailure(ctx, user.Email); err != nil {
+			return err
+		}
+		return ErrInvalidCurrentPassword
+	}
+
+	return s.throttle.Reset(ctx, user.Email)
+}
+
+func (s *authService) ensureEmailAvailable(ctx context.Context, email string, userID uuid.UUID) error {
+	existing, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != userID {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// sendEmailChange mails a confirmation link to the pending address
+func (s *authService) sendEmailChange(user *models.User) error {
+	token, err := jwt.GenerateActionToken(jwt.ActionParams{
+		Purpose: models.TokenPurposeEmailChange,
+		Subject: user.ID.String(),
+		Email:   user.PendingEmail,
+		TTL:     s.opts.EmailVerificationTTL,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/confirm-email-change?token=%s", s.opts.AppURL, url.QueryEscape(token))
+	go s.sendMail(mailer.Message{
+		To:      user.PendingEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen the link below to use this address for your account. It expires in %s.\n\n%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			user.Name, s.opts.EmailVerificationTTL, link,
+		),
+	})
+
+	return nil
+}
@@ -1,41 +1,154 @@
 package service
 
 import (
+	"backend/internal/cache"
 	"backend/internal/generated"
+	"backend/internal/mailer"
 	"backend/internal/models"
 	"backend/internal/repository"
 	jwt "backend/pkg"
+	"backend/pkg/oidc"
+	"backend/pkg/password"
+	"backend/pkg/webauthn"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"log"
+	"time"
 
+	"github.com/google/uuid"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
 type AuthService interface {
 	Register(ctx context.Context, req *generated.RegisterRequest) (*generated.AuthResponse, error)
-	Login(ctx context.Context, req *generated.LoginRequest) (*generated.AuthResponse, error)
+	// Login returns either tokens or, for users with two-factor
+	// authentication, a challenge to complete with VerifyMFA
+	Login(ctx context.Context, req *generated.LoginRequest, client ClientInfo) (*generated.AuthResponse, *generated.MfaChallengeResponse, error)
+	Refresh(ctx context.Context, req *generated.RefreshRequest) (*generated.AuthResponse, error)
+	Logout(ctx context.Context, claims *jwt.Claims, req *generated.LogoutRequest) error
+	ForgotPassword(ctx context.Context, req *generated.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *generated.ResetPasswordRequest) error
+	VerifyEmail(ctx context.Context, req *generated.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, req *generated.ResendVerificationRequest) error
+	VerifyMFA(ctx context.Context, req *generated.MfaVerifyRequest, client ClientInfo) (*generated.AuthResponse, error)
+	EnrollTOTP(ctx context.Context, userID uuid.UUID) (*generated.TotpEnrollResponse, error)
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, req *generated.TotpCodeRequest) (*generated.RecoveryCodesResponse, error)
+	DisableTOTP(ctx context.Context, userID uuid.UUID, req *generated.TotpCodeRequest) error
+	LoginHistory(ctx context.Context, userID uuid.UUID, page, perPage int) ([]models.LoginAttempt, int64, error)
+	StartOIDCLogin(ctx context.Context) (*generated.OidcAuthorizationResponse, error)
+	// CompleteOIDCLogin answers like Login: tokens, or an MFA challenge
+	CompleteOIDCLogin(ctx context.Context, req *generated.OidcCallbackRequest, client ClientInfo) (*generated.AuthResponse, *generated.MfaChallengeResponse, error)
+	GetProfile(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req *generated.UpdateProfileRequest) (*models.User, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *generated.ChangePasswordRequest) error
+	ConfirmEmailChange(ctx context.Context, req *generated.VerifyEmailRequest) error
+	RequestMagicLink(ctx context.Context, req *generated.MagicLinkRequest) error
+	// RedeemMagicLink answers like Login: tokens, or an MFA challenge
+	RedeemMagicLink(ctx context.Context, req *generated.MagicLinkVerifyRequest, client ClientInfo) (*generated.AuthResponse, *generated.MfaChallengeResponse, error)
+	StartPasskeyRegistration(ctx context.Context, userID uuid.UUID) (*generated.PasskeyCreationOptions, error)
+	FinishPasskeyRegistration(ctx context.Context, userID uuid.UUID, req *generated.PasskeyRegistrationRequest) (*models.WebAuthnCredential, error)
+	StartPasskeyLogin(ctx context.Context) (*generated.PasskeyRequestOptions, error)
+	FinishPasskeyLogin(ctx context.Context, req *generated.PasskeyAssertionCredential, client ClientInfo) (*generated.AuthResponse, error)
+	ListPasskeys(ctx context.Context, userID uuid.UUID) ([]models.WebAuthnCredential, error)
+	DeletePasskey(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type AuthOptions struct {
+	RefreshTokenTTL      time.Duration
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+	// RequireVerifiedEmail refuses tokens to users who have not verified
+	// their email address yet
+	RequireVerifiedEmail bool
+	// MFARequiredRoles may not turn two-factor authentication off
+	MFARequiredRoles []string
+	MFAIssuer        string
+	// AppURL is the frontend base URL for links in emails
+	AppURL string
+	// OIDCAutoProvision creates accounts on first single sign-on
+	OIDCAutoProvision bool
+	// MagicLinkEnabled allows login through single-use emailed links
+	MagicLinkEnabled bool
+	MagicLinkTTL     time.Duration
+	PasswordPolicy   *password.Policy
 }
 
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	oneTimeTokenRepo repository.OneTimeTokenRepository
+	recoveryCodeRepo repository.RecoveryCodeRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	identityRepo     repository.UserIdentityRepository
+	passkeyRepo      repository.WebAuthnCredentialRepository
+	tokens           TokenService
+	throttle         LoginThrottle
+	store            cache.Store
+	mailer           mailer.Mailer
+	// sso is nil when single sign-on is disabled
+	sso *oidc.Provider
+	// passkeys is nil when WebAuthn is disabled
+	passkeys *webauthn.RelyingParty
+	opts     AuthOptions
 }
 
-func NewAuthService(userRepo repository.UserRepository) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	oneTimeTokenRepo repository.OneTimeTokenRepository,
+	recoveryCodeRepo repository.RecoveryCodeRepository,
+	loginAttemptRepo repository.LoginAttemptRepository,
+	identityRepo repository.UserIdentityRepository,
+	passkeyRepo repository.WebAuthnCredentialRepository,
+	tokens TokenService,
+	throttle LoginThrottle,
+	store cache.Store,
+	mailer mailer.Mailer,
+	sso *oidc.Provider,
+	passkeys *webauthn.RelyingParty,
+	opts AuthOptions,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		oneTimeTokenRepo: oneTimeTokenRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		identityRepo:     identityRepo,
+		passkeyRepo:      passkeyRepo,
+		tokens:           tokens,
+		throttle:         throttle,
+		store:            store,
+		mailer:           mailer,
+		sso:              sso,
+		passkeys:         passkeys,
+		opts:             opts,
 	}
 }
 
 func (s *authService) Register(ctx context.Context, req *generated.RegisterRequest) (*generated.AuthResponse, error) {
+	if err := s.opts.PasswordPolicy.Validate(req.Password, string(req.Email), req.Name); err != nil {
+		return nil, err
+	}
+
 	// Check if email exists
 	existing, err := s.userRepo.FindByEmail(ctx, string(req.Email))
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, err
 	}
 	if existing != nil {
-		return nil, errors.New("email already registered")
+		return nil, ErrEmailTaken
 	}
 
 	// Create user
@@ -54,54 +167,222 @@ func (s *authService) Register(ctx context.Context, req *generated.RegisterReque
 		return nil, err
 	}
 
-	// Generate token
-	token, err := jwt.GenerateToken(user.ID.String(), user.Email, user.Role)
-	if err != nil {
+	if err := s.sendVerification(user); err != nil {
 		return nil, err
 	}
 
-	return &generated.AuthResponse{
-		Token: token,
-		User: generated.UserData{
-			Id:       user.ID,
-			Name:     user.Name,
-			Email:    openapi_types.Email(user.Email),
-			Role:     generated.UserDataRole(user.Role),
-			IsActive: user.IsActive,
-		},
-	}, nil
+	if s.opts.RequireVerifiedEmail {
+		return nil, ErrEmailNotVerified
+	}
+
+	return s.issueTokens(ctx, user, uuid.Must(uuid.NewV7()))
+}
+
+func (s *authService) Login(ctx context.Context, req *generated.LoginRequest, client ClientInfo) (*generated.AuthResponse, *generated.MfaChallengeResponse, error) {
+	email := string(req.Email)
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, nil, err
+		}
+		user = nil
+	}
+
+	// Throttle unknown addresses too, so lockouts do not reveal accounts
+	if err := s.throttle.Check(ctx, email); err != nil {
+		var locked *LockedOutError
+		if errors.As(err, &locked) {
+			s.recordAttempt(ctx, user, email, client, models.LoginOutcomeLockedOut)
+		}
+		return nil, nil, err
+	}
+
+	if user == nil || !user.CheckPassword(string(req.Password)) {
+		if err := s.throttle.RecordFailure(ctx, email); err != nil {
+			return nil, nil, err
+		}
+		s.recordAttempt(ctx, user, email, client, models.LoginOutcomeInvalidCredentials)
+		return nil, nil, errors.New("invalid email or password")
+	}
+
+	s.upgradePasswordHash(ctx, user, string(req.Password))
+
+	if !user.IsActive {
+		s.recordAttempt(ctx, user, email, client, models.LoginOutcomeAccountDisabled)
+		return nil, nil, errors.New("account is disabled")
+	}
+
+	if s.opts.RequireVerifiedEmail && !user.IsEmailVerified() {
+		s.recordAttempt(ctx, user, email, client, models.LoginOutcomeEmailNotVerified)
+		return nil, nil, ErrEmailNotVerified
+	}
+
+	// Failures are only cleared once every factor has been checked
+	if user.IsMFAEnabled() {
+		s.recordAttempt(ctx, user, email, client, models.LoginOutcomeMFAChallenge)
+		challenge, err := s.mfaChallenge(user)
+		return nil, challenge, err
+	}
+
+	response, err := s.completeLogin(ctx, user, client)
+	return response, nil, err
 }
 
-func (s *authService) Login(ctx context.Context, req *generated.LoginRequest) (*generated.AuthResponse, error) {
-	user, err := s.userRepo.FindByEmail(ctx, string(req.Email))
+// upgradePasswordHash rehashes bcrypt or outdated argon2 hashes while the
+// plain password is at hand. Failing only delays the upgrade to the next
+// login, so it never fails the login itself.
+func (s *authService) upgradePasswordHash(ctx context.Context, user *models.User, plain string) {
+	if !user.PasswordNeedsRehash() {
+		return
+	}
+	if err := user.HashPassword(plain); err != nil {
+		log.Printf("Warning: failed to rehash password: %v", err)
+		return
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		log.Printf("Warning: failed to store rehashed password: %v", err)
+	}
+}
+
+func (s *authService) Refresh(ctx context.Context, req *generated.RefreshRequest) (*generated.AuthResponse, error) {
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, hashToken(req.RefreshToken))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("invalid email or password")
+			return nil, ErrInvalidRefreshToken
 		}
 		return nil, err
 	}
 
-	if !user.CheckPassword(string(req.Password)) {
-		return nil, errors.New("invalid email or password")
+	if stored.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
 	}
 
-	if !user.IsActive {
-		return nil, errors.New("account is disabled")
+	// A rotated token coming back means it was copied; kill the family
+	if stored.RotatedAt != nil {
+		return nil, s.revokeFamily(ctx, stored.FamilyID)
 	}
 
-	token, err := jwt.GenerateToken(user.ID.String(), user.Email, user.Role)
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	rotated, err := s.refreshTokenRepo.MarkRotated(ctx, stored.ID)
 	if err != nil {
 		return nil, err
 	}
+	if !rotated {
+		// Lost a race against another refresh with the same token
+		return nil, s.revokeFamily(ctx, stored.FamilyID)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	// Invited users have not joined yet, so they have no session to keep
+	if !user.IsActive || user.IsPending() {
+		return nil, ErrAccountInactive
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+func (s *authService) Logout(ctx context.Context, claims *jwt.Claims, req *generated.LogoutRequest) error {
+	if err := s.tokens.RevokeToken(ctx, claims); err != nil {
+		return err
+	}
+
+	if req == nil || req.RefreshToken == nil || *req.RefreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, hashToken(*req.RefreshToken))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Never let one user revoke another user's refresh tokens
+	if stored.UserID.String() != claims.UserID {
+		return nil
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+func (s *authService) revokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens signs an access token and stores a new refresh token in the
+// given family. Logins start a new family, refreshes continue the old one.
+func (s *authService) issueTokens(ctx context.Context, user *models.User, familyID uuid.UUID) (*generated.AuthResponse, error) {
+	token, err := jwt.GenerateToken(jwt.TokenParams{
+		UserID:  user.ID.String(),
+		Email:   user.Email,
+		Role:    user.Role,
+		Version: user.TokenVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshTokenRepo.Create(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.opts.RefreshTokenTTL),
+	}); err != nil {
+		return nil, err
+	}
 
 	return &generated.AuthResponse{
-		Token: token,
-		User: generated.UserData{
-			Id:       user.ID,
-			Name:     user.Name,
-			Email:    openapi_types.Email(user.Email),
-			Role:     generated.UserDataRole(user.Role),
-			IsActive: user.IsActive,
-		},
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(jwt.AccessTokenTTL().Seconds()),
+		User:         toUserData(user),
 	}, nil
 }
+
+func toUserData(user *models.User) generated.UserData {
+	return generated.UserData{
+		Id:            user.ID,
+		Name:          user.Name,
+		Email:         openapi_types.Email(user.Email),
+		Role:          user.Role,
+		IsActive:      user.IsActive,
+		EmailVerified: user.IsEmailVerified(),
+		MfaEnabled:    user.IsMFAEnabled(),
+	}
+}
+
+// generateOpaqueToken returns 256 random bits, URL-safe encoded
+func generateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is what gets stored for opaque tokens; they have full entropy
+// so a fast hash is enough
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newRefreshTestService(t *testing.T, user *models.User) (*authService, *fakeRefreshTokenRepo) {
+	t.Helper()
+	useTestKeys(t)
+	refreshTokens := newFakeRefreshTokenRepo()
+	return &authService{
+		userRepo:         newFakeUserRepo(user),
+		refreshTokenRepo: refreshTokens,
+		tokens:           &fakeTokenService{},
+		opts:             AuthOptions{RefreshTokenTTL: time.Hour},
+	}, refreshTokens
+}
+
+func activeUser() *models.User {
+	return &models.User{
+		BaseUUID: models.BaseUUID{ID: uuid.New()},
+		Email:    "jane@example.com",
+		Role:     models.RoleUser,
+		IsActive: true,
+		Status:   models.UserStatusActive,
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	s, refreshTokens := newRefreshTestService(t, user)
+	family := uuid.New()
+
+	first, err := s.issueTokens(ctx, user, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	if claims, err := jwt.ParseToken(second.Token); err != nil || claims.UserID != user.ID.String() {
+		t.Fatalf("new access token is invalid: %v", err)
+	}
+
+	// The new token continues the family and is the only live one
+	stored, err := refreshTokens.FindByHash(ctx, hashToken(second.RefreshToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.FamilyID != family {
+		t.Fatalf("got family %s, want %s", stored.FamilyID, family)
+	}
+	if live := refreshTokens.live(family); live != 1 {
+		t.Fatalf("got %d live tokens in the family, want 1", live)
+	}
+
+	if _, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: second.RefreshToken}); err != nil {
+		t.Fatalf("refreshing with the rotated token: %v", err)
+	}
+}
+
+func TestRefreshReplayRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	s, refreshTokens := newRefreshTestService(t, user)
+	family := uuid.New()
+
+	first, err := s.issueTokens(ctx, user, family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Presenting the already rotated token again looks like theft
+	if _, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: first.RefreshToken}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("got %v, want ErrRefreshTokenReused", err)
+	}
+	if live := refreshTokens.live(family); live != 0 {
+		t.Fatalf("got %d live tokens in the family, want 0", live)
+	}
+
+	// The legitimate holder of the newest token is logged out too
+	if _, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: second.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("got %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshAfterLogout(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	s, _ := newRefreshTestService(t, user)
+
+	tokens, err := s.issueTokens(ctx, user, uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := jwt.ParseToken(tokens.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Logout(ctx, claims, &generated.LogoutRequest{RefreshToken: &tokens.RefreshToken}); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: tokens.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("got %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(user *models.User)
+		want   error
+	}{
+		{"disabled user", func(user *models.User) { user.IsActive = false }, ErrAccountInactive},
+		{"pending user", func(user *models.User) { user.Status = models.UserStatusPending }, ErrAccountInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := activeUser()
+			s, _ := newRefreshTestService(t, user)
+
+			tokens, err := s.issueTokens(ctx, user, uuid.New())
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(user)
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: tokens.RefreshToken}); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown token", func(t *testing.T) {
+		s, _ := newRefreshTestService(t, activeUser())
+		if _, err := s.Refresh(context.Background(), &generated.RefreshRequest{RefreshToken: "nope"}); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Fatalf("got %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		ctx := context.Background()
+		user := activeUser()
+		s, _ := newRefreshTestService(t, user)
+		s.opts.RefreshTokenTTL = -time.Minute
+
+		tokens, err := s.issueTokens(ctx, user, uuid.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Refresh(ctx, &generated.RefreshRequest{RefreshToken: tokens.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Fatalf("got %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/pkg/webauthn"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// passkeyCeremonyTTL bounds how long the browser may take for one
+// registration or login; it is also the timeout sent in the options
+const passkeyCeremonyTTL = 5 * time.Minute
+
+var (
+	ErrWebAuthnDisabled       = errors.New("passkeys are not enabled")
+	ErrInvalidPasskeyCeremony = errors.New("invalid or expired passkey request")
+	ErrPasskeyRegistered      = errors.New("this passkey is already registered")
+	ErrPasskeyLoginFailed     = errors.New("passkey login failed")
+)
+
+// passkeyRegistration is kept server side between the two registration
+// calls, one per user
+type passkeyRegistration struct {
+	Challenge string `json:"challenge"`
+}
+
+func passkeyRegistrationKey(userID uuid.UUID) string {
+	return fmt.Sprintf("auth:webauthn:registration:%s", userID)
+}
+
+// Login challenges are not tied to an account until the assertion names
+// its credential, so they are looked up by the challenge itself
+func passkeyLoginKey(challenge string) string {
+	return fmt.Sprintf("auth:webauthn:login:%s", challenge)
+}
+
+func (s *authService) StartPasskeyRegistration(ctx context.Context, userID uuid.UUID) (*generated.PasskeyCreationOptions, error) {
+	if s.passkeys == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.passkeyRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	// Starting again replaces a registration that was never finished
+	if err := s.store.Set(ctx, passkeyRegistrationKey(userID), passkeyRegistration{Challenge: challenge}, passkeyCeremonyTTL); err != nil {
+		return nil, err
+	}
+
+	params := make([]generated.PasskeyCredentialParameter, len(webauthn.Algorithms))
+	for i, alg := range webauthn.Algorithms {
+		params[i] = generated.PasskeyCredentialParameter{Type: "public-key", Alg: alg}
+	}
+	exclude := make([]generated.PasskeyCredentialDescriptor, len(existing))
+	for i, credential := range existing {
+		exclude[i] = generated.PasskeyCredentialDescriptor{Type: "public-key", Id: webauthn.Encode(credential.CredentialID)}
+	}
+
+	return &generated.PasskeyCreationOptions{
+		Rp: generated.PasskeyRelyingParty{
+			Id:   s.passkeys.ID(),
+			Name: s.passkeys.Name(),
+		},
+		User: generated.PasskeyUser{
+			Id:          webauthn.Encode(user.ID[:]),
+			Name:        user.Email,
+			DisplayName: user.Name,
+		},
+		Challenge:          challenge,
+		PubKeyCredParams:   params,
+		Timeout:            int(passkeyCeremonyTTL.Milliseconds()),
+		ExcludeCredentials: exclude,
+		// Discoverable credentials let users log in without typing an email
+		AuthenticatorSelection: generated.PasskeyAuthenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+func (s *authService) FinishPasskeyRegistration(ctx context.Context, userID uuid.UUID, req *generated.PasskeyRegistrationRequest) (*models.WebAuthnCredential, error) {
+	if s.passkeys == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	// Each challenge is good for one attempt
+	key := passkeyRegistrationKey(userID)
+	var registration passkeyRegistration
+	if err := s.store.Get(ctx, key, &registration); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, ErrInvalidPasskeyCeremony
+		}
+		return nil, err
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		return nil, err
+	}
+
+	clientData, err := webauthn.Decode(req.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+	attestation, err := webauthn.Decode(req.Credential.Response.AttestationObject)
+	if err != nil {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+
+	verified, err := s.passkeys.VerifyRegistration(registration.Challenge, clientData, attestation)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrVerification) {
+			log.Printf("Warning: passkey registration rejected: %v", err)
+			return nil, ErrInvalidPasskeyCeremony
+		}
+		return nil, err
+	}
+
+	if _, err := s.passkeyRepo.FindByCredentialID(ctx, verified.ID); err == nil {
+		return nil, ErrPasskeyRegistered
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	name := "Passkey"
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		name = strings.TrimSpace(*req.Name)
+	}
+	aaguid, _ := uuid.FromBytes(verified.AAGUID)
+
+	credential := &models.WebAuthnCredential{
+		UserID:         userID,
+		Name:           name,
+		CredentialID:   verified.ID,
+		PublicKey:      verified.PublicKey,
+		SignCount:      int64(verified.SignCount),
+		AAGUID:         aaguid,
+		BackupEligible: verified.BackupEligible,
+	}
+	if err := s.passkeyRepo.Create(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (s *authService) StartPasskeyLogin(ctx context.Context) (*generated.PasskeyRequestOptions, error) {
+	if s.passkeys == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Set(ctx, passkeyLoginKey(challenge), true, passkeyCeremonyTTL); err != nil {
+		return nil, err
+	}
+
+	return &generated.PasskeyRequestOptions{
+		Challenge:        challenge,
+		RpId:             s.passkeys.ID(),
+		Timeout:          int(passkeyCeremonyTTL.Milliseconds()),
+		UserVerification: "required",
+	}, nil
+}
+
+// FinishPasskeyLogin issues the same tokens as Login. The authenticator
+// has verified the user, so a passkey counts as both factors.
+func (s *authService) FinishPasskeyLogin(ctx context.Context, req *generated.PasskeyAssertionCredential, client ClientInfo) (*generated.AuthResponse, error) {
+	if s.passkeys == nil {
+		return nil, ErrWebAuthnDisabled
+	}
+
+	assertion, err := decodeAssertion(&req.Response)
+	if err != nil {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+
+	challenge, err := webauthn.Challenge(assertion.ClientDataJSON)
+	if err != nil {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+	key := passkeyLoginKey(challenge)
+	var pending bool
+	if err := s.store.Get(ctx, key, &pending); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, ErrInvalidPasskeyCeremony
+		}
+		return nil, err
+	}
+	if err := s.store.Delete(ctx, key); err != nil {
+		return nil, err
+	}
+
+	credentialID, err := webauthn.Decode(req.RawId)
+	if err != nil {
+		return nil, ErrInvalidPasskeyCeremony
+	}
+	credential, err := s.passkeyRepo.FindByCredentialID(ctx, credentialID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPasskeyLoginFailed
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, credential.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPasskeyLoginFailed
+		}
+		return nil, err
+	}
+
+	// The user handle, when sent, must name the credential's owner
+	if req.Response.UserHandle != nil && *req.Response.UserHandle != "" {
+		handle, err := webauthn.Decode(*req.Response.UserHandle)
+		if err != nil || !bytes.Equal(handle, user.ID[:]) {
+			return nil, ErrPasskeyLoginFailed
+		}
+	}
+
+	if err := s.throttle.Check(ctx, user.Email); err != nil {
+		var locked *LockedOutError
+		if errors.As(err, &locked) {
+			s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeLockedOut)
+		}
+		return nil, err
+	}
+
+	signCount, err := s.passkeys.VerifyAssertion(challenge, assertion, credential.PublicKey, uint32(credential.SignCount))
+	if err != nil {
+		if !errors.Is(err, webauthn.ErrVerification) && !errors.Is(err, webauthn.ErrCloned) {
+			return nil, err
+		}
+		log.Printf("Warning: passkey login rejected for credential %s: %v", credential.ID, err)
+		if err := s.throttle.RecordFailure(ctx, user.Email); err != nil {
+			return nil, err
+		}
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeInvalidCredentials)
+		return nil, ErrPasskeyLoginFailed
+	}
+
+	if err := s.passkeyRepo.RecordUse(ctx, credential.ID, signCount); err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive || user.IsPending() {
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeAccountDisabled)
+		return nil, ErrAccountInactive
+	}
+
+	if s.opts.RequireVerifiedEmail && !user.IsEmailVerified() {
+		s.recordAttempt(ctx, user, user.Email, client, models.LoginOutcomeEmailNotVerified)
+		return nil, ErrEmailNotVerified
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+func decodeAssertion(resp *generated.PasskeyAssertionResponse) (webauthn.Assertion, error) {
+	var a webauthn.Assertion
+	var err error
+	if a.ClientDataJSON, err = webauthn.Decode(resp.ClientDataJSON); err != nil {
+		return a, err
+	}
+	if a.AuthenticatorData, err = webauthn.Decode(resp.AuthenticatorData); err != nil {
+		return a, err
+	}
+	if a.Signature, err = webauthn.Decode(resp.Signature); err != nil {
+		return a, err
+	}
+	return a, nil
+}
+
+func (s *authService) ListPasskeys(ctx context.Context, userID uuid.UUID) ([]models.WebAuthnCredential, error) {
+	return s.passkeyRepo.FindByUser(ctx, userID)
+}
+
+func (s *authService) DeletePasskey(ctx context.Context, userID, id uuid.UUID) error {
+	credential, err := s.passkeyRepo.FindByIDForUser(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return s.passkeyRepo.Delete(ctx, credential.ID)
+}
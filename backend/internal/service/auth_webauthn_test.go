@@ -0,0 +1,286 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/webauthn"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	passkeyTestRPID   = "example.com"
+	passkeyTestOrigin = "https://app.example.com"
+)
+
+type fakePasskeyRepo struct {
+	repository.WebAuthnCredentialRepository
+	mu          sync.Mutex
+	credentials []*models.WebAuthnCredential
+}
+
+func (r *fakePasskeyRepo) Create(ctx context.Context, credential *models.WebAuthnCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	credential.ID = uuid.New()
+	copied := *credential
+	r.credentials = append(r.credentials, &copied)
+	return nil
+}
+
+func (r *fakePasskeyRepo) FindByCredentialID(ctx context.Context, credentialID []byte) (*models.WebAuthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, credential := range r.credentials {
+		if bytes.Equal(credential.CredentialID, credentialID) {
+			copied := *credential
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePasskeyRepo) FindByUser(ctx context.Context, userID uuid.UUID) ([]models.WebAuthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []models.WebAuthnCredential
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			found = append(found, *credential)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePasskeyRepo) RecordUse(ctx context.Context, id uuid.UUID, signCount uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, credential := range r.credentials {
+		if credential.ID == id {
+			now := time.Now()
+			credential.SignCount = int64(signCount)
+			credential.LastUsedAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeLoginAttemptRepo struct {
+	repository.LoginAttemptRepository
+	mu       sync.Mutex
+	outcomes []string
+}
+
+func (r *fakeLoginAttemptRepo) Create(ctx context.Context, attempt *models.LoginAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outcomes = append(r.outcomes, attempt.Outcome)
+	return nil
+}
+
+// edPasskey is an Ed25519 software authenticator, enough to drive the
+// ceremonies through the service; pkg/webauthn covers the rest
+type edPasskey struct {
+	id        []byte
+	key       ed25519.PrivateKey
+	signCount uint32
+}
+
+func newEdPasskey(t *testing.T) *edPasskey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &edPasskey{id: []byte(uuid.NewString()), key: key, signCount: 1}
+}
+
+// coseKey is {1: 1 (OKP), 3: -8 (EdDSA), -1: 6 (Ed25519), -2: x}
+func (p *edPasskey) coseKey() []byte {
+	return append([]byte{0xa4, 0x01, 0x01, 0x03, 0x27, 0x20, 0x06, 0x21, 0x58, 0x20}, p.key.Public().(ed25519.PublicKey)...)
+}
+
+func (p *edPasskey) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(passkeyTestRPID))
+	flags := byte(0x05) // user present and verified
+	if attested {
+		flags |= 0x40
+	}
+	data := binary.BigEndian.AppendUint32(append(rpIDHash[:], flags), p.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(p.id)))
+		data = append(append(data, p.id...), p.coseKey()...)
+	}
+	return data
+}
+
+func passkeyClientData(ceremony, challenge string) []byte {
+	data, _ := json.Marshal(map[string]string{"type": ceremony, "challenge": challenge, "origin": passkeyTestOrigin})
+	return data
+}
+
+func (p *edPasskey) register(challenge string) *generated.PasskeyRegistrationRequest {
+	authData := p.authData(true)
+	// {"fmt": "none", "attStmt": {}, "authData": authData}
+	attestation := []byte{0xa3, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a', 0x58, byte(len(authData))}
+	attestation = append(attestation, authData...)
+	return &generated.PasskeyRegistrationRequest{
+		Credential: generated.PasskeyAttestationCredential{
+			Id:    webauthn.Encode(p.id),
+			RawId: webauthn.Encode(p.id),
+			Type:  "public-key",
+			Response: generated.PasskeyAttestationResponse{
+				ClientDataJSON:    webauthn.Encode(passkeyClientData("webauthn.create", challenge)),
+				AttestationObject: webauthn.Encode(attestation),
+			},
+		},
+	}
+}
+
+func (p *edPasskey) assert(challenge string) *generated.PasskeyAssertionCredential {
+	clientData := passkeyClientData("webauthn.get", challenge)
+	authData := p.authData(false)
+	hash := sha256.Sum256(clientData)
+	signature := ed25519.Sign(p.key, append(append([]byte(nil), authData...), hash[:]...))
+	return &generated.PasskeyAssertionCredential{
+		Id:    webauthn.Encode(p.id),
+		RawId: webauthn.Encode(p.id),
+		Type:  "public-key",
+		Response: generated.PasskeyAssertionResponse{
+			ClientDataJSON:    webauthn.Encode(clientData),
+			AuthenticatorData: webauthn.Encode(authData),
+			Signature:         webauthn.Encode(signature),
+		},
+	}
+}
+
+func newPasskeyTestService(t *testing.T, user *models.User) (*authService, *fakePasskeyRepo) {
+	t.Helper()
+	useTestKeys(t)
+	rp, err := webauthn.New(webauthn.Config{RPID: passkeyTestRPID, Origins: []string{passkeyTestOrigin}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := cache.NewMemoryCache()
+	passkeys := &fakePasskeyRepo{}
+	return &authService{
+		userRepo:         newFakeUserRepo(user),
+		refreshTokenRepo: newFakeRefreshTokenRepo(),
+		loginAttemptRepo: &fakeLoginAttemptRepo{},
+		passkeyRepo:      passkeys,
+		tokens:           &fakeTokenService{},
+		throttle:         NewLoginThrottle(store, LoginThrottleOptions{MaxAttempts: 10, LockoutDuration: time.Minute}),
+		store:            store,
+		passkeys:         rp,
+		opts:             AuthOptions{RefreshTokenTTL: time.Hour},
+	}, passkeys
+}
+
+func TestPasskeyRegistrationChallengeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	s, passkeys := newPasskeyTestService(t, user)
+	passkey := newEdPasskey(t)
+
+	options, err := s.StartPasskeyRegistration(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := passkey.register(options.Challenge)
+	credential, err := s.FinishPasskeyRegistration(ctx, user.ID, req)
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration: %v", err)
+	}
+	if !bytes.Equal(credential.CredentialID, passkey.id) || len(passkeys.credentials) != 1 {
+		t.Fatalf("registered %+v", credential)
+	}
+
+	if _, err := s.FinishPasskeyRegistration(ctx, user.ID, req); !errors.Is(err, ErrInvalidPasskeyCeremony) {
+		t.Fatalf("replayed registration: err = %v, want ErrInvalidPasskeyCeremony", err)
+	}
+
+	// A response to another challenge is refused
+	if _, err := s.StartPasskeyRegistration(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.FinishPasskeyRegistration(ctx, user.ID, newEdPasskey(t).register("another-challenge")); !errors.Is(err, ErrInvalidPasskeyCeremony) {
+		t.Fatalf("wrong challenge: err = %v, want ErrInvalidPasskeyCeremony", err)
+	}
+	if len(passkeys.credentials) != 1 {
+		t.Fatal("a rejected registration was stored")
+	}
+}
+
+func TestPasskeyLoginChallengeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	s, passkeys := newPasskeyTestService(t, user)
+	passkey := newEdPasskey(t)
+	passkeys.Create(ctx, &models.WebAuthnCredential{UserID: user.ID, CredentialID: passkey.id, PublicKey: passkey.coseKey(), SignCount: 1})
+
+	options, err := s.StartPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passkey.signCount = 2
+	req := passkey.assert(options.Challenge)
+	response, err := s.FinishPasskeyLogin(ctx, req, ClientInfo{})
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+	if response.Token == "" {
+		t.Fatal("no access token issued")
+	}
+	if stored, _ := passkeys.FindByCredentialID(ctx, passkey.id); stored.SignCount != 2 {
+		t.Fatalf("stored sign count %d, want 2", stored.SignCount)
+	}
+
+	if _, err := s.FinishPasskeyLogin(ctx, req, ClientInfo{}); !errors.Is(err, ErrInvalidPasskeyCeremony) {
+		t.Fatalf("replayed assertion: err = %v, want ErrInvalidPasskeyCeremony", err)
+	}
+
+	passkey.signCount = 3
+	if _, err := s.FinishPasskeyLogin(ctx, passkey.assert("never-issued"), ClientInfo{}); !errors.Is(err, ErrInvalidPasskeyCeremony) {
+		t.Fatalf("unknown challenge: err = %v, want ErrInvalidPasskeyCeremony", err)
+	}
+}
+
+func TestPasskeyLoginRejectsCounterGoingBackwards(t *testing.T) {
+	ctx := context.Background()
+	user := activeUser()
+	s, passkeys := newPasskeyTestService(t, user)
+	passkey := newEdPasskey(t)
+	passkeys.Create(ctx, &models.WebAuthnCredential{UserID: user.ID, CredentialID: passkey.id, PublicKey: passkey.coseKey(), SignCount: 7})
+
+	options, err := s.StartPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passkey.signCount = 6
+	if _, err := s.FinishPasskeyLogin(ctx, passkey.assert(options.Challenge), ClientInfo{}); !errors.Is(err, ErrPasskeyLoginFailed) {
+		t.Fatalf("err = %v, want ErrPasskeyLoginFailed", err)
+	}
+
+	if stored, _ := passkeys.FindByCredentialID(ctx, passkey.id); stored.SignCount != 7 {
+		t.Fatalf("stored sign count changed to %d", stored.SignCount)
+	}
+	attempts := s.loginAttemptRepo.(*fakeLoginAttemptRepo)
+	if len(attempts.outcomes) != 1 || attempts.outcomes[0] != models.LoginOutcomeInvalidCredentials {
+		t.Fatalf("recorded %v", attempts.outcomes)
+	}
+}
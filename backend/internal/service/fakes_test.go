@@ -0,0 +1,493 @@
+package service
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/internal/repository"
+	jwt "backend/pkg"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The fakes embed their interface so tests only implement what they use;
+// anything else panics and shows up as a failing test.
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	mu    sync.Mutex
+	users map[uuid.UUID]*models.User
+}
+
+func newFakeUserRepo(users ...*models.User) *fakeUserRepo {
+	repo := &fakeUserRepo{users: make(map[uuid.UUID]*models.User)}
+	for _, user := range users {
+		if user.ID == uuid.Nil {
+			user.ID = uuid.New()
+		}
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id generated.IdParam) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok || user.TOTPLastStep >= step {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+type fakeRefreshTokenRepo struct {
+	repository.RefreshTokenRepository
+	mu     sync.Mutex
+	tokens map[uuid.UUID]*models.RefreshToken
+}
+
+func newFakeRefreshTokenRepo() *fakeRefreshTokenRepo {
+	return &fakeRefreshTokenRepo{tokens: make(map[uuid.UUID]*models.RefreshToken)}
+}
+
+func (r *fakeRefreshTokenRepo) Create(ctx context.Context, token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token.ID = uuid.New()
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRefreshTokenRepo) MarkRotated(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[id]
+	if !ok || token.RotatedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.RotatedAt = &now
+	return true, nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// live counts the tokens of a family that are neither rotated nor revoked
+func (r *fakeRefreshTokenRepo) live(familyID uuid.UUID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RotatedAt == nil && token.RevokedAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+type fakeTokenService struct {
+	TokenService
+	revoked []string
+	// sessionsRevoked lists the users whose sessions were all revoked
+	sessionsRevoked []uuid.UUID
+}
+
+func (s *fakeTokenService) RevokeToken(ctx context.Context, claims *jwt.Claims) error {
+	s.revoked = append(s.revoked, claims.ID)
+	return nil
+}
+
+func (s *fakeTokenService) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	s.sessionsRevoked = append(s.sessionsRevoked, userID)
+	return nil
+}
+
+func (s *fakeTokenService) InvalidatePrincipal(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+// useTestKeys installs an HMAC signing key for the duration of the test
+func useTestKeys(t *testing.T) {
+	t.Helper()
+	key, err := jwt.NewHMACKey("test", []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := jwt.NewKeySet("test", []jwt.Key{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwt.SetKeySet(ks)
+	t.Cleanup(func() { jwt.SetKeySet(nil) })
+}
+
+type fakeRecoveryCodeRepo struct {
+	repository.RecoveryCodeRepository
+	mu sync.Mutex
+	// used maps each code hash to whether it was consumed
+	used map[string]bool
+}
+
+func (r *fakeRecoveryCodeRepo) ReplaceForUser(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.used = make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		r.used[hash] = false
+	}
+	return nil
+}
+
+func (r *fakeRecoveryCodeRepo) Use(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	used, ok := r.used[hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.used[hash] = true
+	return true, nil
+}
+
+type fakeIdentityRepo struct {
+	repository.UserIdentityRepository
+	mu         sync.Mutex
+	identities []*models.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *models.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	identity.ID = uuid.New()
+	copied := *identity
+	r.identities = append(r.identities, &copied)
+	return nil
+}
+
+func (r *fakeIdentityRepo) FindBySubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeIdentityRepo) TouchLastLogin(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.ID == id {
+			now := time.Now()
+			identity.LastLoginAt = &now
+		}
+	}
+	return nil
+}
+
+type fakeRoleRepo struct {
+	repository.RoleRepository
+	mu    sync.Mutex
+	roles map[string]*models.Role
+}
+
+func newFakeRoleRepo(names ...string) *fakeRoleRepo {
+	repo := &fakeRoleRepo{roles: make(map[string]*models.Role)}
+	for _, name := range names {
+		repo.roles[name] = &models.Role{Name: name}
+	}
+	return repo
+}
+
+func (r *fakeRoleRepo) FindByName(ctx context.Context, name string) (*models.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *role
+	return &copied, nil
+}
+
+func (r *fakeRoleRepo) Create(ctx context.Context, role *models.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *role
+	r.roles[role.Name] = &copied
+	return nil
+}
+
+func (r *fakeRoleRepo) ReplacePermissions(ctx context.Context, role *models.Role, permissions []models.Permission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role.Permissions = append([]models.Permission(nil), permissions...)
+	copied := *role
+	r.roles[role.Name] = &copied
+	return nil
+}
+
+type fakePermissionRepo struct {
+	repository.PermissionRepository
+	mu          sync.Mutex
+	permissions []models.Permission
+}
+
+func (r *fakePermissionRepo) Create(ctx context.Context, permission *models.Permission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.permissions = append(r.permissions, *permission)
+	return nil
+}
+
+func (r *fakePermissionRepo) FindAll(ctx context.Context) ([]models.Permission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.Permission(nil), r.permissions...), nil
+}
+
+func (r *fakePermissionRepo) FindByName(ctx context.Context, name string) (*models.Permission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, permission := range r.permissions {
+		if permission.Name == name {
+			return &permission, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePermissionRepo) FindByNames(ctx context.Context, names []string) ([]models.Permission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []models.Permission
+	for _, permission := range r.permissions {
+		if slices.Contains(names, permission.Name) {
+			found = append(found, permission)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePermissionRepo) Update(ctx context.Context, permission *models.Permission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.permissions {
+		if r.permissions[i].Name == permission.Name {
+			r.permissions[i] = *permission
+		}
+	}
+	return nil
+}
+
+func (r *fakeRoleRepo) UpdateDescription(ctx context.Context, role *models.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.roles[role.Name].Description = role.Description
+	return nil
+}
+
+type fakeProductRepo struct {
+	repository.ProductRepository
+	mu       sync.Mutex
+	products map[uuid.UUID]*models.Product
+}
+
+func newFakeProductRepo(products ...*models.Product) *fakeProductRepo {
+	repo := &fakeProductRepo{products: make(map[uuid.UUID]*models.Product)}
+	for _, product := range products {
+		if product.ID == uuid.Nil {
+			product.ID = uuid.New()
+		}
+		repo.products[product.ID] = product
+	}
+	return repo
+}
+
+func (r *fakeProductRepo) FindByID(ctx context.Context, id generated.IdParam) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.products[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *product
+	return &copied, nil
+}
+
+func (r *fakeProductRepo) FindAll(ctx context.Context, page, perPage int, ownerID *uuid.UUID) ([]models.Product, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []models.Product
+	for _, product := range r.products {
+		if ownerID == nil || (product.OwnerID != nil && *product.OwnerID == *ownerID) {
+			found = append(found, *product)
+		}
+	}
+	return found, int64(len(found)), nil
+}
+
+func (r *fakeProductRepo) Update(ctx context.Context, product *models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *product
+	r.products[product.ID] = &copied
+	return nil
+}
+
+func (r *fakeProductRepo) Delete(ctx context.Context, id generated.IdParam) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.products, id)
+	return nil
+}
+
+type fakePATRepo struct {
+	repository.PersonalAccessTokenRepository
+	mu     sync.Mutex
+	tokens []*models.PersonalAccessToken
+}
+
+func (r *fakePATRepo) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token.ID = uuid.New()
+	copied := *token
+	r.tokens = append(r.tokens, &copied)
+	return nil
+}
+
+func (r *fakePATRepo) FindByHash(ctx context.Context, hash string) (*models.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakePATRepo) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []models.PersonalAccessToken
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			found = append(found, *token)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakePATRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakePATRepo) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.ID == id {
+			now := time.Now()
+			token.LastUsedAt = &now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/internal/repository"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxImpersonationReasonLength = 500
+
+var (
+	ErrImpersonationReasonRequired = errors.New("a reason is required to impersonate a user")
+	ErrCannotImpersonateSelf       = errors.New("you cannot impersonate yourself")
+	ErrCannotImpersonateAdmin      = errors.New("users with administrative permissions cannot be impersonated")
+	ErrImpersonationTargetInactive = errors.New("only active users can be impersonated")
+)
+
+type ImpersonationService interface {
+	// Start records the impersonation and returns an access token for the
+	// target carrying the admin in its act claim. There is no refresh
+	// token; the admin starts again once it expires.
+	Start(ctx context.Context, actorID uuid.UUID, targetID generated.IdParam, reason string, client ClientInfo) (*models.Impersonation, string, error)
+	List(ctx context.Context, filter repository.ImpersonationFilter, page, perPage int) ([]models.Impersonation, int64, error)
+}
+
+type impersonationService struct {
+	repo        repository.ImpersonationRepository
+	userRepo    repository.UserRepository
+	permissions PermissionResolver
+	ttl         time.Duration
+}
+
+func NewImpersonationService(repo repository.ImpersonationRepository, userRepo repository.UserRepository, permissions PermissionResolver, ttl time.Duration) ImpersonationService {
+	return &impersonationService{
+		repo:        repo,
+		userRepo:    userRepo,
+		permissions: permissions,
+		ttl:         ttl,
+	}
+}
+
+func (s *impersonationService) Start(ctx context.Context, actorID uuid.UUID, targetID generated.IdParam, reason string, client ClientInfo) (*models.Impersonation, string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxImpersonationReasonLength {
+		return nil, "", ErrImpersonationReasonRequired
+	}
+	if actorID == targetID {
+		return nil, "", ErrCannotImpersonateSelf
+	}
+
+	target, err := s.userRepo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.checkTarget(ctx, actorID, target); err != nil {
+		return nil, "", err
+	}
+	if !target.IsActive || target.IsPending() {
+		return nil, "", ErrImpersonationTargetInactive
+	}
+
+	impersonation := &models.Impersonation{
+		ActorID:   actorID,
+		TargetID:  target.ID,
+		Reason:    reason,
+		IPAddress: client.IP,
+		UserAgent: client.UserAgent,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	impersonation.ID = uuid.Must(uuid.NewV7())
+	if runes := []rune(impersonation.UserAgent); len(runes) > maxUserAgentLength {
+		impersonation.UserAgent = string(runes[:maxUserAgentLength])
+	}
+
+	// Record first: a token must never exist without its audit entry
+	if err := s.repo.Create(ctx, impersonation); err != nil {
+		return nil, "", err
+	}
+
+	token, err := jwt.GenerateToken(jwt.TokenParams{
+		UserID:  target.ID.String(),
+		Email:   target.Email,
+		Role:    target.Role,
+		Version: target.TokenVersion,
+		ActorID: actorID.String(),
+		ID:      impersonation.ID.String(),
+		TTL:     s.ttl,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return impersonation, token, nil
+}
+
+// checkTarget refuses targets holding admin-level permissions. Roles are
+// custom, so this goes by what the target's role grants, not its name: an
+// admin acting as another admin would only blur the audit trail, and
+// acting as someone with permissions the actor lacks would escalate.
+func (s *impersonationService) checkTarget(ctx context.Context, actorID uuid.UUID, target *models.User) error {
+	actor, err := s.userRepo.FindByID(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	targetPermissions, err := s.permissions.RolePermissions(ctx, target.Role)
+	if err != nil {
+		return err
+	}
+	actorPermissions, err := s.permissions.RolePermissions(ctx, actor.Role)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(targetPermissions, PermissionImpersonate) || !containsAll(actorPermissions, targetPermissions) {
+		return ErrCannotImpersonateAdmin
+	}
+	return nil
+}
+
+func (s *impersonationService) List(ctx context.Context, filter repository.ImpersonationFilter, page, perPage int) ([]models.Impersonation, int64, error) {
+	return s.repo.FindAll(ctx, filter, page, perPage)
+}
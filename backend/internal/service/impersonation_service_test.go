@@ -0,0 +1,122 @@
+package service
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeImpersonationRepo struct {
+	repository.ImpersonationRepository
+	created []*models.Impersonation
+}
+
+func (r *fakeImpersonationRepo) Create(ctx context.Context, impersonation *models.Impersonation) error {
+	r.created = append(r.created, impersonation)
+	return nil
+}
+
+// fakePermissions grants each role a fixed list of permissions
+type fakePermissions map[string][]string
+
+func (p fakePermissions) RolePermissions(ctx context.Context, role string) ([]string, error) {
+	return p[role], nil
+}
+
+func (p fakePermissions) Authorize(ctx context.Context, principal *Principal, required []string) (bool, error) {
+	return containsAll(p[principal.Role], required), nil
+}
+
+func TestImpersonationTargets(t *testing.T) {
+	useTestKeys(t)
+	permissions := fakePermissions{
+		models.RoleAdmin: {"users:read", "users:write", "users:impersonate", "roles:write", "products:read"},
+		"support":        {"users:read", "users:impersonate", "products:read"},
+		"superuser":      {"users:read", "users:write", "users:impersonate", "roles:write", "products:read"},
+		"role-manager":   {"users:read", "roles:write"},
+		models.RoleUser:  {"products:read"},
+	}
+
+	tests := []struct {
+		name       string
+		actorRole  string
+		targetRole string
+		want       error
+	}{
+		{"admin impersonates a user", models.RoleAdmin, models.RoleUser, nil},
+		{"support impersonates a user", "support", models.RoleUser, nil},
+		{"admin target", "support", models.RoleAdmin, ErrCannotImpersonateAdmin},
+		{"custom role that may impersonate", models.RoleAdmin, "superuser", ErrCannotImpersonateAdmin},
+		{"target holds permissions the actor lacks", "support", "role-manager", ErrCannotImpersonateAdmin},
+		{"unknown role grants nothing", "support", "retired", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := activeUser()
+			actor.Role = tt.actorRole
+			target := activeUser()
+			target.Role = tt.targetRole
+			repo := &fakeImpersonationRepo{}
+			s := NewImpersonationService(repo, newFakeUserRepo(actor, target), permissions, time.Minute)
+
+			impersonation, token, err := s.Start(context.Background(), actor.ID, target.ID, "ticket 42", ClientInfo{})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if len(repo.created) != 0 {
+					t.Fatal("a refused impersonation was recorded")
+				}
+				return
+			}
+
+			claims, err := jwt.ParseToken(token)
+			if err != nil {
+				t.Fatalf("token is invalid: %v", err)
+			}
+			if claims.UserID != target.ID.String() || claims.ID != impersonation.ID.String() {
+				t.Fatalf("token is for %s (%s)", claims.UserID, claims.ID)
+			}
+			if len(repo.created) != 1 {
+				t.Fatalf("%d impersonations recorded, want 1", len(repo.created))
+			}
+		})
+	}
+}
+
+func TestImpersonationRejects(t *testing.T) {
+	actor := activeUser()
+	actor.Role = models.RoleAdmin
+	pending := activeUser()
+	pending.Status = models.UserStatusPending
+	disabled := activeUser()
+	disabled.IsActive = false
+	s := NewImpersonationService(&fakeImpersonationRepo{}, newFakeUserRepo(actor, pending, disabled), fakePermissions{
+		models.RoleAdmin: {"users:impersonate"},
+	}, time.Minute)
+
+	tests := []struct {
+		name   string
+		target uuid.UUID
+		reason string
+		want   error
+	}{
+		{"no reason", pending.ID, "  ", ErrImpersonationReasonRequired},
+		{"self", actor.ID, "testing", ErrCannotImpersonateSelf},
+		{"pending", pending.ID, "testing", ErrImpersonationTargetInactive},
+		{"disabled", disabled.ID, "testing", ErrImpersonationTargetInactive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := s.Start(context.Background(), actor.ID, tt.target, tt.reason, ClientInfo{}); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/mailer"
+	"backend/internal/models"
+	"backend/internal/repository"
+	jwt "backend/pkg"
+	"backend/pkg/password"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidInvitation  = errors.New("invalid or expired invitation")
+	ErrInvitationAccepted = errors.New("invitation was already accepted")
+)
+
+type InvitationOptions struct {
+	TTL    time.Duration
+	AppURL string
+}
+
+type InvitationService interface {
+	// Invite creates user as pending and mails them a link to set a password
+	Invite(ctx context.Context, user *models.User, invitedBy uuid.UUID) (*models.Invitation, error)
+	ListPending(ctx context.Context, page, perPage int) ([]models.Invitation, int64, error)
+	Resend(ctx context.Context, id generated.IdParam) (*models.Invitation, error)
+	Revoke(ctx context.Context, id generated.IdParam) error
+	Accept(ctx context.Context, req *generated.AcceptInvitationRequest) error
+}
+
+type invitationService struct {
+	repo     repository.InvitationRepository
+	userRepo repository.UserRepository
+	roleRepo repository.RoleRepository
+	cache    *cache.RedisCache
+	tokens   TokenService
+	mailer   mailer.Mailer
+	policy   *password.Policy
+	opts     InvitationOptions
+}
+
+func NewInvitationService(repo repository.InvitationRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, cache *cache.RedisCache, tokens TokenService, mail mailer.Mailer, policy *password.Policy, opts InvitationOptions) InvitationService {
+	return &invitationService{
+		repo:     repo,
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		cache:    cache,
+		tokens:   tokens,
+		mailer:   mail,
+		policy:   policy,
+		opts:     opts,
+	}
+}
+
+func (s *invitationService) Invite(ctx context.Context, user *models.User, invitedBy uuid.UUID) (*models.Invitation, error) {
+	_, err := s.userRepo.FindByEmail(ctx, user.Email)
+	if err == nil {
+		return nil, ErrEmailTaken
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err := checkRole(ctx, s.roleRepo, user.Role); err != nil {
+		return nil, err
+	}
+
+	// No password until the invitee picks one, so the account cannot log in
+	user.Password = ""
+	user.Status = models.UserStatusPending
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	invitation := &models.Invitation{UserID: user.ID, User: *user}
+	if invitedBy != uuid.Nil {
+		invitation.InvitedBy = &invitedBy
+	}
+
+	token, err := s.newToken(invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		// Without an invitation the pending user could never be activated
+		if delErr := s.userRepo.Delete(ctx, user.ID); delErr != nil {
+			log.Printf("Warning: failed to remove user %s after a failed invitation: %v", user.ID, delErr)
+		}
+		return nil, err
+	}
+
+	s.invalidateUsers(ctx)
+	go s.sendInvitation(invitation, token)
+
+	return invitation, nil
+}
+
+func (s *invitationService) ListPending(ctx context.Context, page, perPage int) ([]models.Invitation, int64, error) {
+	return s.repo.FindPending(ctx, page, perPage)
+}
+
+// Resend issues a new link with a fresh expiry; earlier links stop working
+func (s *invitationService) Resend(ctx context.Context, id generated.IdParam) (*models.Invitation, error) {
+	invitation, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.IsAccepted() {
+		return nil, ErrInvitationAccepted
+	}
+
+	token, err := s.newToken(invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	go s.sendInvitation(invitation, token)
+
+	return invitation, nil
+}
+
+// Revoke deletes the pending user, and the invitation with it, so the
+// address can be invited again
+func (s *invitationService) Revoke(ctx context.Context, id generated.IdParam) error {
+	invitation, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if invitation.IsAccepted() {
+		return ErrInvitationAccepted
+	}
+
+	if err := s.userRepo.Delete(ctx, invitation.UserID); err != nil {
+		return err
+	}
+
+	s.invalidateUsers(ctx)
+	if s.cache != nil {
+		s.cache.Delete(ctx, fmt.Sprintf("user:%d", invitation.UserID))
+	}
+
+	return nil
+}
+
+// Accept sets the invitee's password and activates the account. The
+// emailed link also proves the address, so it counts as verified.
+func (s *invitationService) Accept(ctx context.Context, req *generated.AcceptInvitationRequest) error {
+	claims, err := jwt.ParseActionToken(req.Token, models.TokenPurposeInvitation)
+	if err != nil {
+		return ErrInvalidInvitation
+	}
+
+	hash := hashToken(req.Token)
+	invitation, err := s.repo.FindByHash(ctx, hash)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrInvalidInvitation
+		}
+		return err
+	}
+
+	user := &invitation.User
+	// The address may have been corrected by an admin since the link was sent
+	if invitation.ID.String() != claims.Subject || !user.IsPending() || !strings.EqualFold(user.Email, claims.Email) {
+		return ErrInvalidInvitation
+	}
+
+	// Check the policy first so a rejected password does not burn the link
+	if err := s.policy.Validate(req.Password, user.Email, user.Name); err != nil {
+		return err
+	}
+
+	accepted, err := s.repo.MarkAccepted(ctx, invitation.ID, hash)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return ErrInvalidInvitation
+	}
+
+	if err := user.HashPassword(req.Password); err != nil {
+		return err
+	}
+	now := time.Now()
+	user.Status = models.UserStatusActive
+	if user.EmailVerifiedAt == nil {
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.invalidateUsers(ctx)
+	if s.cache != nil {
+		s.cache.Delete(ctx, fmt.Sprintf("user:%d", user.ID))
+	}
+
+	return s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+// newToken signs a link for the invitation's current address and stores
+// its hash in place of the previous one
+func (s *invitationService) newToken(invitation *models.Invitation) (string, error) {
+	if invitation.ID == uuid.Nil {
+		invitation.ID = uuid.Must(uuid.NewV7())
+	}
+
+	token, err := jwt.GenerateActionToken(jwt.ActionParams{
+		Purpose: models.TokenPurposeInvitation,
+		Subject: invitation.ID.String(),
+		Email:   invitation.User.Email,
+		TTL:     s.opts.TTL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	invitation.TokenHash = hashToken(token)
+	invitation.ExpiresAt = now.Add(s.opts.TTL)
+	invitation.SentAt = now
+	return token, nil
+}
+
+func (s *invitationService) sendInvitation(invitation *models.Invitation, token string) {
+	link := fmt.Sprintf("%s/accept-invitation?token=%s", s.opts.AppURL, url.QueryEscape(token))
+	deliverMail(s.mailer, mailer.Message{
+		To:      invitation.User.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nAn account has been created for you. Open the link below to choose a password. It expires in %s and works once.\n\n%s\n",
+			invitation.User.Name, s.opts.TTL, link,
+		),
+	})
+}
+
+func (s *invitationService) invalidateUsers(ctx context.Context) {
+	if s.cache != nil {
+		s.cache.DeletePattern(ctx, "users:list:*")
+	}
+}
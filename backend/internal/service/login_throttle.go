@@ -0,0 +1,121 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Failures below this count are not delayed at all
+	backoffThreshold = 3
+	backoffBase      = time.Second
+	backoffMax       = time.Minute
+)
+
+// LockedOutError rejects a login attempt made before RetryAfter has passed
+type LockedOutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedOutError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+// LoginThrottle tracks failed logins per email address across all client
+// IPs. Each failure past backoffThreshold doubles the wait before the next
+// attempt; MaxAttempts failures lock the address for LockoutDuration.
+type LoginThrottle interface {
+	Check(ctx context.Context, email string) error
+	RecordFailure(ctx context.Context, email string) error
+	Reset(ctx context.Context, email string) error
+}
+
+type LoginThrottleOptions struct {
+	MaxAttempts     int
+	LockoutDuration time.Duration
+}
+
+// loginFailures is the stored state for one email address
+type loginFailures struct {
+	Count         int       `json:"count"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+type loginThrottle struct {
+	store cache.Store
+	opts  LoginThrottleOptions
+	now   func() time.Time
+}
+
+func NewLoginThrottle(store cache.Store, opts LoginThrottleOptions) LoginThrottle {
+	return &loginThrottle{
+		store: store,
+		opts:  opts,
+		now:   time.Now,
+	}
+}
+
+func loginFailuresKey(email string) string {
+	return fmt.Sprintf("auth:login:failures:%s", strings.ToLower(strings.TrimSpace(email)))
+}
+
+func (t *loginThrottle) load(ctx context.Context, email string) (loginFailures, error) {
+	var state loginFailures
+	err := t.store.Get(ctx, loginFailuresKey(email), &state)
+	if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		return state, err
+	}
+	return state, nil
+}
+
+func (t *loginThrottle) Check(ctx context.Context, email string) error {
+	state, err := t.load(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if wait := state.NextAttemptAt.Sub(t.now()); wait > 0 {
+		return &LockedOutError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure is read-modify-write, so concurrent failures may be
+// counted once; the backoff still grows with every sequential attempt
+func (t *loginThrottle) RecordFailure(ctx context.Context, email string) error {
+	state, err := t.load(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	state.Count++
+	delay := t.delay(state.Count)
+	state.NextAttemptAt = t.now().Add(delay)
+
+	// Forget failures once the lockout window has passed without new ones
+	ttl := max(t.opts.LockoutDuration, delay)
+	return t.store.Set(ctx, loginFailuresKey(email), state, ttl)
+}
+
+func (t *loginThrottle) Reset(ctx context.Context, email string) error {
+	return t.store.Delete(ctx, loginFailuresKey(email))
+}
+
+func (t *loginThrottle) delay(failures int) time.Duration {
+	if failures >= t.opts.MaxAttempts {
+		return t.opts.LockoutDuration
+	}
+	if failures < backoffThreshold {
+		return 0
+	}
+
+	shift := failures - backoffThreshold
+	if shift >= 16 {
+		return backoffMax
+	}
+	return min(backoffBase<<shift, backoffMax)
+}
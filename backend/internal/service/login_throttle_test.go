@@ -0,0 +1,134 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestThrottle returns a throttle whose clock only moves when told to
+func newTestThrottle(opts LoginThrottleOptions) (*loginThrottle, func(time.Duration)) {
+	now := time.Now()
+	t := NewLoginThrottle(cache.NewMemoryCache(), opts).(*loginThrottle)
+	t.now = func() time.Time { return now }
+	return t, func(d time.Duration) { now = now.Add(d) }
+}
+
+// retryAfter is the wait Check reports, or zero if the attempt is allowed
+func retryAfter(t *testing.T, throttle LoginThrottle, email string) time.Duration {
+	t.Helper()
+	err := throttle.Check(context.Background(), email)
+	if err == nil {
+		return 0
+	}
+	var locked *LockedOutError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Check: %v", err)
+	}
+	return locked.RetryAfter
+}
+
+func TestLoginBackoff(t *testing.T) {
+	ctx := context.Background()
+	throttle, advance := newTestThrottle(LoginThrottleOptions{MaxAttempts: 10, LockoutDuration: 15 * time.Minute})
+
+	// The first two failures are free, then the wait doubles up to a minute
+	want := []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		16 * time.Second, 32 * time.Second, time.Minute}
+	for i, delay := range want {
+		if err := throttle.RecordFailure(ctx, "jane@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if got := retryAfter(t, throttle, "jane@example.com"); got != delay {
+			t.Fatalf("after %d failures: wait %s, want %s", i+1, got, delay)
+		}
+		if delay == 0 {
+			continue
+		}
+		advance(delay - time.Millisecond)
+		if retryAfter(t, throttle, "jane@example.com") == 0 {
+			t.Fatalf("after %d failures: allowed before the wait was over", i+1)
+		}
+		advance(time.Millisecond)
+		if got := retryAfter(t, throttle, "jane@example.com"); got != 0 {
+			t.Fatalf("after %d failures: still waiting %s", i+1, got)
+		}
+	}
+
+	// Addresses are throttled apart, ignoring case and spaces
+	if got := retryAfter(t, throttle, "john@example.com"); got != 0 {
+		t.Fatalf("another address waits %s", got)
+	}
+	if err := throttle.RecordFailure(ctx, " Jane@Example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if retryAfter(t, throttle, "jane@example.com") == 0 {
+		t.Fatal("the failure counted for another address")
+	}
+}
+
+func TestLoginBackoffCap(t *testing.T) {
+	throttle, _ := newTestThrottle(LoginThrottleOptions{MaxAttempts: 100, LockoutDuration: time.Hour})
+	for _, failures := range []int{9, 10, 18, 19, 50, 99} {
+		if got := throttle.delay(failures); got != backoffMax {
+			t.Fatalf("delay(%d) = %s, want %s", failures, got, backoffMax)
+		}
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	throttle, advance := newTestThrottle(LoginThrottleOptions{MaxAttempts: 5, LockoutDuration: 15 * time.Minute})
+
+	for i := 0; i < 5; i++ {
+		advance(time.Hour)
+		if err := throttle.RecordFailure(ctx, "jane@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := retryAfter(t, throttle, "jane@example.com"); got != 15*time.Minute {
+		t.Fatalf("locked for %s, want 15m", got)
+	}
+
+	advance(15 * time.Minute)
+	if got := retryAfter(t, throttle, "jane@example.com"); got != 0 {
+		t.Fatalf("still locked for %s", got)
+	}
+
+	// Failing again straight after a lockout locks the address again
+	if err := throttle.RecordFailure(ctx, "jane@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if got := retryAfter(t, throttle, "jane@example.com"); got != 15*time.Minute {
+		t.Fatalf("locked for %s, want 15m", got)
+	}
+}
+
+func TestLoginThrottleReset(t *testing.T) {
+	ctx := context.Background()
+	throttle, _ := newTestThrottle(LoginThrottleOptions{MaxAttempts: 5, LockoutDuration: 15 * time.Minute})
+
+	for i := 0; i < 5; i++ {
+		if err := throttle.RecordFailure(ctx, "jane@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A successful login forgives earlier failures, so counting starts over
+	if err := throttle.Reset(ctx, "Jane@Example.com"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if got := retryAfter(t, throttle, "jane@example.com"); got != 0 {
+			t.Fatalf("after reset and %d failures: wait %s", i, got)
+		}
+		if err := throttle.RecordFailure(ctx, "jane@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := retryAfter(t, throttle, "jane@example.com"); got != 0 {
+		t.Fatalf("after reset and 2 failures: wait %s", got)
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultTokenLifetimeDays = 90
+
+var ErrInvalidScope = errors.New("invalid scope")
+
+type PersonalAccessTokenService interface {
+	// Create returns the stored token and the raw value, which is never
+	// available again
+	Create(ctx context.Context, userID uuid.UUID, req *generated.CreatePersonalAccessTokenRequest) (*models.PersonalAccessToken, string, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type personalAccessTokenService struct {
+	repo  repository.PersonalAccessTokenRepository
+	store cache.Store
+}
+
+func NewPersonalAccessTokenService(repo repository.PersonalAccessTokenRepository, store cache.Store) PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		repo:  repo,
+		store: store,
+	}
+}
+
+func (s *personalAccessTokenService) Create(ctx context.Context, userID uuid.UUID, req *generated.CreatePersonalAccessTokenRequest) (*models.PersonalAccessToken, string, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	days := defaultTokenLifetimeDays
+	if req.ExpiresInDays != nil {
+		days = *req.ExpiresInDays
+	}
+
+	raw, err := generateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	token := models.PersonalAccessTokenPrefix + raw
+
+	pat := &models.PersonalAccessToken{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		TokenPrefix: token[:len(models.PersonalAccessTokenPrefix)+8],
+		TokenHash:   hashToken(token),
+		Scopes:      strings.Join(scopes, " "),
+		ExpiresAt:   time.Now().AddDate(0, 0, days),
+	}
+
+	if err := s.repo.Create(ctx, pat); err != nil {
+		return nil, "", err
+	}
+
+	return pat, token, nil
+}
+
+func (s *personalAccessTokenService) List(ctx context.Context, userID uuid.UUID) ([]models.PersonalAccessToken, error) {
+	return s.repo.FindActiveByUser(ctx, userID)
+}
+
+func (s *personalAccessTokenService) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	pat, err := s.repo.FindByIDForUser(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Revoke(ctx, pat.ID); err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, personalAccessTokenKey(pat.TokenHash))
+}
+
+// normalizeScopes dedupes and sorts the requested scopes and rejects any
+// that no route in the API requires
+func normalizeScopes(requested []string) ([]string, error) {
+	known := knownScopes()
+
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		scope = strings.TrimSpace(scope)
+		if !known[scope] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	slices.Sort(scopes)
+	return scopes, nil
+}
+
+// knownScopes are the permissions the spec declares
+func knownScopes() map[string]bool {
+	known := make(map[string]bool, len(generated.Permissions))
+	for _, info := range generated.Permissions {
+		known[info.Name] = true
+	}
+	return known
+}
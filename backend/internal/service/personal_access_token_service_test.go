@@ -0,0 +1,67 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCreatePersonalAccessTokenLifetime(t *testing.T) {
+	scope := generated.Permissions[0].Name
+	week := 7
+
+	tests := []struct {
+		name string
+		days *int
+		want time.Duration
+	}{
+		{"default", nil, 90 * 24 * time.Hour},
+		{"requested", &week, 7 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePATRepo{}
+			s := NewPersonalAccessTokenService(repo, cache.NewMemoryCache())
+
+			before := time.Now()
+			pat, raw, err := s.Create(context.Background(), uuid.New(), &generated.CreatePersonalAccessTokenRequest{
+				Name:          " ci ",
+				Scopes:        []string{scope},
+				ExpiresInDays: tt.days,
+			})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			after := time.Now()
+
+			if pat.ExpiresAt.Before(before.Add(tt.want)) || pat.ExpiresAt.After(after.Add(tt.want)) {
+				t.Fatalf("expires %s after creation, want %s", pat.ExpiresAt.Sub(before), tt.want)
+			}
+			// Only the hash is stored
+			if !strings.HasPrefix(raw, models.PersonalAccessTokenPrefix) || pat.TokenHash != hashToken(raw) || len(repo.tokens) != 1 {
+				t.Fatalf("stored %+v for %q", pat, raw)
+			}
+			if pat.Name != "ci" || pat.Scopes != scope {
+				t.Fatalf("name %q, scopes %q", pat.Name, pat.Scopes)
+			}
+		})
+	}
+}
+
+func TestCreatePersonalAccessTokenScopes(t *testing.T) {
+	s := NewPersonalAccessTokenService(&fakePATRepo{}, cache.NewMemoryCache())
+
+	for _, scopes := range [][]string{nil, {"nonexistent:read"}, {models.RoleAdmin}} {
+		_, _, err := s.Create(context.Background(), uuid.New(), &generated.CreatePersonalAccessTokenRequest{Name: "ci", Scopes: scopes})
+		if !errors.Is(err, ErrInvalidScope) {
+			t.Fatalf("scopes %v: err = %v, want ErrInvalidScope", scopes, err)
+		}
+	}
+}
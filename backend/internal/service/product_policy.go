@@ -0,0 +1,37 @@
+package service
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+)
+
+// PermissionManageProducts lets a role update and delete products it does not own
+const PermissionManageProducts = "products:manage"
+
+var ErrProductForbidden = errors.New("only the product owner can change this product")
+
+// ProductPolicy decides who may change an existing product
+type ProductPolicy interface {
+	// CanModify reports whether the principal may update or delete the product
+	CanModify(ctx context.Context, principal *Principal, product *models.Product) (bool, error)
+}
+
+type productPolicy struct {
+	permissions PermissionResolver
+}
+
+// NewProductPolicy allows the product's owner and roles granted products:manage
+func NewProductPolicy(permissions PermissionResolver) ProductPolicy {
+	return &productPolicy{permissions: permissions}
+}
+
+func (p *productPolicy) CanModify(ctx context.Context, principal *Principal, product *models.Product) (bool, error) {
+	if principal == nil {
+		return false, nil
+	}
+	if product.OwnerID != nil && product.OwnerID.String() == principal.UserID {
+		return true, nil
+	}
+	return p.permissions.Authorize(ctx, principal, []string{PermissionManageProducts})
+}
@@ -8,25 +8,32 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type ProductService interface {
 	CreateProduct(ctx context.Context, product *models.Product) error
 	GetProduct(ctx context.Context, id generated.IdParam) (*models.Product, error)
-	ListProducts(ctx context.Context, page, perPage int) ([]models.Product, int64, error)
-	UpdateProduct(ctx context.Context, id generated.IdParam, product *models.Product) error
-	DeleteProduct(ctx context.Context, id generated.IdParam) error
+	// ListProducts lists products, only those owned by ownerID when it is set
+	ListProducts(ctx context.Context, page, perPage int, ownerID *uuid.UUID) ([]models.Product, int64, error)
+	// UpdateProduct and DeleteProduct return ErrProductForbidden when the
+	// policy does not let the principal change the product
+	UpdateProduct(ctx context.Context, principal *Principal, id generated.IdParam, product *models.Product) error
+	DeleteProduct(ctx context.Context, principal *Principal, id generated.IdParam) error
 }
 
 type productService struct {
-	repo  repository.ProductRepository
-	cache *cache.RedisCache
+	repo   repository.ProductRepository
+	cache  *cache.RedisCache
+	policy ProductPolicy
 }
 
-func NewProductService(repo repository.ProductRepository, cache *cache.RedisCache) ProductService {
+func NewProductService(repo repository.ProductRepository, cache *cache.RedisCache, policy ProductPolicy) ProductService {
 	return &productService{
-		repo:  repo,
-		cache: cache,
+		repo:   repo,
+		cache:  cache,
+		policy: policy,
 	}
 }
 
@@ -68,8 +75,11 @@ func (s *productService) GetProduct(ctx context.Context, id generated.IdParam) (
 	return product, nil
 }
 
-func (s *productService) ListProducts(ctx context.Context, page, perPage int) ([]models.Product, int64, error) {
+func (s *productService) ListProducts(ctx context.Context, page, perPage int, ownerID *uuid.UUID) ([]models.Product, int64, error) {
 	cacheKey := fmt.Sprintf("products:list:%d:%d", page, perPage)
+	if ownerID != nil {
+		cacheKey = fmt.Sprintf("products:list:owner:%s:%d:%d", ownerID, page, perPage)
+	}
 
 	// Try to get from cache
 	if s.cache != nil {
@@ -83,7 +93,7 @@ func (s *productService) ListProducts(ctx context.Context, page, perPage int) ([
 	}
 
 	// Get from database
-	products, total, err := s.repo.FindAll(ctx, page, perPage)
+	products, total, err := s.repo.FindAll(ctx, page, perPage, ownerID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -100,13 +110,14 @@ func (s *productService) ListProducts(ctx context.Context, page, perPage int) ([
 	return products, total, nil
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, id generated.IdParam, product *models.Product) error {
-	existing, err := s.repo.FindByID(ctx, id)
+func (s *productService) UpdateProduct(ctx context.Context, principal *Principal, id generated.IdParam, product *models.Product) error {
+	existing, err := s.findModifiable(ctx, principal, id)
 	if err != nil {
 		return err
 	}
 
 	product.ID = existing.ID
+	product.OwnerID = existing.OwnerID
 	product.CreatedAt = existing.CreatedAt
 
 	if err := s.repo.Update(ctx, product); err != nil {
@@ -122,16 +133,38 @@ func (s *productService) UpdateProduct(ctx context.Context, id generated.IdParam
 	return nil
 }
 
-func (s *productService) DeleteProduct(ctx context.Context, id generated.IdParam) error {
+func (s *productService) DeleteProduct(ctx context.Context, principal *Principal, id generated.IdParam) error {
+	if _, err := s.findModifiable(ctx, principal, id); err != nil {
+		return err
+	}
+
 	if err := s.repo.Delete(ctx, id); err != nil {
 		return err
 	}
 
 	// Invalidate cache
 	if s.cache != nil {
-		s.cache.Delete(ctx, fmt.Sprintf("product:%d", id))
+		s.cache.Delete(ctx, fmt.Sprintf("product:%s", id))
 		s.cache.DeletePattern(ctx, "products:list:*")
 	}
 
 	return nil
 }
+
+// findModifiable loads a product the principal is allowed to change
+func (s *productService) findModifiable(ctx context.Context, principal *Principal, id generated.IdParam) (*models.Product, error) {
+	product, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := s.policy.CanModify(ctx, principal, product)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrProductForbidden
+	}
+
+	return product, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var productPermissions = fakePermissions{
+	models.RoleAdmin: {"products:read", "products:write", PermissionManageProducts},
+	models.RoleUser:  {"products:read", "products:write"},
+}
+
+func TestProductChangesFollowOwnership(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	ownerPrincipal := &Principal{UserID: owner.String(), Role: models.RoleUser}
+	otherPrincipal := &Principal{UserID: other.String(), Role: models.RoleUser}
+	manager := &Principal{UserID: uuid.NewString(), Role: models.RoleAdmin}
+
+	tests := []struct {
+		name      string
+		owner     *uuid.UUID
+		principal *Principal
+		want      error
+	}{
+		{"owner", &owner, ownerPrincipal, nil},
+		{"another user", &owner, otherPrincipal, ErrProductForbidden},
+		{"products:manage", &owner, manager, nil},
+		{"no principal", &owner, nil, ErrProductForbidden},
+		{"unowned product, user", nil, ownerPrincipal, ErrProductForbidden},
+		{"unowned product, products:manage", nil, manager, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" updates", func(t *testing.T) {
+			product := &models.Product{Name: "Lamp", OwnerID: tt.owner}
+			repo := newFakeProductRepo(product)
+			s := NewProductService(repo, nil, NewProductPolicy(productPermissions))
+
+			// The owner cannot be changed through an update
+			err := s.UpdateProduct(context.Background(), tt.principal, product.ID, &models.Product{Name: "Desk lamp", OwnerID: &other})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			stored := repo.products[product.ID]
+			if tt.want != nil {
+				if stored.Name != "Lamp" {
+					t.Fatal("a refused update was stored")
+				}
+				return
+			}
+			if stored.Name != "Desk lamp" || stored.OwnerID != tt.owner {
+				t.Fatalf("stored %+v", stored)
+			}
+		})
+
+		t.Run(tt.name+" deletes", func(t *testing.T) {
+			product := &models.Product{Name: "Lamp", OwnerID: tt.owner}
+			repo := newFakeProductRepo(product)
+			s := NewProductService(repo, nil, NewProductPolicy(productPermissions))
+
+			err := s.DeleteProduct(context.Background(), tt.principal, product.ID)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if _, kept := repo.products[product.ID]; kept != (tt.want != nil) {
+				t.Fatalf("product kept = %v", kept)
+			}
+		})
+	}
+}
+
+func TestListProductsByOwner(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	repo := newFakeProductRepo(
+		&models.Product{Name: "Mine", OwnerID: &owner},
+		&models.Product{Name: "Theirs", OwnerID: &other},
+		&models.Product{Name: "Unowned"},
+	)
+	s := NewProductService(repo, nil, NewProductPolicy(productPermissions))
+
+	products, total, err := s.ListProducts(context.Background(), 1, 10, &owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(products) != 1 || products[0].Name != "Mine" {
+		t.Fatalf("listed %d: %+v", total, products)
+	}
+
+	if _, total, _ := s.ListProducts(context.Background(), 1, 10, nil); total != 3 {
+		t.Fatalf("unfiltered list has %d products, want 3", total)
+	}
+}
@@ -0,0 +1,488 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// rolePermissionsTTL bounds how long a changed role keeps its old
+// permissions on instances that do not share the cache
+const rolePermissionsTTL = time.Minute
+
+// PermissionImpersonate lets a role act as other users
+const PermissionImpersonate = "users:impersonate"
+
+var (
+	ErrUnknownRole            = errors.New("unknown role")
+	ErrUnknownPermission      = errors.New("unknown permission")
+	ErrInvalidRoleName        = errors.New("role names are 2 to 50 lowercase letters, digits, - or _, starting with a letter")
+	ErrInvalidPermissionName  = errors.New("permission names look like resource:action")
+	ErrRoleExists             = errors.New("role already exists")
+	ErrPermissionExists       = errors.New("permission already exists")
+	ErrBuiltinRole            = errors.New("built-in roles cannot be deleted")
+	ErrAdminRolePermissions   = errors.New("the admin role always holds every permission")
+	ErrRoleInUse              = errors.New("role is still assigned to users")
+	ErrBuiltinPermission      = errors.New("permissions checked by the API cannot be deleted")
+	ErrPermissionNotGrantable = errors.New("you can only grant or remove permissions you hold")
+)
+
+var (
+	roleNamePattern       = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+	permissionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*:[a-z][a-z0-9_-]*$`)
+)
+
+// builtinRoles are created on first start. Only admin's permissions are
+// enforced afterwards; the others keep whatever admins assign them.
+var builtinRoles = []struct {
+	name        string
+	description string
+	permissions []string
+}{
+	{models.RoleAdmin, "Full access", nil},
+	{models.RoleUser, "Default role of new accounts", []string{"profile:read", "products:read", "products:write"}},
+	{models.RoleGuest, "Product access only", []string{"products:read", "products:write"}},
+}
+
+// PermissionResolver answers what a caller may do
+type PermissionResolver interface {
+	// RolePermissions returns the names of the permissions a role grants,
+	// none for unknown roles
+	RolePermissions(ctx context.Context, role string) ([]string, error)
+	// Authorize reports whether the caller holds every required permission
+	Authorize(ctx context.Context, principal *Principal, required []string) (bool, error)
+}
+
+// RoleService manages roles, permissions and role-permission assignments
+type RoleService interface {
+	PermissionResolver
+	// Sync creates the permissions declared by the spec and the built-in
+	// roles, and grants the admin role every permission
+	Sync(ctx context.Context) error
+	ListRoles(ctx context.Context) ([]models.Role, error)
+	GetRole(ctx context.Context, name string) (*models.Role, error)
+	// CreateRole and UpdateRole refuse to grant or remove permissions the
+	// principal does not hold, and UpdateRole refuses the principal's own role
+	CreateRole(ctx context.Context, principal *Principal, role *models.Role, permissions []string) error
+	// UpdateRole changes the description and, when permissions is not nil,
+	// replaces the role's permissions
+	UpdateRole(ctx context.Context, principal *Principal, name string, description *string, permissions []string) (*models.Role, error)
+	DeleteRole(ctx context.Context, name string) error
+	ListPermissions(ctx context.Context) ([]models.Permission, error)
+	CreatePermission(ctx context.Context, permission *models.Permission) error
+	DeletePermission(ctx context.Context, name string) error
+}
+
+type roleService struct {
+	roleRepo       repository.RoleRepository
+	permissionRepo repository.PermissionRepository
+	store          cache.Store
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, permissionRepo repository.PermissionRepository, store cache.Store) RoleService {
+	return &roleService{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		store:          store,
+	}
+}
+
+func rolePermissionsKey(role string) string {
+	return fmt.Sprintf("rbac:role:%s", role)
+}
+
+func (s *roleService) RolePermissions(ctx context.Context, role string) ([]string, error) {
+	var permissions []string
+	err := s.store.Get(ctx, rolePermissionsKey(role), &permissions)
+	if err == nil {
+		return permissions, nil
+	}
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, err
+	}
+
+	found, err := s.roleRepo.FindByName(ctx, role)
+	switch {
+	case err == nil:
+		permissions = found.PermissionNames()
+	case err == gorm.ErrRecordNotFound:
+		permissions = []string{}
+	default:
+		return nil, err
+	}
+
+	if err := s.store.Set(ctx, rolePermissionsKey(role), permissions, rolePermissionsTTL); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// Authorize checks the caller's role and, for personal access tokens, the
+// token's scopes too. A scope naming a role stands for that role's
+// permissions, as tokens created before permissions existed carry them.
+func (s *roleService) Authorize(ctx context.Context, principal *Principal, required []string) (bool, error) {
+	if len(required) == 0 {
+		return true, nil
+	}
+
+	granted, err := s.RolePermissions(ctx, principal.Role)
+	if err != nil {
+		return false, err
+	}
+	if !containsAll(granted, required) {
+		return false, nil
+	}
+	if !principal.IsPersonalAccessToken() {
+		return true, nil
+	}
+
+	var scoped []string
+	for _, scope := range principal.Scopes {
+		if strings.Contains(scope, ":") {
+			scoped = append(scoped, scope)
+			continue
+		}
+		permissions, err := s.RolePermissions(ctx, scope)
+		if err != nil {
+			return false, err
+		}
+		scoped = append(scoped, permissions...)
+	}
+	return containsAll(scoped, required), nil
+}
+
+func containsAll(granted, required []string) bool {
+	for _, permission := range required {
+		if !slices.Contains(granted, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *roleService) Sync(ctx context.Context) error {
+	declared := make(map[string]bool, len(generated.Permissions))
+	for _, info := range generated.Permissions {
+		declared[info.Name] = true
+
+		permission, err := s.permissionRepo.FindByName(ctx, info.Name)
+		if err == gorm.ErrRecordNotFound {
+			permission = &models.Permission{Name: info.Name, Description: info.Description, Builtin: true}
+			if err := s.permissionRepo.Create(ctx, permission); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if permission.Description != info.Description || !permission.Builtin {
+			permission.Description = info.Description
+			permission.Builtin = true
+			if err := s.permissionRepo.Update(ctx, permission); err != nil {
+				return err
+			}
+		}
+	}
+
+	all, err := s.permissionRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	// Permissions the spec no longer declares stay with their grants but
+	// can now be deleted
+	for i := range all {
+		if all[i].Builtin && !declared[all[i].Name] {
+			all[i].Builtin = false
+			if err := s.permissionRepo.Update(ctx, &all[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, builtin := range builtinRoles {
+		role, err := s.roleRepo.FindByName(ctx, builtin.name)
+		if err == gorm.ErrRecordNotFound {
+			role = &models.Role{Name: builtin.name, Description: builtin.description, Builtin: true}
+			if err := s.roleRepo.Create(ctx, role); err != nil {
+				return err
+			}
+			if builtin.name != models.RoleAdmin {
+				permissions, err := s.permissionRepo.FindByNames(ctx, builtin.permissions)
+				if err != nil {
+					return err
+				}
+				if err := s.roleRepo.ReplacePermissions(ctx, role, permissions); err != nil {
+					return err
+				}
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if builtin.name == models.RoleAdmin && !samePermissions(role.Permissions, all) {
+			if err := s.roleRepo.ReplacePermissions(ctx, role, all); err != nil {
+				return err
+			}
+		}
+		s.invalidate(ctx, builtin.name)
+	}
+
+	return nil
+}
+
+// samePermissions reports whether both lists grant the same names
+func samePermissions(a, b []models.Permission) bool {
+	names := make(map[string]bool, len(a))
+	for _, permission := range a {
+		names[permission.Name] = true
+	}
+	other := make(map[string]bool, len(b))
+	for _, permission := range b {
+		if !names[permission.Name] {
+			return false
+		}
+		other[permission.Name] = true
+	}
+	return len(names) == len(other)
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]models.Role, error) {
+	return s.roleRepo.FindAll(ctx)
+}
+
+func (s *roleService) GetRole(ctx context.Context, name string) (*models.Role, error) {
+	return s.roleRepo.FindByName(ctx, name)
+}
+
+func (s *roleService) CreateRole(ctx context.Context, principal *Principal, role *models.Role, permissions []string) error {
+	if !roleNamePattern.MatchString(role.Name) {
+		return ErrInvalidRoleName
+	}
+	if _, err := s.roleRepo.FindByName(ctx, role.Name); err == nil {
+		return ErrRoleExists
+	} else if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	resolved, err := s.resolvePermissions(ctx, permissions)
+	if err != nil {
+		return err
+	}
+	if err := s.checkGrant(ctx, principal, permissionNames(resolved)); err != nil {
+		return err
+	}
+
+	role.Builtin = false
+	role.Permissions = resolved
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return err
+	}
+
+	// An earlier lookup may have cached the name as granting nothing
+	s.invalidate(ctx, role.Name)
+	return nil
+}
+
+func (s *roleService) UpdateRole(ctx context.Context, principal *Principal, name string, description *string, permissions []string) (*models.Role, error) {
+	role, err := s.roleRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if principal != nil && principal.Role == role.Name {
+		return nil, ErrCannotChangeOwnRole
+	}
+
+	if permissions != nil {
+		if role.Name == models.RoleAdmin {
+			return nil, ErrAdminRolePermissions
+		}
+		resolved, err := s.resolvePermissions(ctx, permissions)
+		if err != nil {
+			return nil, err
+		}
+		// Removing a permission is refused like granting it, so callers
+		// cannot strip roles more privileged than their own
+		if err := s.checkGrant(ctx, principal, changedPermissions(role.PermissionNames(), permissionNames(resolved))); err != nil {
+			return nil, err
+		}
+		if err := s.roleRepo.ReplacePermissions(ctx, role, resolved); err != nil {
+			return nil, err
+		}
+	}
+
+	if description != nil {
+		role.Description = *description
+		if err := s.roleRepo.UpdateDescription(ctx, role); err != nil {
+			return nil, err
+		}
+	}
+
+	s.invalidate(ctx, role.Name)
+	return s.roleRepo.FindByName(ctx, name)
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, name string) error {
+	role, err := s.roleRepo.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if role.Builtin {
+		return ErrBuiltinRole
+	}
+
+	users, err := s.roleRepo.CountUsers(ctx, name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(ctx, role); err != nil {
+		return err
+	}
+	s.invalidate(ctx, name)
+	return nil
+}
+
+func (s *roleService) ListPermissions(ctx context.Context) ([]models.Permission, error) {
+	return s.permissionRepo.FindAll(ctx)
+}
+
+// CreatePermission adds a permission for clients and grants it to admin
+func (s *roleService) CreatePermission(ctx context.Context, permission *models.Permission) error {
+	if len(permission.Name) > 100 || !permissionNamePattern.MatchString(permission.Name) {
+		return ErrInvalidPermissionName
+	}
+	if _, err := s.permissionRepo.FindByName(ctx, permission.Name); err == nil {
+		return ErrPermissionExists
+	} else if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	permission.Builtin = false
+	if err := s.permissionRepo.Create(ctx, permission); err != nil {
+		return err
+	}
+
+	admin, err := s.roleRepo.FindByName(ctx, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if err := s.roleRepo.ReplacePermissions(ctx, admin, append(admin.Permissions, *permission)); err != nil {
+		return err
+	}
+	s.invalidate(ctx, models.RoleAdmin)
+	return nil
+}
+
+func (s *roleService) DeletePermission(ctx context.Context, name string) error {
+	permission, err := s.permissionRepo.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if permission.Builtin {
+		return ErrBuiltinPermission
+	}
+
+	if err := s.permissionRepo.Delete(ctx, permission); err != nil {
+		return err
+	}
+
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		s.invalidate(ctx, role.Name)
+	}
+	return nil
+}
+
+// resolvePermissions loads permissions by name, refusing unknown ones
+func (s *roleService) resolvePermissions(ctx context.Context, names []string) ([]models.Permission, error) {
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+
+	permissions, err := s.permissionRepo.FindByNames(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(unique) {
+		for _, name := range unique {
+			if !slices.ContainsFunc(permissions, func(p models.Permission) bool { return p.Name == name }) {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownPermission, name)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// checkGrant refuses permissions the principal does not hold
+func (s *roleService) checkGrant(ctx context.Context, principal *Principal, permissions []string) error {
+	if principal == nil {
+		return ErrPermissionNotGrantable
+	}
+	allowed, err := s.Authorize(ctx, principal, permissions)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionNotGrantable
+	}
+	return nil
+}
+
+func permissionNames(permissions []models.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = permission.Name
+	}
+	return names
+}
+
+// changedPermissions returns the names in only one of the lists
+func changedPermissions(before, after []string) []string {
+	var changed []string
+	for _, name := range before {
+		if !slices.Contains(after, name) {
+			changed = append(changed, name)
+		}
+	}
+	for _, name := range after {
+		if !slices.Contains(before, name) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+func (s *roleService) invalidate(ctx context.Context, role string) {
+	s.store.Delete(ctx, rolePermissionsKey(role))
+}
+
+// checkRole refuses role names no role exists for
+func checkRole(ctx context.Context, roles repository.RoleRepository, name string) error {
+	if _, err := roles.FindByName(ctx, name); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("%w: %q", ErrUnknownRole, name)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/generated"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSyncGrantsAdminEveryPermission(t *testing.T) {
+	ctx := context.Background()
+	permissions := &fakePermissionRepo{}
+	for _, info := range generated.Permissions {
+		permissions.permissions = append(permissions.permissions, models.Permission{Name: info.Name, Description: info.Description, Builtin: true})
+	}
+
+	// As many grants as there are permissions, but not the same ones
+	stale := make([]models.Permission, len(permissions.permissions))
+	copy(stale, permissions.permissions)
+	stale[0] = models.Permission{Name: "legacy:read"}
+	roles := newFakeRoleRepo(models.RoleUser, models.RoleGuest)
+	roles.roles[models.RoleAdmin] = &models.Role{Name: models.RoleAdmin, Builtin: true, Permissions: stale}
+
+	s := NewRoleService(roles, permissions, cache.NewMemoryCache())
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	admin, _ := roles.FindByName(ctx, models.RoleAdmin)
+	got := admin.PermissionNames()
+	slices.Sort(got)
+	var want []string
+	for _, info := range generated.Permissions {
+		want = append(want, info.Name)
+	}
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Fatalf("admin holds %v, want %v", got, want)
+	}
+}
+
+func TestSamePermissions(t *testing.T) {
+	named := func(names ...string) []models.Permission {
+		var permissions []models.Permission
+		for _, name := range names {
+			permissions = append(permissions, models.Permission{Name: name})
+		}
+		return permissions
+	}
+
+	tests := []struct {
+		name string
+		a, b []models.Permission
+		want bool
+	}{
+		{"both empty", nil, nil, true},
+		{"same order", named("a:r", "b:r"), named("a:r", "b:r"), true},
+		{"other order", named("a:r", "b:r"), named("b:r", "a:r"), true},
+		{"same count, other names", named("a:r", "b:r"), named("a:r", "c:r"), false},
+		{"missing one", named("a:r", "b:r"), named("a:r"), false},
+		{"extra one", named("a:r"), named("a:r", "b:r"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := samePermissions(tt.a, tt.b); got != tt.want {
+				t.Fatalf("samePermissions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newRoleTestService(t *testing.T) (RoleService, *fakeRoleRepo) {
+	t.Helper()
+	permissions := &fakePermissionRepo{}
+	byName := make(map[string]models.Permission)
+	for _, name := range []string{"products:read", "products:write", "roles:write", "users:impersonate"} {
+		permission := models.Permission{Name: name}
+		permissions.permissions = append(permissions.permissions, permission)
+		byName[name] = permission
+	}
+	grant := func(names ...string) []models.Permission {
+		var granted []models.Permission
+		for _, name := range names {
+			granted = append(granted, byName[name])
+		}
+		return granted
+	}
+
+	roles := newFakeRoleRepo()
+	roles.roles["role-manager"] = &models.Role{Name: "role-manager", Permissions: grant("roles:write", "products:read")}
+	roles.roles["editor"] = &models.Role{Name: "editor", Permissions: grant("products:read", "products:write")}
+	roles.roles["viewer"] = &models.Role{Name: "viewer"}
+	return NewRoleService(roles, permissions, cache.NewMemoryCache()), roles
+}
+
+func TestCreateRoleOnlyGrantsHeldPermissions(t *testing.T) {
+	ctx := context.Background()
+	s, roles := newRoleTestService(t)
+	principal := &Principal{UserID: "manager", Role: "role-manager"}
+
+	if err := s.CreateRole(ctx, principal, &models.Role{Name: "reader"}, []string{"products:read"}); err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		principal   *Principal
+		permissions []string
+	}{
+		{"a permission the caller lacks", principal, []string{"products:read", "users:impersonate"}},
+		{"no principal", nil, []string{"products:read"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.CreateRole(ctx, tt.principal, &models.Role{Name: "escalated"}, tt.permissions); !errors.Is(err, ErrPermissionNotGrantable) {
+				t.Fatalf("err = %v, want ErrPermissionNotGrantable", err)
+			}
+			if _, ok := roles.roles["escalated"]; ok {
+				t.Fatal("a refused role was created")
+			}
+		})
+	}
+}
+
+func TestUpdateRoleOnlyChangesHeldPermissions(t *testing.T) {
+	description := "changed"
+	principal := &Principal{UserID: "manager", Role: "role-manager"}
+
+	tests := []struct {
+		name        string
+		principal   *Principal
+		role        string
+		description *string
+		permissions []string
+		want        error
+	}{
+		{"grant a held permission", principal, "viewer", nil, []string{"products:read"}, nil},
+		{"description only", principal, "editor", &description, nil, nil},
+		{"keep permissions the caller lacks", principal, "editor", nil, []string{"products:read", "products:write"}, nil},
+		{"grant a permission the caller lacks", principal, "viewer", nil, []string{"users:impersonate"}, ErrPermissionNotGrantable},
+		{"remove a permission the caller lacks", principal, "editor", nil, []string{"products:read"}, ErrPermissionNotGrantable},
+		{"own role", principal, "role-manager", nil, []string{"roles:write", "products:read", "users:impersonate"}, ErrCannotChangeOwnRole},
+		{"own role's description", principal, "role-manager", &description, nil, ErrCannotChangeOwnRole},
+		{"no principal", nil, "viewer", nil, []string{"products:read"}, ErrPermissionNotGrantable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, roles := newRoleTestService(t)
+			before := roles.roles[tt.role].PermissionNames()
+
+			_, err := s.UpdateRole(ctx, tt.principal, tt.role, tt.description, tt.permissions)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil {
+				return
+			}
+			stored := roles.roles[tt.role]
+			if !slices.Equal(stored.PermissionNames(), before) || stored.Description != "" {
+				t.Fatalf("a refused update changed the role to %+v", stored)
+			}
+		})
+	}
+}
+
+func TestAuthorizeNarrowsPersonalAccessTokens(t *testing.T) {
+	pat := func(role string, scopes ...string) *Principal {
+		return &Principal{UserID: "owner", Role: role, PersonalAccessTokenID: "pat", Scopes: scopes}
+	}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		required  []string
+		want      bool
+	}{
+		{"session ignores scopes", &Principal{Role: "editor", Scopes: []string{"products:read"}}, []string{"products:write"}, true},
+		{"within scope and role", pat("editor", "products:read"), []string{"products:read"}, true},
+		{"outside scope", pat("editor", "products:read"), []string{"products:write"}, false},
+		{"scope beyond the role", pat("editor", "products:read", "users:impersonate"), []string{"users:impersonate"}, false},
+		{"one of two outside scope", pat("editor", "products:read"), []string{"products:read", "products:write"}, false},
+		{"no scopes", pat("editor"), []string{"products:read"}, false},
+		{"no permissions required", pat("viewer"), nil, true},
+		{"role scope expands", pat("editor", "editor"), []string{"products:write"}, true},
+		{"role scope beyond the role", pat("editor", "role-manager"), []string{"roles:write"}, false},
+		{"role scope within the role", pat("editor", "role-manager"), []string{"products:read"}, true},
+		{"unknown role scope", pat("editor", "ghost"), []string{"products:read"}, false},
+		{"owner demoted", pat("viewer", "editor", "products:read"), []string{"products:read"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newRoleTestService(t)
+			got, err := s.Authorize(context.Background(), tt.principal, tt.required)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Authorize = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPersonalAccessTokensNeverExceedScopesOrRole(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newRoleTestService(t)
+	permissions := []string{"products:read", "products:write", "roles:write", "users:impersonate"}
+	scopes := append([]string{"role-manager", "editor", "viewer"}, permissions...)
+
+	// Every owner role against every set of scopes and every permission
+	for _, role := range []string{"role-manager", "editor", "viewer"} {
+		held, err := s.RolePermissions(ctx, role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for set := 0; set < 1<<len(scopes); set++ {
+			var granted, expanded []string
+			for i, scope := range scopes {
+				if set&(1<<i) == 0 {
+					continue
+				}
+				granted = append(granted, scope)
+				if slices.Contains(permissions, scope) {
+					expanded = append(expanded, scope)
+					continue
+				}
+				rolePermissions, err := s.RolePermissions(ctx, scope)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expanded = append(expanded, rolePermissions...)
+			}
+			principal := &Principal{Role: role, PersonalAccessTokenID: "pat", Scopes: granted}
+			for _, permission := range permissions {
+				got, err := s.Authorize(ctx, principal, []string{permission})
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := slices.Contains(held, permission) && slices.Contains(expanded, permission)
+				if got != want {
+					t.Fatalf("role %s, scopes %v, %s: Authorize = %v, want %v", role, granted, permission, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"backend/internal/models"
+	"backend/internal/repository"
+	"backend/pkg/scim"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userSchemaPrefix may qualify attribute names in filters and patches
+var userSchemaPrefix = strings.ToLower(scim.UserSchema) + ":"
+
+// SCIMService provisions users for an identity provider over SCIM 2.0.
+// Protocol errors are *scim.Error; missing users are gorm.ErrRecordNotFound.
+type SCIMService interface {
+	List(ctx context.Context, filter string, startIndex, count int) ([]models.User, int64, error)
+	Get(ctx context.Context, id uuid.UUID) (*models.User, error)
+	Create(ctx context.Context, resource *scim.User) (*models.User, error)
+	Replace(ctx context.Context, id uuid.UUID, resource *scim.User) (*models.User, error)
+	Patch(ctx context.Context, id uuid.UUID, req *scim.PatchRequest) (*models.User, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type scimService struct {
+	users    UserService
+	userRepo repository.UserRepository
+}
+
+func NewSCIMService(users UserService, userRepo repository.UserRepository) SCIMService {
+	return &scimService{
+		users:    users,
+		userRepo: userRepo,
+	}
+}
+
+func (s *scimService) List(ctx context.Context, filter string, startIndex, count int) ([]models.User, int64, error) {
+	var cond clause.Expression
+	if filter != "" {
+		expr, err := scim.ParseFilter(filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		sql, vars, err := filterSQL(expr, "")
+		if err != nil {
+			return nil, 0, err
+		}
+		cond = clause.Expr{SQL: sql, Vars: vars}
+	}
+
+	return s.userRepo.FindMatching(ctx, cond, startIndex-1, count)
+}
+
+func (s *scimService) Get(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return s.users.GetUser(ctx, id)
+}
+
+func (s *scimService) Create(ctx context.Context, resource *scim.User) (*models.User, error) {
+	now := time.Now()
+	user := &models.User{
+		Role:     "user",
+		IsActive: true,
+		// The directory vouches for the addresses it provisions
+		EmailVerifiedAt: &now,
+	}
+	if err := applyResource(user, resource); err != nil {
+		return nil, err
+	}
+
+	if err := s.users.ProvisionUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *scimService) Replace(ctx context.Context, id uuid.UUID, resource *scim.User) (*models.User, error) {
+	user, err := s.users.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousEmail := user.Email
+
+	if err := applyResource(user, resource); err != nil {
+		return nil, err
+	}
+
+	return user, s.update(ctx, user, previousEmail)
+}
+
+func (s *scimService) Patch(ctx context.Context, id uuid.UUID, req *scim.PatchRequest) (*models.User, error) {
+	if len(req.Operations) == 0 {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidSyntax, "no operations")
+	}
+
+	user, err := s.users.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousEmail := user.Email
+
+	for _, op := range req.Operations {
+		if err := applyPatch(user, op); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, s.update(ctx, user, previousEmail)
+}
+
+// update saves through UserService, which revokes the sessions of users
+// who were deactivated
+func (s *scimService) update(ctx context.Context, user *models.User, previousEmail string) error {
+	if !strings.EqualFold(user.Email, previousEmail) {
+		existing, err := s.userRepo.FindByEmail(ctx, user.Email)
+		if err == nil && existing.ID != user.ID {
+			return ErrEmailTaken
+		}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+	}
+
+	return s.users.UpdateUser(ctx, user.ID, user)
+}
+
+func (s *scimService) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.userRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return s.users.DeleteUser(ctx, id)
+}
+
+// applyResource sets the writable attributes of a full resource
+func applyResource(user *models.User, resource *scim.User) error {
+	if err := setUserName(user, resource.UserName); err != nil {
+		return err
+	}
+
+	switch {
+	case resource.DisplayName != "":
+		user.Name = resource.DisplayName
+	case resource.Name != nil && formatName(resource.Name) != "":
+		user.Name = formatName(resource.Name)
+	case user.Name == "":
+		user.Name = user.Email
+	}
+
+	user.ExternalID = resource.ExternalID
+	if resource.Active != nil {
+		user.IsActive = *resource.Active
+	}
+	return nil
+}
+
+// userName is the login email; emails only mirror it
+func setUserName(user *models.User, userName string) error {
+	address, err := mail.ParseAddress(userName)
+	if err != nil || address.Address != userName {
+		return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "userName must be an email address")
+	}
+	user.Email = userName
+	return nil
+}
+
+func formatName(name *scim.Name) string {
+	if name.Formatted != "" {
+		return name.Formatted
+	}
+	return strings.TrimSpace(name.GivenName + " " + name.FamilyName)
+}
+
+func applyPatch(user *models.User, op scim.PatchOperation) error {
+	kind := strings.ToLower(op.Op)
+	path := normalizeAttr(op.Path)
+
+	switch kind {
+	case "add", "replace":
+		if path == "" {
+			// Without a path the value holds attributes to set
+			var attrs map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &attrs); err != nil {
+				return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "value must be an object when path is omitted")
+			}
+			for attr, value := range attrs {
+				if err := setAttribute(user, normalizeAttr(attr), value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return setAttribute(user, path, op.Value)
+	case "remove":
+		switch {
+		case path == "externalid":
+			user.ExternalID = ""
+			return nil
+		case ignoredAttribute(path):
+			return nil
+		case path == "":
+			return scim.NewError(http.StatusBadRequest, scim.ErrNoTarget, "remove requires a path")
+		}
+		return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, fmt.Sprintf("%s cannot be removed", op.Path))
+	}
+	return scim.NewError(http.StatusBadRequest, scim.ErrInvalidSyntax, fmt.Sprintf("unknown operation %q", op.Op))
+}
+
+func setAttribute(user *models.User, attr string, value json.RawMessage) error {
+	switch attr {
+	case "active":
+		active, err := patchBool(value)
+		if err != nil {
+			return err
+		}
+		user.IsActive = active
+	case "username":
+		userName, err := patchString(attr, value)
+		if err != nil {
+			return err
+		}
+		return setUserName(user, userName)
+	case "displayname", "name.formatted":
+		name, err := patchString(attr, value)
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			user.Name = name
+		}
+	case "name":
+		var name scim.Name
+		if err := json.Unmarshal(value, &name); err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "name must be an object")
+		}
+		if formatted := formatName(&name); formatted != "" {
+			user.Name = formatted
+		}
+	case "externalid":
+		externalID, err := patchString(attr, value)
+		if err != nil {
+			return err
+		}
+		user.ExternalID = externalID
+	default:
+		if !ignoredAttribute(attr) {
+			return scim.NewError(http.StatusBadRequest, scim.ErrInvalidPath, fmt.Sprintf("unsupported attribute %q", attr))
+		}
+	}
+	return nil
+}
+
+// ignoredAttribute covers attributes clients commonly send that are not
+// stored separately: name parts (displayName holds the name) and emails
+// (userName is the email)
+func ignoredAttribute(attr string) bool {
+	return attr == "name.givenname" || attr == "name.familyname" ||
+		attr == "emails" || strings.HasPrefix(attr, "emails.") || strings.HasPrefix(attr, "emails[")
+}
+
+func normalizeAttr(attr string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(attr)), userSchemaPrefix)
+}
+
+func patchString(attr string, value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, fmt.Sprintf("%s must be a string", attr))
+	}
+	return s, nil
+}
+
+// patchBool also accepts "True" and "False", which some clients send
+func patchBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		switch strings.ToLower(s) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, scim.NewError(http.StatusBadRequest, scim.ErrInvalidValue, "active must be a boolean")
+}
+
+type scimColumnKind int
+
+const (
+	scimColumnString scimColumnKind = iota
+	// scimColumnExact strings are compared case sensitively
+	scimColumnExact
+	scimColumnBool
+	scimColumnTime
+	scimColumnID
+)
+
+type scimColumn struct {
+	name string
+	kind scimColumnKind
+}
+
+// scimColumns maps filterable attributes to users columns
+var scimColumns = map[string]scimColumn{
+	"id":                {"id", scimColumnID},
+	"username":          {"email", scimColumnString},
+	"emails":            {"email", scimColumnString},
+	"emails.value":      {"email", scimColumnString},
+	"externalid":        {"external_id", scimColumnExact},
+	"displayname":       {"name", scimColumnString},
+	"name.formatted":    {"name", scimColumnString},
+	"active":            {"is_active", scimColumnBool},
+	"meta.created":      {"created_at", scimColumnTime},
+	"meta.lastmodified": {"updated_at", scimColumnTime},
+}
+
+const (
+	sqlTrue  = "1 = 1"
+	sqlFalse = "1 = 0"
+)
+
+// filterSQL turns a filter into a parameterized WHERE condition. Column
+// names only ever come from scimColumns. prefix qualifies attributes
+// inside a value path such as emails[value eq "x"].
+func filterSQL(expr scim.Expr, prefix string) (string, []interface{}, error) {
+	switch e := expr.(type) {
+	case *scim.Logical:
+		left, leftVars, err := filterSQL(e.Left, prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightVars, err := filterSQL(e.Right, prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(%s %s %s)", left, strings.ToUpper(e.Op), right), append(leftVars, rightVars...), nil
+	case *scim.Not:
+		inner, vars, err := filterSQL(e.Expr, prefix)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(NOT %s)", inner), vars, nil
+	case *scim.ValuePath:
+		if prefix != "" || normalizeAttr(e.Attr) != "emails" {
+			return "", nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidFilter, fmt.Sprintf("unsupported value path %q", e.Attr))
+		}
+		return filterSQL(e.Filter, "emails.")
+	case *scim.Compare:
+		return compareSQL(e, prefix)
+	}
+	return "", nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidFilter, "unsupported filter")
+}
+
+func compareSQL(e *scim.Compare, prefix string) (string, []interface{}, error) {
+	attr := prefix + normalizeAttr(e.Attr)
+
+	// The single email is the primary, work address
+	switch attr {
+	case "emails.type":
+		return constantSQL(e.Op == "eq" && e.Value == "work" || e.Op == "ne" && e.Value != "work"), nil, nil
+	case "emails.primary":
+		return constantSQL(e.Op == "eq" && e.Value == true || e.Op == "ne" && e.Value != true), nil, nil
+	}
+
+	col, ok := scimColumns[attr]
+	if !ok {
+		return "", nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidFilter, fmt.Sprintf("unsupported attribute %q", e.Attr))
+	}
+	invalid := func() (string, []interface{}, error) {
+		return "", nil, scim.NewError(http.StatusBadRequest, scim.ErrInvalidFilter, fmt.Sprintf("invalid comparison for %q", e.Attr))
+	}
+
+	if e.Op == "pr" {
+		if col.kind == scimColumnString || col.kind == scimColumnExact {
+			return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", col.name, col.name), nil, nil
+		}
+		return fmt.Sprintf("(%s IS NOT NULL)", col.name), nil, nil
+	}
+	if e.Value == nil {
+		switch e.Op {
+		case "eq":
+			return fmt.Sprintf("(%s IS NULL)", col.name), nil, nil
+		case "ne":
+			return fmt.Sprintf("(%s IS NOT NULL)", col.name), nil, nil
+		}
+		return invalid()
+	}
+
+	switch col.kind {
+	case scimColumnString, scimColumnExact:
+		value, ok := e.Value.(string)
+		if !ok {
+			return invalid()
+		}
+		column, placeholder := col.name, "?"
+		if col.kind == scimColumnString {
+			column, placeholder = "LOWER("+col.name+")", "LOWER(?)"
+		}
+		switch e.Op {
+		case "co":
+			return fmt.Sprintf("(%s LIKE %s)", column, placeholder), []interface{}{"%" + escapeLike(value) + "%"}, nil
+		case "sw":
+			return fmt.Sprintf("(%s LIKE %s)", column, placeholder), []interface{}{escapeLike(value) + "%"}, nil
+		case "ew":
+			return fmt.Sprintf("(%s LIKE %s)", column, placeholder), []interface{}{"%" + escapeLike(value)}, nil
+		}
+		return fmt.Sprintf("(%s %s %s)", column, sqlOperator(e.Op), placeholder), []interface{}{value}, nil
+	case scimColumnBool:
+		value, ok := e.Value.(bool)
+		if !ok || (e.Op != "eq" && e.Op != "ne") {
+			return invalid()
+		}
+		return fmt.Sprintf("(%s %s ?)", col.name, sqlOperator(e.Op)), []interface{}{value}, nil
+	case scimColumnTime:
+		raw, ok := e.Value.(string)
+		if !ok {
+			return invalid()
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil || e.Op == "co" || e.Op == "sw" || e.Op == "ew" {
+			return invalid()
+		}
+		return fmt.Sprintf("(%s %s ?)", col.name, sqlOperator(e.Op)), []interface{}{value}, nil
+	case scimColumnID:
+		raw, ok := e.Value.(string)
+		if !ok || (e.Op != "eq" && e.Op != "ne") {
+			return invalid()
+		}
+		// Ids that are not UUIDs match no user
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return constantSQL(e.Op == "ne"), nil, nil
+		}
+		return fmt.Sprintf("(%s %s ?)", col.name, sqlOperator(e.Op)), []interface{}{id.String()}, nil
+	}
+	return invalid()
+}
+
+func sqlOperator(op string) string {
+	switch op {
+	case "ne":
+		return "<>"
+	case "gt":
+		return ">"
+	case "ge":
+		return ">="
+	case "lt":
+		return "<"
+	case "le":
+		return "<="
+	}
+	return "="
+}
+
+func constantSQL(match bool) string {
+	if match {
+		return sqlTrue
+	}
+	return sqlFalse
+}
+
+// escapeLike makes wildcards in user input literal; backslash is the
+// default LIKE escape character in PostgreSQL
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"backend/pkg/scim"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func parseFilterSQL(t *testing.T, filter string) (string, []interface{}, error) {
+	t.Helper()
+	expr, err := scim.ParseFilter(filter)
+	if err != nil {
+		t.Fatalf("ParseFilter(%q): %v", filter, err)
+	}
+	return filterSQL(expr, "")
+}
+
+func TestFilterSQL(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		filter   string
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		// Case-insensitive string attributes
+		{`userName eq "Jane@Example.com"`, `(LOWER(email) = LOWER(?))`, []interface{}{"Jane@Example.com"}},
+		{`userName ne "jane@example.com"`, `(LOWER(email) <> LOWER(?))`, []interface{}{"jane@example.com"}},
+		{`displayName co "ann"`, `(LOWER(name) LIKE LOWER(?))`, []interface{}{"%ann%"}},
+		{`displayName sw "Ann"`, `(LOWER(name) LIKE LOWER(?))`, []interface{}{"Ann%"}},
+		{`emails ew "@example.com"`, `(LOWER(email) LIKE LOWER(?))`, []interface{}{"%@example.com"}},
+		{`name.formatted gt "m"`, `(LOWER(name) > LOWER(?))`, []interface{}{"m"}},
+		{`userName pr`, `(email IS NOT NULL AND email <> '')`, nil},
+
+		// externalId is compared exactly
+		{`externalId eq "AbC"`, `(external_id = ?)`, []interface{}{"AbC"}},
+		{`externalId sw "AbC"`, `(external_id LIKE ?)`, []interface{}{"AbC%"}},
+		{`externalId eq null`, `(external_id IS NULL)`, nil},
+		{`externalId ne null`, `(external_id IS NOT NULL)`, nil},
+
+		// Booleans, times and ids
+		{`active eq true`, `(is_active = ?)`, []interface{}{true}},
+		{`active ne false`, `(is_active <> ?)`, []interface{}{false}},
+		{`active pr`, `(is_active IS NOT NULL)`, nil},
+		{`meta.created ge "2024-05-01T12:00:00Z"`, `(created_at >= ?)`, []interface{}{created}},
+		{`meta.lastModified lt "2024-05-01T12:00:00Z"`, `(updated_at < ?)`, []interface{}{created}},
+		{`meta.lastModified le "2024-05-01T12:00:00Z"`, `(updated_at <= ?)`, []interface{}{created}},
+		{`id eq "5F0C6A52-3F8E-4F55-9C35-0E9B1F3D6A11"`, `(id = ?)`, []interface{}{"5f0c6a52-3f8e-4f55-9c35-0e9b1f3d6a11"}},
+		{`id eq "not-a-uuid"`, sqlFalse, nil},
+		{`id ne "not-a-uuid"`, sqlTrue, nil},
+
+		// Schema URNs are accepted on attribute names
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "a"`, `(LOWER(email) = LOWER(?))`, []interface{}{"a"}},
+
+		// Grouping follows the parsed tree: and binds tighter than or
+		{
+			`userName eq "a" or userName eq "b" and active eq true`,
+			`((LOWER(email) = LOWER(?)) OR ((LOWER(email) = LOWER(?)) AND (is_active = ?)))`,
+			[]interface{}{"a", "b", true},
+		},
+		{
+			`(userName eq "a" or userName eq "b") and active eq true`,
+			`(((LOWER(email) = LOWER(?)) OR (LOWER(email) = LOWER(?))) AND (is_active = ?))`,
+			[]interface{}{"a", "b", true},
+		},
+		{
+			`not (active eq false) and externalId pr`,
+			`((NOT (is_active = ?)) AND (external_id IS NOT NULL AND external_id <> ''))`,
+			[]interface{}{false},
+		},
+
+		// The single email is the primary work address
+		{`emails[type eq "work" and value co "example"]`, `(1 = 1 AND (LOWER(email) LIKE LOWER(?)))`, []interface{}{"%example%"}},
+		{`emails[type eq "home"]`, sqlFalse, nil},
+		{`emails[type ne "home"]`, sqlTrue, nil},
+		{`emails[primary eq true]`, sqlTrue, nil},
+		{`emails[primary eq false]`, sqlFalse, nil},
+		{`emails[value pr]`, `(email IS NOT NULL AND email <> '')`, nil},
+
+		// LIKE wildcards and escapes in values are literal
+		{`userName co "100%_sure\\"`, `(LOWER(email) LIKE LOWER(?))`, []interface{}{`%100\%\_sure\\%`}},
+		{`userName sw "%"`, `(LOWER(email) LIKE LOWER(?))`, []interface{}{`\%%`}},
+		// Equality needs no escaping
+		{`userName eq "100%_sure"`, `(LOWER(email) = LOWER(?))`, []interface{}{"100%_sure"}},
+
+		// Quotes and SQL in values only ever reach the parameters
+		{`userName eq "x' OR '1'='1"`, `(LOWER(email) = LOWER(?))`, []interface{}{`x' OR '1'='1`}},
+		{`displayName eq "a\"); DROP TABLE users; --"`, `(LOWER(name) = LOWER(?))`, []interface{}{`a"); DROP TABLE users; --`}},
+		{`externalId eq "' OR 1=1"`, `(external_id = ?)`, []interface{}{`' OR 1=1`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			sql, vars, err := parseFilterSQL(t, tt.filter)
+			if err != nil {
+				t.Fatalf("filterSQL: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Fatalf("sql  %s\nwant %s", sql, tt.wantSQL)
+			}
+			if len(vars) != 0 || len(tt.wantVars) != 0 {
+				if !reflect.DeepEqual(vars, tt.wantVars) {
+					t.Fatalf("vars %#v, want %#v", vars, tt.wantVars)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSQLRejects(t *testing.T) {
+	for _, filter := range []string{
+		// Attributes outside the allowlist
+		`password eq "secret"`,
+		`role eq "admin"`,
+		`tokenVersion gt 0`,
+		`name.givenName eq "Jane"`,
+		`email_verified_at pr`,
+		`is_active eq true`,
+		`urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:employeeNumber eq "1"`,
+		`urn:ietf:params:scim:schemas:core:2.0:Group:displayName eq "a"`,
+		`emails[display eq "x"]`,
+		`emails[emails.value eq "x"]`,
+		`userName eq "a" or password pr`,
+		`not (role eq "admin")`,
+
+		// Value paths other than emails, or nested ones
+		`phoneNumbers[type eq "work"]`,
+		`emails[value eq "a" and emails[type eq "work"]]`,
+
+		// Values of the wrong type for the column
+		`userName eq 42`,
+		`userName eq true`,
+		`active eq "true"`,
+		`active gt true`,
+		`active co "t"`,
+		`meta.created gt "yesterday"`,
+		`meta.created gt 1714564800`,
+		`meta.created co "2024"`,
+		`id gt "5f0c6a52-3f8e-4f55-9c35-0e9b1f3d6a11"`,
+		`id eq 42`,
+		`userName gt null`,
+		`active co null`,
+	} {
+		t.Run(filter, func(t *testing.T) {
+			sql, vars, err := parseFilterSQL(t, filter)
+			if err == nil {
+				t.Fatalf("filterSQL accepted it as %s %v", sql, vars)
+			}
+			var scimErr *scim.Error
+			if !errors.As(err, &scimErr) || scimErr.ScimType != scim.ErrInvalidFilter {
+				t.Fatalf("err = %#v, want invalidFilter", err)
+			}
+		})
+	}
+}
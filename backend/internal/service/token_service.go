@@ -0,0 +1,368 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/models"
+	"backend/internal/repository"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// principalCacheTTL bounds how long a disabled or demoted user keeps
+// their old access after an admin change on another instance
+const principalCacheTTL = 30 * time.Second
+
+var (
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrTokenRevoked    = errors.New("token has been revoked")
+	ErrAccountInactive = errors.New("account is inactive")
+)
+
+// Principal is the authenticated caller. Role and Email come from the
+// current user record, not from the token.
+type Principal struct {
+	UserID        string
+	Email         string
+	Role          string
+	EmailVerified bool
+	MFAEnabled    bool
+	// Claims is set for JWT access tokens only
+	Claims *jwt.Claims
+	// PersonalAccessTokenID and Scopes are set for personal access tokens,
+	// which are limited to their scopes on top of the user's role
+	PersonalAccessTokenID string
+	Scopes                []string
+	// ActorID is the admin behind an impersonation token
+	ActorID string
+	// ExpiresAt is when the token stops working
+	ExpiresAt time.Time
+}
+
+// IsPersonalAccessToken reports whether the caller used an API key
+func (p *Principal) IsPersonalAccessToken() bool {
+	return p.PersonalAccessTokenID != ""
+}
+
+// IsImpersonated reports whether an admin is acting as the user
+func (p *Principal) IsImpersonated() bool {
+	return p.ActorID != ""
+}
+
+// patState is the cached slice of a personal access token
+type patState struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Revoked   bool      `json:"revoked"`
+}
+
+// principalState is the cached slice of a user needed to authorize requests
+type principalState struct {
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	IsActive      bool   `json:"is_active"`
+	EmailVerified bool   `json:"email_verified"`
+	MFAEnabled    bool   `json:"mfa_enabled"`
+	TokenVersion  int    `json:"token_version"`
+}
+
+// TokenService validates access tokens and personal access tokens against
+// signature or hash, expiry, the server-side revocation list and current
+// account state, and revokes them.
+type TokenService interface {
+	Authenticate(ctx context.Context, rawToken string) (*Principal, error)
+	RevokeToken(ctx context.Context, claims *jwt.Claims) error
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) error
+	InvalidatePrincipal(ctx context.Context, userID uuid.UUID) error
+}
+
+type tokenService struct {
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	patRepo          repository.PersonalAccessTokenRepository
+	permissions      PermissionResolver
+	store            cache.Store
+}
+
+func NewTokenService(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	patRepo repository.PersonalAccessTokenRepository,
+	permissions PermissionResolver,
+	store cache.Store,
+) TokenService {
+	return &tokenService{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		patRepo:          patRepo,
+		permissions:      permissions,
+		store:            store,
+	}
+}
+
+func revokedTokenKey(jti string) string {
+	return fmt.Sprintf("auth:revoked:jti:%s", jti)
+}
+
+func revokedUserKey(userID string) string {
+	return fmt.Sprintf("auth:revoked:user:%s", userID)
+}
+
+func principalKey(userID string) string {
+	return fmt.Sprintf("auth:principal:%s", userID)
+}
+
+func personalAccessTokenKey(hash string) string {
+	return fmt.Sprintf("auth:pat:%s", hash)
+}
+
+func (s *tokenService) Authenticate(ctx context.Context, rawToken string) (*Principal, error) {
+	if strings.HasPrefix(rawToken, models.PersonalAccessTokenPrefix) {
+		return s.authenticatePersonalAccessToken(ctx, rawToken)
+	}
+
+	claims, err := jwt.ParseToken(rawToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.ID == "" || claims.IssuedAt == nil || claims.ExpiresAt == nil {
+		return nil, ErrInvalidToken
+	}
+
+	var revoked bool
+	err = s.store.Get(ctx, revokedTokenKey(claims.ID), &revoked)
+	if err == nil {
+		return nil, ErrTokenRevoked
+	}
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, err
+	}
+
+	// Everything issued up to the cut-off was revoked with the user's sessions
+	var cutoff int64
+	err = s.store.Get(ctx, revokedUserKey(claims.UserID), &cutoff)
+	if err == nil && claims.IssuedAt.Unix() <= cutoff {
+		return nil, ErrTokenRevoked
+	}
+	if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, err
+	}
+
+	state, err := s.loadPrincipalState(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !state.IsActive {
+		return nil, ErrAccountInactive
+	}
+
+	if claims.TokenVersion != state.TokenVersion {
+		return nil, ErrTokenRevoked
+	}
+
+	principal := &Principal{
+		UserID:        claims.UserID,
+		Email:         state.Email,
+		Role:          state.Role,
+		EmailVerified: state.EmailVerified,
+		MFAEnabled:    state.MFAEnabled,
+		Claims:        claims,
+		ExpiresAt:     claims.ExpiresAt.Time,
+	}
+
+	if claims.Actor != nil {
+		// The token dies with the admin's right to impersonate
+		actor, err := s.loadPrincipalState(ctx, claims.Actor.Subject)
+		if err != nil {
+			if errors.Is(err, ErrAccountInactive) || errors.Is(err, ErrInvalidToken) {
+				return nil, ErrTokenRevoked
+			}
+			return nil, err
+		}
+		if !actor.IsActive {
+			return nil, ErrTokenRevoked
+		}
+		// The actor must still be allowed to impersonate
+		permissions, err := s.permissions.RolePermissions(ctx, actor.Role)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(permissions, PermissionImpersonate) {
+			return nil, ErrTokenRevoked
+		}
+		principal.ActorID = claims.Actor.Subject
+	}
+
+	return principal, nil
+}
+
+func (s *tokenService) authenticatePersonalAccessToken(ctx context.Context, rawToken string) (*Principal, error) {
+	pat, err := s.loadPersonalAccessToken(ctx, hashToken(rawToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if pat.Revoked {
+		return nil, ErrTokenRevoked
+	}
+	if time.Now().After(pat.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	state, err := s.loadPrincipalState(ctx, pat.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !state.IsActive {
+		return nil, ErrAccountInactive
+	}
+
+	return &Principal{
+		UserID:                pat.UserID,
+		Email:                 state.Email,
+		Role:                  state.Role,
+		EmailVerified:         state.EmailVerified,
+		MFAEnabled:            state.MFAEnabled,
+		PersonalAccessTokenID: pat.ID,
+		Scopes:                pat.Scopes,
+		ExpiresAt:             pat.ExpiresAt,
+	}, nil
+}
+
+// loadPersonalAccessToken caches lookups like loadPrincipalState; revoking
+// a token drops its entry. Last use is recorded on every cache miss, which
+// is accurate to principalCacheTTL.
+func (s *tokenService) loadPersonalAccessToken(ctx context.Context, hash string) (*patState, error) {
+	var state patState
+	err := s.store.Get(ctx, personalAccessTokenKey(hash), &state)
+	if err == nil {
+		return &state, nil
+	}
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, err
+	}
+
+	pat, err := s.patRepo.FindByHash(ctx, hash)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	state = patState{
+		ID:        pat.ID.String(),
+		UserID:    pat.UserID.String(),
+		Scopes:    pat.ScopeList(),
+		ExpiresAt: pat.ExpiresAt,
+		Revoked:   pat.RevokedAt != nil,
+	}
+
+	if !state.Revoked {
+		if err := s.patRepo.TouchLastUsed(ctx, pat.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.Set(ctx, personalAccessTokenKey(hash), state, principalCacheTTL); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// loadPrincipalState reads the user through a short-lived cache so every
+// request sees account changes within principalCacheTTL at worst
+func (s *tokenService) loadPrincipalState(ctx context.Context, userID string) (*principalState, error) {
+	var state principalState
+	err := s.store.Get(ctx, principalKey(userID), &state)
+	if err == nil {
+		return &state, nil
+	}
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// Deleted users lose access immediately
+			return nil, ErrAccountInactive
+		}
+		return nil, err
+	}
+
+	// Invited users have no session until they accept
+	state = principalState{
+		Email:         user.Email,
+		Role:          user.Role,
+		IsActive:      user.IsActive && !user.IsPending(),
+		EmailVerified: user.IsEmailVerified(),
+		MFAEnabled:    user.IsMFAEnabled(),
+		TokenVersion:  user.TokenVersion,
+	}
+
+	if err := s.store.Set(ctx, principalKey(userID), state, principalCacheTTL); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func (s *tokenService) InvalidatePrincipal(ctx context.Context, userID uuid.UUID) error {
+	return s.store.Delete(ctx, principalKey(userID.String()))
+}
+
+func (s *tokenService) RevokeToken(ctx context.Context, claims *jwt.Claims) error {
+	if claims.ExpiresAt == nil {
+		return ErrInvalidToken
+	}
+
+	// Entries only need to outlive the token itself
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.store.Set(ctx, revokedTokenKey(claims.ID), true, ttl)
+}
+
+func (s *tokenService) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	pats, err := s.patRepo.FindActiveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.patRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	for _, pat := range pats {
+		if err := s.store.Delete(ctx, personalAccessTokenKey(pat.TokenHash)); err != nil {
+			return err
+		}
+	}
+
+	// No access token outlives AccessTokenTTL (config keeps impersonation
+	// tokens within it), so neither must the cut-off
+	return s.store.Set(ctx, revokedUserKey(userID.String()), time.Now().Unix(), jwt.AccessTokenTTL())
+}
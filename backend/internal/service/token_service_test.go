@@ -0,0 +1,210 @@
+package service
+
+import (
+	"backend/internal/cache"
+	"backend/internal/models"
+	jwt "backend/pkg"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type tokenTest struct {
+	service *tokenService
+	users   *fakeUserRepo
+	refresh *fakeRefreshTokenRepo
+	pats    *fakePATRepo
+	store   cache.Store
+	user    *models.User
+}
+
+func newTokenTest(t *testing.T) *tokenTest {
+	t.Helper()
+	useTestKeys(t)
+	user := activeUser()
+	tt := &tokenTest{
+		users:   newFakeUserRepo(user),
+		refresh: newFakeRefreshTokenRepo(),
+		pats:    &fakePATRepo{},
+		store:   cache.NewMemoryCache(),
+		user:    user,
+	}
+	tt.service = &tokenService{
+		userRepo:         tt.users,
+		refreshTokenRepo: tt.refresh,
+		patRepo:          tt.pats,
+		permissions:      fakePermissions{},
+		store:            tt.store,
+	}
+	return tt
+}
+
+func (tt *tokenTest) token(t *testing.T, version int) (string, *jwt.Claims) {
+	t.Helper()
+	raw, err := jwt.GenerateToken(jwt.TokenParams{UserID: tt.user.ID.String(), Email: tt.user.Email, Role: tt.user.Role, Version: version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := jwt.ParseToken(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw, claims
+}
+
+func TestAuthenticateAccessToken(t *testing.T) {
+	ctx := context.Background()
+	tt := newTokenTest(t)
+	raw, claims := tt.token(t, 0)
+
+	principal, err := tt.service.Authenticate(ctx, raw)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if principal.UserID != tt.user.ID.String() || principal.Role != models.RoleUser || principal.Claims.ID != claims.ID {
+		t.Fatalf("principal %+v", principal)
+	}
+
+	for _, bad := range []string{"", "not-a-jwt", raw + "x"} {
+		if _, err := tt.service.Authenticate(ctx, bad); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("Authenticate(%q): err = %v, want ErrInvalidToken", bad, err)
+		}
+	}
+}
+
+func TestRevokedTokenIsDenied(t *testing.T) {
+	ctx := context.Background()
+	tt := newTokenTest(t)
+	raw, claims := tt.token(t, 0)
+	other, _ := tt.token(t, 0)
+
+	if err := tt.service.RevokeToken(ctx, claims); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tt.service.Authenticate(ctx, raw); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("revoked token: err = %v, want ErrTokenRevoked", err)
+	}
+	// Only that jti is denied
+	if _, err := tt.service.Authenticate(ctx, other); err != nil {
+		t.Fatalf("another token of the user: %v", err)
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	ctx := context.Background()
+	tt := newTokenTest(t)
+	raw, _ := tt.token(t, 0)
+
+	familyID := uuid.New()
+	if err := tt.refresh.Create(ctx, &models.RefreshToken{UserID: tt.user.ID, FamilyID: familyID, TokenHash: "refresh"}); err != nil {
+		t.Fatal(err)
+	}
+	pat := models.PersonalAccessTokenPrefix + "secret"
+	if err := tt.pats.Create(ctx, &models.PersonalAccessToken{UserID: tt.user.ID, TokenHash: hashToken(pat), ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	// Cache the token's state, which revocation has to drop
+	if _, err := tt.service.Authenticate(ctx, pat); err != nil {
+		t.Fatalf("personal access token: %v", err)
+	}
+
+	if err := tt.service.RevokeUserSessions(ctx, tt.user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tt.service.Authenticate(ctx, raw); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("access token issued before the cut-off: err = %v, want ErrTokenRevoked", err)
+	}
+	if _, err := tt.service.Authenticate(ctx, pat); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("personal access token: err = %v, want ErrTokenRevoked", err)
+	}
+	if tt.refresh.live(familyID) != 0 {
+		t.Fatal("refresh token survived")
+	}
+}
+
+func TestTokensIssuedAfterCutoffAreAccepted(t *testing.T) {
+	ctx := context.Background()
+	tt := newTokenTest(t)
+
+	// Cut-offs are in whole seconds, so set one safely in the past
+	if err := tt.store.Set(ctx, revokedUserKey(tt.user.ID.String()), time.Now().Add(-time.Minute).Unix(), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := tt.token(t, 0)
+	if _, err := tt.service.Authenticate(ctx, raw); err != nil {
+		t.Fatalf("token issued after the cut-off: %v", err)
+	}
+
+	// A token issued in the cut-off's second is still revoked
+	raw, claims := tt.token(t, 0)
+	if err := tt.store.Set(ctx, revokedUserKey(tt.user.ID.String()), claims.IssuedAt.Unix(), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tt.service.Authenticate(ctx, raw); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("token issued at the cut-off: err = %v, want ErrTokenRevoked", err)
+	}
+}
+
+func TestTokenVersionAndPrincipalCache(t *testing.T) {
+	ctx := context.Background()
+	tt := newTokenTest(t)
+	raw, _ := tt.token(t, 0)
+
+	if _, err := tt.service.Authenticate(ctx, raw); err != nil {
+		t.Fatal(err)
+	}
+
+	// Demote the user behind the cache's back
+	demoted := *tt.user
+	demoted.Role = models.RoleGuest
+	demoted.TokenVersion = 1
+	if err := tt.users.Update(ctx, &demoted); err != nil {
+		t.Fatal(err)
+	}
+	principal, err := tt.service.Authenticate(ctx, raw)
+	if err != nil || principal.Role != models.RoleUser {
+		t.Fatalf("cached state: %+v, %v", principal, err)
+	}
+
+	// Invalidating the principal makes the change take effect at once
+	if err := tt.service.InvalidatePrincipal(ctx, tt.user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tt.service.Authenticate(ctx, raw); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("old version: err = %v, want ErrTokenRevoked", err)
+	}
+
+	current, _ := tt.token(t, 1)
+	principal, err = tt.service.Authenticate(ctx, current)
+	if err != nil || principal.Role != models.RoleGuest {
+		t.Fatalf("current version: %+v, %v", principal, err)
+	}
+}
+
+func TestTokensOfInactiveUsersAreDenied(t *testing.T) {
+	ctx := context.Background()
+	tt := newTokenTest(t)
+	raw, _ := tt.token(t, 0)
+
+	disabled := *tt.user
+	disabled.IsActive = false
+	if err := tt.users.Update(ctx, &disabled); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tt.service.Authenticate(ctx, raw); !errors.Is(err, ErrAccountInactive) {
+		t.Fatalf("disabled user: err = %v, want ErrAccountInactive", err)
+	}
+
+	// Deleted users too, once the cached state is dropped
+	delete(tt.users.users, tt.user.ID)
+	if err := tt.service.InvalidatePrincipal(ctx, tt.user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tt.service.Authenticate(ctx, raw); !errors.Is(err, ErrAccountInactive) {
+		t.Fatalf("deleted user: err = %v, want ErrAccountInactive", err)
+	}
+}
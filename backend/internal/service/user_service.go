@@ -6,54 +6,56 @@ import (
 	"backend/internal/models"
 	"backend/internal/repository"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrRoleNotAssignable   = errors.New("you can only assign roles whose permissions you hold")
+	ErrCannotChangeOwnRole = errors.New("you cannot change your own role")
+)
+
 type UserService interface {
-	CreateUser(ctx context.Context, user *models.User) error
 	GetUser(ctx context.Context, id generated.IdParam) (*models.User, error)
 	ListUsers(ctx context.Context, page, perPage int) ([]models.User, int64, error)
+	// ProvisionUser creates an active account without a password for a
+	// directory that manages it; the user signs in through single sign-on,
+	// a login link or a password reset
+	ProvisionUser(ctx context.Context, user *models.User) error
 	UpdateUser(ctx context.Context, id generated.IdParam, user *models.User) error
+	// CheckRoleAssignment refuses to let the principal give user, or a new
+	// account when user is nil, a role with permissions the principal does
+	// not hold, or change their own role
+	CheckRoleAssignment(ctx context.Context, principal *Principal, user *models.User, role string) error
 	DeleteUser(ctx context.Context, id generated.IdParam) error
+	RevokeSessions(ctx context.Context, id generated.IdParam) error
+	ClearLockout(ctx context.Context, id generated.IdParam) error
 }
 
 type userService struct {
-	repo  repository.UserRepository
-	cache *cache.RedisCache
+	repo        repository.UserRepository
+	roleRepo    repository.RoleRepository
+	permissions PermissionResolver
+	cache       *cache.RedisCache
+	tokens      TokenService
+	throttle    LoginThrottle
 }
 
-func NewUserService(repo repository.UserRepository, cache *cache.RedisCache) UserService {
+func NewUserService(repo repository.UserRepository, roleRepo repository.RoleRepository, permissions PermissionResolver, cache *cache.RedisCache, tokens TokenService, throttle LoginThrottle) UserService {
 	return &userService{
-		repo:  repo,
-		cache: cache,
+		repo:        repo,
+		roleRepo:    roleRepo,
+		permissions: permissions,
+		cache:       cache,
+		tokens:      tokens,
+		throttle:    throttle,
 	}
 }
 
-func (s *userService) CreateUser(ctx context.Context, user *models.User) error {
-	// Check if email already exists
-	existing, err := s.repo.FindByEmail(ctx, user.Email)
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return err
-	}
-	if existing != nil {
-		return fmt.Errorf("email already exists")
-	}
-
-	if err := s.repo.Create(ctx, user); err != nil {
-		return err
-	}
-
-	// Invalidate users list cache
-	if s.cache != nil {
-		s.cache.DeletePattern(ctx, "users:list:*")
-	}
-
-	return nil
-}
-
 func (s *userService) GetUser(ctx context.Context, id generated.IdParam) (*models.User, error) {
 	cacheKey := fmt.Sprintf("user:%d", id)
 
@@ -111,6 +113,29 @@ func (s *userService) ListUsers(ctx context.Context, page, perPage int) ([]model
 	return users, total, nil
 }
 
+func (s *userService) ProvisionUser(ctx context.Context, user *models.User) error {
+	_, err := s.repo.FindByEmail(ctx, user.Email)
+	if err == nil {
+		return ErrEmailTaken
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	user.Password = ""
+	user.Status = models.UserStatusActive
+	if err := s.repo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	if s.cache != nil {
+		s.cache.DeletePattern(ctx, "users:list:*")
+	}
+
+	return nil
+}
+
 func (s *userService) UpdateUser(ctx context.Context, id generated.IdParam, user *models.User) error {
 	existing, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -119,6 +144,33 @@ func (s *userService) UpdateUser(ctx context.Context, id generated.IdParam, user
 
 	user.ID = existing.ID
 	user.CreatedAt = existing.CreatedAt
+	// The passed user may come from the JSON cache, which never carries the
+	// password hash or TOTP secret, so always keep the stored credentials
+	user.Password = existing.Password
+	user.TOTPSecret = existing.TOTPSecret
+	user.TOTPEnabledAt = existing.TOTPEnabledAt
+	user.TOTPLastStep = existing.TOTPLastStep
+	user.TokenVersion = existing.TokenVersion
+	user.PendingEmail = existing.PendingEmail
+	user.Status = existing.Status
+
+	// A new address has to be verified again
+	if strings.EqualFold(user.Email, existing.Email) {
+		user.EmailVerifiedAt = existing.EmailVerifiedAt
+	} else {
+		user.EmailVerifiedAt = nil
+	}
+
+	if user.Role != existing.Role {
+		if err := checkRole(ctx, s.roleRepo, user.Role); err != nil {
+			return err
+		}
+	}
+
+	// Demoted or disabled users must not keep using tokens issued before
+	if user.Role != existing.Role || (existing.IsActive && !user.IsActive) {
+		user.TokenVersion++
+	}
 
 	if err := s.repo.Update(ctx, user); err != nil {
 		return err
@@ -130,6 +182,42 @@ func (s *userService) UpdateUser(ctx context.Context, id generated.IdParam, user
 		s.cache.DeletePattern(ctx, "users:list:*")
 	}
 
+	return s.tokens.InvalidatePrincipal(ctx, user.ID)
+}
+
+func (s *userService) CheckRoleAssignment(ctx context.Context, principal *Principal, user *models.User, role string) error {
+	if principal == nil {
+		return ErrRoleNotAssignable
+	}
+
+	roles := []string{role}
+	if user != nil {
+		if user.Role == role {
+			return nil
+		}
+		if user.ID.String() == principal.UserID {
+			return ErrCannotChangeOwnRole
+		}
+		// Demoting someone who holds more than the caller is refused too
+		roles = append(roles, user.Role)
+	}
+	if err := checkRole(ctx, s.roleRepo, role); err != nil {
+		return err
+	}
+
+	for _, name := range roles {
+		required, err := s.permissions.RolePermissions(ctx, name)
+		if err != nil {
+			return err
+		}
+		allowed, err := s.permissions.Authorize(ctx, principal, required)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrRoleNotAssignable
+		}
+	}
 	return nil
 }
 
@@ -144,5 +232,23 @@ func (s *userService) DeleteUser(ctx context.Context, id generated.IdParam) erro
 		s.cache.DeletePattern(ctx, "users:list:*")
 	}
 
-	return nil
+	return s.tokens.InvalidatePrincipal(ctx, id)
+}
+
+func (s *userService) RevokeSessions(ctx context.Context, id generated.IdParam) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.tokens.RevokeUserSessions(ctx, user.ID)
+}
+
+func (s *userService) ClearLockout(ctx context.Context, id generated.IdParam) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.throttle.Reset(ctx, user.Email)
 }
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckRoleAssignment(t *testing.T) {
+	permissions := fakePermissions{
+		models.RoleAdmin: {"users:read", "users:write", "roles:write", "products:read"},
+		"user-manager":   {"users:read", "users:write", "products:read"},
+		"support":        {"users:read"},
+		models.RoleUser:  {"products:read"},
+	}
+	s := &userService{
+		roleRepo:    newFakeRoleRepo(models.RoleAdmin, "user-manager", "support", models.RoleUser),
+		permissions: permissions,
+	}
+
+	caller := activeUser()
+	caller.Role = "user-manager"
+	principal := &Principal{UserID: caller.ID.String(), Role: caller.Role}
+
+	withRole := func(role string) *models.User {
+		user := activeUser()
+		user.Role = role
+		return user
+	}
+
+	tests := []struct {
+		name string
+		user *models.User
+		role string
+		want error
+	}{
+		{"new account with a role the caller covers", nil, "support", nil},
+		{"new account with the caller's own role", nil, "user-manager", nil},
+		{"new account as admin", nil, models.RoleAdmin, ErrRoleNotAssignable},
+		{"new account with an unknown role", nil, "root", ErrUnknownRole},
+		{"promote within the caller's permissions", withRole(models.RoleUser), "support", nil},
+		{"promote to admin", withRole(models.RoleUser), models.RoleAdmin, ErrRoleNotAssignable},
+		{"demote an admin", withRole(models.RoleAdmin), models.RoleUser, ErrRoleNotAssignable},
+		{"unchanged role needs no permission", withRole(models.RoleAdmin), models.RoleAdmin, nil},
+		{"own role", caller, models.RoleAdmin, ErrCannotChangeOwnRole},
+		{"own role, even downwards", caller, models.RoleUser, ErrCannotChangeOwnRole},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.CheckRoleAssignment(context.Background(), principal, tt.user, tt.role); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := s.CheckRoleAssignment(context.Background(), nil, nil, models.RoleUser); !errors.Is(err, ErrRoleNotAssignable) {
+		t.Fatalf("no principal: err = %v, want ErrRoleNotAssignable", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Package session carries tokens in cookies for browser clients, so the
+// frontend never has to keep them in storage scripts can read. Clients
+// opt in per request with "X-Auth-Transport: cookie". Cookie-authenticated
+// requests that change state must echo the CSRF cookie in X-CSRF-Token
+// (double-submit).
+package session
+
+import (
+	"backend/internal/generated"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	// CSRFCookie is readable by scripts; its value goes in CSRFHeader
+	CSRFCookie      = "csrf_token"
+	CSRFHeader      = "X-CSRF-Token"
+	TransportHeader = "X-Auth-Transport"
+	TransportCookie = "cookie"
+
+	// The refresh token is only sent to the endpoints that consume it
+	refreshCookiePath = generated.BasePath + "/auth"
+	csrfTokenSize     = 32
+)
+
+type Options struct {
+	Enabled bool
+	// Domain is empty for host-only cookies
+	Domain   string
+	SameSite http.SameSite
+	// RefreshTTL is how long the refresh and CSRF cookies live
+	RefreshTTL time.Duration
+}
+
+// Requested reports whether the client asked for cookies instead of tokens
+// in the response body
+func (o Options) Requested(c *gin.Context) bool {
+	return o.Enabled && c.GetHeader(TransportHeader) == TransportCookie
+}
+
+// SetTokens stores the tokens in HttpOnly cookies and issues a new CSRF
+// token alongside them
+func (o Options) SetTokens(c *gin.Context, accessToken string, accessTTL time.Duration, refreshToken string) error {
+	csrf := make([]byte, csrfTokenSize)
+	if _, err := rand.Read(csrf); err != nil {
+		return err
+	}
+
+	o.set(c, AccessTokenCookie, accessToken, "/", accessTTL, true)
+	o.set(c, RefreshTokenCookie, refreshToken, refreshCookiePath, o.RefreshTTL, true)
+	o.set(c, CSRFCookie, base64.RawURLEncoding.EncodeToString(csrf), "/", o.RefreshTTL, false)
+	return nil
+}
+
+// Clear expires all session cookies
+func (o Options) Clear(c *gin.Context) {
+	o.set(c, AccessTokenCookie, "", "/", -1, true)
+	o.set(c, RefreshTokenCookie, "", refreshCookiePath, -1, true)
+	o.set(c, CSRFCookie, "", "/", -1, false)
+}
+
+func (o Options) set(c *gin.Context, name, value, path string, ttl time.Duration, httpOnly bool) {
+	maxAge := int(ttl.Seconds())
+	if ttl < 0 {
+		maxAge = -1
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   o.Domain,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: httpOnly,
+		SameSite: o.SameSite,
+	})
+}
+
+// Cookie returns a session cookie's value, or "" when absent
+func Cookie(c *gin.Context, name string) string {
+	value, err := c.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// HasCookies reports whether the request carries any session cookie
+func HasCookies(c *gin.Context) bool {
+	return Cookie(c, AccessTokenCookie) != "" || Cookie(c, RefreshTokenCookie) != ""
+}
+
+// SafeMethod is true for methods that must not change state and so need
+// no CSRF token
+func SafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// ValidCSRF checks the double-submitted CSRF token
+func ValidCSRF(c *gin.Context) bool {
+	cookie := Cookie(c, CSRFCookie)
+	header := c.GetHeader(CSRFHeader)
+	if cookie == "" || header == "" {
+		return false
+	}
+	// Compare digests so the comparison time does not depend on length
+	got, want := sha256.Sum256([]byte(header)), sha256.Sum256([]byte(cookie))
+	return subtle.ConstantTimeCompare(got[:], want[:]) == 1
+}
+
+// ParseSameSite maps "lax", "strict" and "none" to cookie attributes
+func ParseSameSite(value string) (http.SameSite, bool) {
+	switch value {
+	case "lax":
+		return http.SameSiteLaxMode, true
+	case "strict":
+		return http.SameSiteStrictMode, true
+	case "none":
+		return http.SameSiteNoneMode, true
+	}
+	return http.SameSiteDefaultMode, false
+}
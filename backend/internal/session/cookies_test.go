@@ -0,0 +1,128 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newContext(method string, cookies map[string]string, headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/", nil)
+	for name, value := range cookies {
+		c.Request.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	for name, value := range headers {
+		c.Request.Header.Set(name, value)
+	}
+	return c, w
+}
+
+func TestSafeMethod(t *testing.T) {
+	for method, want := range map[string]bool{
+		http.MethodGet:     true,
+		http.MethodHead:    true,
+		http.MethodOptions: true,
+		http.MethodPost:    false,
+		http.MethodPut:     false,
+		http.MethodPatch:   false,
+		http.MethodDelete:  false,
+		http.MethodTrace:   false,
+	} {
+		if got := SafeMethod(method); got != want {
+			t.Errorf("SafeMethod(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestValidCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		want   bool
+	}{
+		{"matching", "abc123", "abc123", true},
+		{"no header", "abc123", "", false},
+		{"no cookie", "", "abc123", false},
+		{"neither", "", "", false},
+		{"wrong header", "abc123", "abc124", false},
+		{"prefix of the cookie", "abc123", "abc", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookies := map[string]string{}
+			if tt.cookie != "" {
+				cookies[CSRFCookie] = tt.cookie
+			}
+			headers := map[string]string{}
+			if tt.header != "" {
+				headers[CSRFHeader] = tt.header
+			}
+			c, _ := newContext(http.MethodPost, cookies, headers)
+			if got := ValidCSRF(c); got != tt.want {
+				t.Fatalf("ValidCSRF = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTokensAndClear(t *testing.T) {
+	opts := Options{Enabled: true, SameSite: http.SameSiteStrictMode, RefreshTTL: time.Hour}
+
+	c, w := newContext(http.MethodPost, nil, nil)
+	if err := opts.SetTokens(c, "access", 15*time.Minute, "refresh"); err != nil {
+		t.Fatal(err)
+	}
+	set := make(map[string]*http.Cookie)
+	for _, cookie := range w.Result().Cookies() {
+		set[cookie.Name] = cookie
+	}
+	for name, want := range map[string]struct {
+		httpOnly bool
+		path     string
+		maxAge   int
+	}{
+		AccessTokenCookie:  {true, "/", 900},
+		RefreshTokenCookie: {true, refreshCookiePath, 3600},
+		CSRFCookie:         {false, "/", 3600},
+	} {
+		cookie, ok := set[name]
+		if !ok {
+			t.Fatalf("%s not set", name)
+		}
+		if cookie.HttpOnly != want.httpOnly || cookie.Path != want.path || cookie.MaxAge != want.maxAge || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+			t.Fatalf("%s = %+v", name, cookie)
+		}
+	}
+	if set[CSRFCookie].Value == "" {
+		t.Fatal("empty CSRF token")
+	}
+
+	c, w = newContext(http.MethodPost, nil, nil)
+	opts.Clear(c)
+	cleared := w.Result().Cookies()
+	if len(cleared) != 3 {
+		t.Fatalf("cleared %d cookies, want 3", len(cleared))
+	}
+	for _, cookie := range cleared {
+		if cookie.Value != "" || cookie.MaxAge >= 0 {
+			t.Fatalf("%s not expired: %+v", cookie.Name, cookie)
+		}
+	}
+}
+
+func TestRequested(t *testing.T) {
+	c, _ := newContext(http.MethodPost, nil, map[string]string{TransportHeader: TransportCookie})
+	if !(Options{Enabled: true}).Requested(c) {
+		t.Fatal("cookie transport not requested")
+	}
+	if (Options{}).Requested(c) {
+		t.Fatal("cookies requested while disabled")
+	}
+}
@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// actionTokenType marks tokens that authorize a single action (e.g. an
+// email link) so they can never be used as access tokens and vice versa
+const actionTokenType = "action+jwt"
+
+var ErrWrongPurpose = errors.New("token issued for a different purpose")
+
+// ActionClaims are carried by links sent to users. Email pins the token
+// to the address it was sent to.
+type ActionClaims struct {
+	Purpose string `json:"purpose"`
+	Email   string `json:"email,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ActionParams describes a new action token
+type ActionParams struct {
+	Purpose string
+	Subject string
+	Email   string
+	TTL     time.Duration
+}
+
+func GenerateActionToken(params ActionParams) (string, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := ActionClaims{
+		Purpose: params.Purpose,
+		Email:   params.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   params.Subject,
+			ExpiresAt: jwt.NewNumericDate(now.Add(params.TTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+	token.Header["typ"] = actionTokenType
+	return token.SignedString(ks.active.signKey)
+}
+
+// ParseActionToken verifies an action token and checks it was issued for
+// the expected purpose
+func ParseActionToken(tokenStr, purpose string) (*ActionClaims, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.ParseWithClaims(
+		tokenStr,
+		&ActionClaims{},
+		ks.keyFunc(actionTokenType),
+		validMethods,
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	if claims.Purpose != purpose {
+		return nil, ErrWrongPurpose
+	}
+	return claims, nil
+}
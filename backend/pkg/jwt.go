@@ -5,39 +5,103 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-var jwtSecret = []byte("your-secret-key-change-this")
+// accessTokenType is the typ header of access tokens
+const accessTokenType = "JWT"
+
+var (
+	ErrNoKeySet     = errors.New("jwt key set is not configured")
+	ErrMissingKeyID = errors.New("token has no key id")
+	ErrUnknownKeyID = errors.New("token signed with unknown key")
+	ErrWrongType    = errors.New("unexpected token type")
+)
+
+var validMethods = jwt.WithValidMethods([]string{
+	jwt.SigningMethodHS256.Alg(),
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+})
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"` // admin | user
+	UserID       string `json:"user_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"` // role name, resolved to permissions per request
+	TokenVersion int    `json:"ver"`
+	// Actor is set when someone else acts as the user
+	Actor *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID, email, role string) (string, error) {
+// Actor is the RFC 8693 act claim
+type Actor struct {
+	Subject string `json:"sub"`
+}
+
+// TokenParams describes the subject of a new access token
+type TokenParams struct {
+	UserID string
+	Email  string
+	Role   string
+	// Version must match the user's current token version for the token
+	// to be accepted; bumping it invalidates every older token
+	Version int
+	// ActorID makes an impersonation token acting as UserID
+	ActorID string
+	// ID is the jti; a random one is used when empty
+	ID string
+	// TTL overrides AccessTokenTTL when set
+	TTL time.Duration
+}
+
+func GenerateToken(params TokenParams) (string, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return "", err
+	}
+
+	ttl := params.TTL
+	if ttl <= 0 {
+		ttl = AccessTokenTTL()
+	}
+	id := params.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:       params.UserID,
+		Email:        params.Email,
+		Role:         params.Role,
+		TokenVersion: params.Version,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ID:        id,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
+	if params.ActorID != "" {
+		claims.Actor = &Actor{Subject: params.ActorID}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+	token.Header["typ"] = accessTokenType
+	return token.SignedString(ks.active.signKey)
 }
 
 func ParseToken(tokenStr string) (*Claims, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return nil, err
+	}
+
 	token, err := jwt.ParseWithClaims(
 		tokenStr,
 		&Claims{},
-		func(t *jwt.Token) (interface{}, error) {
-			return jwtSecret, nil
-		},
+		ks.keyFunc(accessTokenType),
+		validMethods,
 	)
 	if err != nil {
 		return nil, err
@@ -49,3 +113,27 @@ func ParseToken(tokenStr string) (*Claims, error) {
 	}
 	return claims, nil
 }
+
+// keyFunc resolves the verification key from the kid header and rejects
+// tokens of another type
+func (ks *KeySet) keyFunc(typ string) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		if got, _ := t.Header["typ"].(string); got != typ {
+			return nil, ErrWrongType
+		}
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrMissingKeyID
+		}
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, ErrUnknownKeyID
+		}
+		// The kid pins the algorithm, so a token cannot switch e.g.
+		// from RS256 to HS256 and use the public key as a secret
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return key.verifyKey, nil
+	}
+}
@@ -0,0 +1,248 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// minSecretLength is the shortest HMAC secret accepted for HS256 (256 bits)
+	minSecretLength = 32
+	minRSABits      = 2048
+)
+
+// Key is a signing or verification key identified by the kid header.
+// Keys built from a public key only can verify but never sign.
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewHMACKey builds an HS256 key from a shared secret
+func NewHMACKey(id string, secret []byte) (Key, error) {
+	if len(secret) < minSecretLength {
+		return Key{}, fmt.Errorf("jwt key %q must be at least %d bytes", id, minSecretLength)
+	}
+	return Key{
+		ID:        id,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}, nil
+}
+
+// ParseKey builds a key from config material. PEM encoded RSA keys become
+// RS256 and Ed25519 keys become EdDSA; anything else is an HMAC secret.
+func ParseKey(id string, material []byte) (Key, error) {
+	block, _ := pem.Decode(material)
+	if block == nil {
+		return NewHMACKey(id, material)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwt key %q: %w", id, err)
+		}
+		return newAsymmetricKey(id, parsed)
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwt key %q: %w", id, err)
+		}
+		return newAsymmetricKey(id, parsed)
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwt key %q: %w", id, err)
+		}
+		return newAsymmetricKey(id, parsed)
+	case "RSA PUBLIC KEY":
+		parsed, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwt key %q: %w", id, err)
+		}
+		return newAsymmetricKey(id, parsed)
+	default:
+		return Key{}, fmt.Errorf("jwt key %q: unsupported PEM block %q", id, block.Type)
+	}
+}
+
+func newAsymmetricKey(id string, parsed interface{}) (Key, error) {
+	key := Key{ID: id}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.signKey, key.verifyKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.verifyKey = jwt.SigningMethodEdDSA, k
+	default:
+		return Key{}, fmt.Errorf("jwt key %q: unsupported key type %T", id, parsed)
+	}
+
+	if pub, ok := key.verifyKey.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+		return Key{}, fmt.Errorf("jwt key %q: RSA keys must be at least %d bits", id, minRSABits)
+	}
+
+	return key, nil
+}
+
+// CanSign reports whether the key holds private material
+func (k Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// KeySet holds the active signing key plus retiring keys that are only
+// used to verify tokens issued before the last rotation.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+func NewKeySet(activeID string, keys []Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+
+	for i := range keys {
+		key := keys[i]
+		if key.ID == "" {
+			return nil, errors.New("jwt key id is required")
+		}
+		if key.Method == nil || key.verifyKey == nil {
+			return nil, fmt.Errorf("jwt key %q is not initialized", key.ID)
+		}
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate jwt key id %q", key.ID)
+		}
+		ks.keys[key.ID] = &key
+	}
+
+	active, ok := ks.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q is not configured", activeID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active jwt key %q has no private key", activeID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// ActiveKeyID returns the kid used for newly issued tokens
+func (ks *KeySet) ActiveKeyID() string {
+	return ks.active.ID
+}
+
+// JWK is the public part of a key as published in a JWKS document (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of every asymmetric key in the set, active
+// and retiring. HMAC secrets are never published.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+
+	for _, key := range ks.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	// Active key first, then by kid, so the document is stable
+	sort.Slice(set.Keys, func(i, j int) bool {
+		if (set.Keys[i].Kid == ks.active.ID) != (set.Keys[j].Kid == ks.active.ID) {
+			return set.Keys[i].Kid == ks.active.ID
+		}
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+var (
+	keySetMu       sync.RWMutex
+	keySet         *KeySet
+	accessTokenTTL = 15 * time.Minute
+)
+
+// SetKeySet installs the key set used by GenerateToken and ParseToken
+func SetKeySet(ks *KeySet) {
+	keySetMu.Lock()
+	defer keySetMu.Unlock()
+	keySet = ks
+}
+
+// SetAccessTokenTTL sets the lifetime of tokens issued by GenerateToken
+func SetAccessTokenTTL(ttl time.Duration) {
+	keySetMu.Lock()
+	defer keySetMu.Unlock()
+	accessTokenTTL = ttl
+}
+
+// AccessTokenTTL returns the lifetime of tokens issued by GenerateToken
+func AccessTokenTTL() time.Duration {
+	keySetMu.RLock()
+	defer keySetMu.RUnlock()
+	return accessTokenTTL
+}
+
+func currentKeySet() (*KeySet, error) {
+	keySetMu.RLock()
+	defer keySetMu.RUnlock()
+	if keySet == nil {
+		return nil, ErrNoKeySet
+	}
+	return keySet, nil
+}
+
+// PublicJWKS returns the JWKS document for the installed key set
+func PublicJWKS() (JWKS, error) {
+	ks, err := currentKeySet()
+	if err != nil {
+		return JWKS{}, err
+	}
+	return ks.JWKS(), nil
+}
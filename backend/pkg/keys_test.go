@@ -0,0 +1,314 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func pemBlock(t *testing.T, blockType string, der []byte) []byte {
+	t.Helper()
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+func rsaKeyPEMs(t *testing.T, bits int) (pkcs8, pkcs1, public []byte, key *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pemBlock(t, "PRIVATE KEY", der),
+		pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		pemBlock(t, "PUBLIC KEY", pub),
+		key
+}
+
+func ed25519KeyPEMs(t *testing.T) (private, public []byte, pub ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pemBlock(t, "PRIVATE KEY", der), pemBlock(t, "PUBLIC KEY", pubDER), pub
+}
+
+// useKeySet installs ks for the duration of the test
+func useKeySet(t *testing.T, ks *KeySet) {
+	t.Helper()
+	previous, _ := currentKeySet()
+	SetKeySet(ks)
+	t.Cleanup(func() { SetKeySet(previous) })
+}
+
+func TestParseKey(t *testing.T) {
+	rsaPKCS8, rsaPKCS1, rsaPublic, _ := rsaKeyPEMs(t, 2048)
+	edPrivate, edPublic, _ := ed25519KeyPEMs(t)
+
+	tests := []struct {
+		name     string
+		material []byte
+		alg      string
+		canSign  bool
+	}{
+		{"hmac secret", testSecret, "HS256", true},
+		{"rsa pkcs8", rsaPKCS8, "RS256", true},
+		{"rsa pkcs1", rsaPKCS1, "RS256", true},
+		{"rsa public", rsaPublic, "RS256", false},
+		{"ed25519 private", edPrivate, "EdDSA", true},
+		{"ed25519 public", edPublic, "EdDSA", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParseKey("k1", tt.material)
+			if err != nil {
+				t.Fatalf("ParseKey: %v", err)
+			}
+			if key.ID != "k1" || key.Method.Alg() != tt.alg || key.CanSign() != tt.canSign {
+				t.Fatalf("got id %q alg %s canSign %v, want k1 %s %v", key.ID, key.Method.Alg(), key.CanSign(), tt.alg, tt.canSign)
+			}
+		})
+	}
+}
+
+func TestParseKeyRejects(t *testing.T) {
+	_, _, _, small := rsaKeyPEMs(t, 1024)
+
+	tests := []struct {
+		name     string
+		material []byte
+		want     string
+	}{
+		{"short hmac secret", []byte("too-short"), "at least 32 bytes"},
+		{"small rsa key", pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(small)), "at least 2048 bits"},
+		{"unsupported block", pemBlock(t, "CERTIFICATE", []byte{1, 2, 3}), "unsupported PEM block"},
+		{"corrupt pkcs8", pemBlock(t, "PRIVATE KEY", []byte{1, 2, 3}), `jwt key "k1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKey("k1", tt.material)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewKeySetRejects(t *testing.T) {
+	hmac, err := NewHMACKey("hmac", testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edPublic, _ := ed25519KeyPEMs(t)
+	public, err := ParseKey("public", edPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		active string
+		keys   []Key
+		want   string
+	}{
+		{"duplicate kid", "hmac", []Key{hmac, hmac}, "duplicate jwt key id"},
+		{"unknown active kid", "other", []Key{hmac}, "is not configured"},
+		{"active key without private key", "public", []Key{hmac, public}, "has no private key"},
+		{"missing kid", "", []Key{{Method: hmac.Method, verifyKey: testSecret}}, "id is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeySet(tt.active, tt.keys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensSurviveKeyRotation(t *testing.T) {
+	_, edPublic, _ := ed25519KeyPEMs(t)
+	rsaPKCS8, _, _, _ := rsaKeyPEMs(t, 2048)
+
+	old, err := NewHMACKey("old", testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := ParseKey("new", rsaPKCS8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyOnly, err := ParseKey("verify-only", edPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := NewKeySet("old", []Key{old})
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeySet(t, before)
+	oldToken, err := GenerateToken(TokenParams{UserID: "u1", Role: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NewKeySet("new", []Key{old, current, verifyOnly})
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeySet(t, after)
+	newToken, err := GenerateToken(TokenParams{UserID: "u2", Role: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != "new" || parsed.Method.Alg() != "RS256" {
+		t.Fatalf("new token has kid %v alg %s, want new RS256", parsed.Header["kid"], parsed.Method.Alg())
+	}
+
+	for token, userID := range map[string]string{oldToken: "u1", newToken: "u2"} {
+		claims, err := ParseToken(token)
+		if err != nil {
+			t.Fatalf("ParseToken: %v", err)
+		}
+		if claims.UserID != userID {
+			t.Fatalf("got user %q, want %q", claims.UserID, userID)
+		}
+	}
+
+	// Once the old key is dropped its tokens are rejected
+	dropped, err := NewKeySet("new", []Key{current})
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeySet(t, dropped)
+	if _, err := ParseToken(oldToken); !errors.Is(err, ErrUnknownKeyID) {
+		t.Fatalf("got %v, want ErrUnknownKeyID", err)
+	}
+}
+
+func TestParseTokenRejectsForgedHeaders(t *testing.T) {
+	key, err := NewHMACKey("k1", testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := NewKeySet("k1", []Key{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	useKeySet(t, ks)
+
+	sign := func(method jwt.SigningMethod, header map[string]interface{}, secret interface{}) string {
+		token := jwt.NewWithClaims(method, Claims{UserID: "u1"})
+		for k, v := range header {
+			token.Header[k] = v
+		}
+		signed, err := token.SignedString(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"missing kid", sign(jwt.SigningMethodHS256, map[string]interface{}{"typ": "JWT"}, testSecret), ErrMissingKeyID},
+		{"unknown kid", sign(jwt.SigningMethodHS256, map[string]interface{}{"typ": "JWT", "kid": "k2"}, testSecret), ErrUnknownKeyID},
+		{"wrong type", sign(jwt.SigningMethodHS256, map[string]interface{}{"typ": "action", "kid": "k1"}, testSecret), ErrWrongType},
+		{"algorithm switch", sign(jwt.SigningMethodHS384, map[string]interface{}{"typ": "JWT", "kid": "k1"}, testSecret), jwt.ErrTokenSignatureInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseToken(tt.token); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaPKCS8, _, _, rsaKey := rsaKeyPEMs(t, 2048)
+	_, edPublic, edKey := ed25519KeyPEMs(t)
+
+	hmac, err := NewHMACKey("a-hmac", testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, err := ParseKey("z-active", rsaPKCS8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retiring, err := ParseKey("b-retiring", edPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := NewKeySet("z-active", []Key{hmac, retiring, active})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := ks.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("got %d keys, want 2 without the HMAC secret: %+v", len(set.Keys), set.Keys)
+	}
+
+	// Active key first, then by kid
+	rsaJWK, edJWK := set.Keys[0], set.Keys[1]
+	if rsaJWK.Kid != "z-active" || rsaJWK.Kty != "RSA" || rsaJWK.Alg != "RS256" || rsaJWK.Use != "sig" {
+		t.Fatalf("unexpected RSA JWK %+v", rsaJWK)
+	}
+	if rsaJWK.N != base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()) ||
+		rsaJWK.E != base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()) {
+		t.Fatalf("RSA JWK does not carry the public key: %+v", rsaJWK)
+	}
+	if edJWK.Kid != "b-retiring" || edJWK.Kty != "OKP" || edJWK.Crv != "Ed25519" || edJWK.Alg != "EdDSA" ||
+		edJWK.X != base64.RawURLEncoding.EncodeToString(edKey) {
+		t.Fatalf("unexpected Ed25519 JWK %+v", edJWK)
+	}
+
+	hmacOnly, err := NewKeySet("a-hmac", []Key{hmac})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := hmacOnly.JWKS().Keys; keys == nil || len(keys) != 0 {
+		t.Fatalf("got %v, want an empty key list", keys)
+	}
+}
@@ -0,0 +1,128 @@
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// clockSkew is tolerated between us and the provider
+const clockSkew = time.Minute
+
+// Asymmetric algorithms only; HS256 ID tokens would be signed with the
+// client secret, which we do not treat as a verification key
+var signingAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// IDToken holds the validated claims of an ID token
+type IDToken struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type idTokenClaims struct {
+	Nonce         string   `json:"nonce"`
+	AuthorizedBy  string   `json:"azp"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// flexBool accepts "true" as well as true; some providers send strings
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = flexBool(v == "true")
+	default:
+		*b = false
+	}
+	return nil
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, md *metadata, raw, nonce string) (*IDToken, error) {
+	p.mu.Lock()
+	keys := p.keys
+	p.mu.Unlock()
+
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := keys.lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		// A key that names its algorithm may only be used with it
+		if key.alg != "" && key.alg != t.Method.Alg() {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return key.key, nil
+	},
+		jwt.WithValidMethods(allowedAlgs(md)),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	if claims.IssuedAt == nil {
+		return nil, fmt.Errorf("%w: missing iat", ErrInvalidIDToken)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	// With several audiences the token must have been issued to us
+	if len(claims.Audience) > 1 || claims.AuthorizedBy != "" {
+		if claims.AuthorizedBy != p.cfg.ClientID {
+			return nil, fmt.Errorf("%w: azp does not match the client id", ErrInvalidIDToken)
+		}
+	}
+
+	return &IDToken{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// allowedAlgs narrows signingAlgs to what the provider advertises
+func allowedAlgs(md *metadata) []string {
+	if len(md.IDTokenSigningAlgValues) == 0 {
+		return signingAlgs
+	}
+	var algs []string
+	for _, alg := range md.IDTokenSigningAlgValues {
+		if slices.Contains(signingAlgs, alg) {
+			algs = append(algs, alg)
+		}
+	}
+	if len(algs) == 0 {
+		// Nothing usable; fail every token rather than accept any
+		return []string{"none-supported"}
+	}
+	return algs
+}
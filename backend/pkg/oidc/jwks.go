@@ -0,0 +1,185 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval stops tokens with unknown kids from making us hammer
+// the provider's JWKS endpoint
+const minRefreshInterval = time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// publicKey is a verification key from the provider's JWKS
+type publicKey struct {
+	alg string
+	key interface{}
+}
+
+// keyCache holds the provider's signing keys and refetches them when a
+// token names a kid it has not seen, which is how providers rotate
+type keyCache struct {
+	uri      string
+	provider *Provider
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+}
+
+func newKeyCache(uri string, provider *Provider) *keyCache {
+	return &keyCache{uri: uri, provider: provider}
+}
+
+// lookup returns the key for kid. An empty kid is accepted when the
+// provider publishes a single key.
+func (c *keyCache) lookup(ctx context.Context, kid string) (publicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.find(kid); ok {
+		return key, nil
+	}
+	if !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) < minRefreshInterval {
+		return publicKey{}, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if err := c.refresh(ctx); err != nil {
+		return publicKey{}, err
+	}
+	if key, ok := c.find(kid); ok {
+		return key, nil
+	}
+	return publicKey{}, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *keyCache) find(kid string) (publicKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+func (c *keyCache) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var set jwks
+	status, err := c.provider.doJSON(req, &set)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: %s returned %d", c.uri, status)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		// Encryption keys are never used to sign ID tokens
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(k)
+		if err != nil {
+			// One odd key must not lock everyone out
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+func parseJWK(k jwk) (publicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return publicKey{}, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return publicKey{}, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return publicKey{}, errors.New("rsa exponent is too large")
+		}
+		return publicKey{alg: k.Alg, key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return publicKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return publicKey{}, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return publicKey{}, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return publicKey{}, errors.New("ec point is not on the curve")
+		}
+		return publicKey{alg: k.Alg, key: &ecdsa.PublicKey{Curve: curve, X: x, Y: y}}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return publicKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, errors.New("invalid ed25519 key")
+		}
+		return publicKey{alg: k.Alg, key: ed25519.PublicKey(x)}, nil
+	default:
+		return publicKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,281 @@
+// Package oidc is a minimal OpenID Connect relying party: discovery, the
+// authorization code flow with PKCE (S256) and ID token validation. It
+// works with any provider that follows OpenID Connect Core and Discovery.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// discoveryTTL is how long the provider metadata is reused
+	discoveryTTL = time.Hour
+	// maxResponseSize caps what is read from provider endpoints
+	maxResponseSize = 1 << 20
+)
+
+var (
+	ErrDiscovery      = errors.New("oidc discovery failed")
+	ErrExchange       = errors.New("oidc code exchange failed")
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+type Config struct {
+	// Issuer is the provider's issuer identifier; discovery is read from
+	// Issuer + "/.well-known/openid-configuration"
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes always include openid
+	Scopes     []string
+	HTTPClient *http.Client
+}
+
+// Provider talks to one identity provider. Metadata and signing keys are
+// fetched on first use and cached, so a provider that is down does not
+// stop the application from starting.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu           sync.Mutex
+	metadata     *metadata
+	discoveredAt time.Time
+	keys         *keyCache
+}
+
+type metadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+	IDTokenSigningAlgValues       []string `json:"id_token_signing_alg_values_supported"`
+}
+
+func NewProvider(cfg Config) (*Provider, error) {
+	issuer, err := url.Parse(cfg.Issuer)
+	if err != nil || issuer.Host == "" || (issuer.Scheme != "https" && issuer.Scheme != "http") {
+		return nil, fmt.Errorf("oidc issuer must be an absolute URL, got %q", cfg.Issuer)
+	}
+	if cfg.ClientID == "" {
+		return nil, errors.New("oidc client id is required")
+	}
+	if _, err := url.ParseRequestURI(cfg.RedirectURL); err != nil {
+		return nil, fmt.Errorf("oidc redirect url is invalid: %w", err)
+	}
+	if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Provider{cfg: cfg, client: client}, nil
+}
+
+// Issuer returns the configured issuer identifier
+func (p *Provider) Issuer() string {
+	return p.cfg.Issuer
+}
+
+// AuthRequest is one authorization code flow in progress. State, Nonce and
+// CodeVerifier must be kept server side until the callback.
+type AuthRequest struct {
+	URL          string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// NewAuthRequest builds the authorization URL the browser is sent to
+func (p *Provider) NewAuthRequest(ctx context.Context) (*AuthRequest, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &AuthRequest{}
+	for _, v := range []*string{&req.State, &req.Nonce, &req.CodeVerifier} {
+		if *v, err = randomString(); err != nil {
+			return nil, err
+		}
+	}
+
+	challenge := sha256.Sum256([]byte(req.CodeVerifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {req.State},
+		"nonce":                 {req.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	authURL, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid authorization endpoint", ErrDiscovery)
+	}
+	// Keep any query the provider put in its endpoint
+	existing := authURL.Query()
+	for k, v := range query {
+		existing[k] = v
+	}
+	authURL.RawQuery = existing.Encode()
+	req.URL = authURL.String()
+
+	return req, nil
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange redeems an authorization code and returns the validated ID
+// token. nonce and codeVerifier come from the matching AuthRequest.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*IDToken, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+
+	// client_secret_basic is the default authentication method; public
+	// clients only identify themselves
+	useBasic := p.cfg.ClientSecret != "" &&
+		(len(md.TokenEndpointAuthMethods) == 0 || slices.Contains(md.TokenEndpointAuthMethods, "client_secret_basic"))
+	if !useBasic {
+		form.Set("client_id", p.cfg.ClientID)
+		if p.cfg.ClientSecret != "" {
+			form.Set("client_secret", p.cfg.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if useBasic {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var body tokenResponse
+	status, err := p.doJSON(req, &body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	if status != http.StatusOK || body.Error != "" {
+		if body.Error == "" {
+			body.Error = http.StatusText(status)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrExchange, strings.TrimSpace(body.Error+" "+body.ErrorDescription))
+	}
+	if body.IDToken == "" {
+		return nil, fmt.Errorf("%w: response has no id_token", ErrExchange)
+	}
+
+	return p.verifyIDToken(ctx, md, body.IDToken, nonce)
+}
+
+// discover returns the provider metadata, fetching it when the cached
+// copy is missing or stale
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil && time.Since(p.discoveredAt) < discoveryTTL {
+		return p.metadata, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+
+	var md metadata
+	status, err := p.doJSON(req, &md)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %d", ErrDiscovery, endpoint, status)
+	}
+
+	// The issuer must match exactly, or tokens from another tenant of the
+	// same provider could be accepted
+	if md.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q does not match %q", ErrDiscovery, md.Issuer, p.cfg.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: metadata is missing required endpoints", ErrDiscovery)
+	}
+	if len(md.CodeChallengeMethodsSupported) > 0 && !slices.Contains(md.CodeChallengeMethodsSupported, "S256") {
+		return nil, fmt.Errorf("%w: provider does not support PKCE with S256", ErrDiscovery)
+	}
+
+	if p.keys == nil || p.keys.uri != md.JWKSURI {
+		p.keys = newKeyCache(md.JWKSURI, p)
+	}
+	p.metadata = &md
+	p.discoveredAt = time.Now()
+	return p.metadata, nil
+}
+
+// doJSON sends req and decodes a JSON body whatever the status code, since
+// token endpoints report errors as JSON
+func (p *Provider) doJSON(req *http.Request, dest interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(data, dest); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("invalid JSON from %s: %v", req.URL, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// randomString returns 256 random bits, URL-safe encoded. It is valid as
+// a PKCE code verifier (43 characters).
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
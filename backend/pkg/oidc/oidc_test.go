@@ -0,0 +1,378 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "backend"
+	testKeyID    = "idp-key-1"
+)
+
+// mockIdP is an in-process provider with discovery, JWKS and a token
+// endpoint that enforces PKCE
+type mockIdP struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]pendingCode
+}
+
+// pendingCode is what the authorization endpoint would have recorded
+type pendingCode struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &mockIdP{t: t, key: key, codes: make(map[string]pendingCode)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *mockIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                idp.server.URL,
+		"authorization_endpoint":                idp.server.URL + "/authorize",
+		"token_endpoint":                        idp.server.URL + "/token",
+		"jwks_uri":                              idp.server.URL + "/jwks",
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (idp *mockIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := idp.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if id, secret, ok := r.BasicAuth(); !ok || id != testClientID || secret != "secret" {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	idp.mu.Lock()
+	pending, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != pending.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":             "invalid_grant",
+			"error_description": "PKCE verification failed",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": idp.sign(pending.claims)})
+}
+
+func (idp *mockIdP) sign(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	return signed
+}
+
+// authorize stands in for the user signing in at the provider: it records
+// the request's PKCE challenge and returns a code whose ID token carries
+// the default claims changed by edit
+func (idp *mockIdP) authorize(t *testing.T, authURL string, edit func(jwt.MapClaims)) string {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", query.Get("code_challenge_method"))
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"sub":            "user-123",
+		"aud":            testClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          query.Get("nonce"),
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+	}
+	if edit != nil {
+		edit(claims)
+	}
+
+	code := "code-" + query.Get("state")
+	idp.mu.Lock()
+	idp.codes[code] = pendingCode{challenge: query.Get("code_challenge"), claims: claims}
+	idp.mu.Unlock()
+	return code
+}
+
+func (idp *mockIdP) provider(t *testing.T) *Provider {
+	t.Helper()
+	p, err := NewProvider(Config{
+		Issuer:       idp.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: "secret",
+		RedirectURL:  "https://app.example.com/auth/callback",
+		HTTPClient:   idp.server.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// login runs one authorization code flow against the mock provider
+func login(t *testing.T, idp *mockIdP, edit func(jwt.MapClaims)) (*IDToken, error) {
+	t.Helper()
+	ctx := context.Background()
+	p := idp.provider(t)
+	req, err := p.NewAuthRequest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := idp.authorize(t, req.URL, edit)
+	return p.Exchange(ctx, code, req.CodeVerifier, req.Nonce)
+}
+
+func TestExchange(t *testing.T) {
+	idp := newMockIdP(t)
+
+	token, err := login(t, idp, nil)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := IDToken{
+		Issuer:        idp.server.URL,
+		Subject:       "user-123",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	}
+	if *token != want {
+		t.Fatalf("got %+v, want %+v", *token, want)
+	}
+}
+
+func TestExchangeEmailVerified(t *testing.T) {
+	idp := newMockIdP(t)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  bool
+	}{
+		{"false", false, false},
+		{"string true", "true", true},
+		{"string false", "false", false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := login(t, idp, func(c jwt.MapClaims) {
+				if tt.value == nil {
+					delete(c, "email_verified")
+				} else {
+					c["email_verified"] = tt.value
+				}
+			})
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			if token.EmailVerified != tt.want {
+				t.Fatalf("EmailVerified = %v, want %v", token.EmailVerified, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsInvalidIDToken(t *testing.T) {
+	idp := newMockIdP(t)
+
+	tests := []struct {
+		name string
+		edit func(jwt.MapClaims)
+	}{
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{"expired", func(c jwt.MapClaims) {
+			c["iat"] = time.Now().Add(-time.Hour).Unix()
+			c["exp"] = time.Now().Add(-10 * time.Minute).Unix()
+		}},
+		{"missing exp", func(c jwt.MapClaims) { delete(c, "exp") }},
+		{"nonce mismatch", func(c jwt.MapClaims) { c["nonce"] = "replayed-nonce" }},
+		{"missing nonce", func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{"missing subject", func(c jwt.MapClaims) { delete(c, "sub") }},
+		{"missing iat", func(c jwt.MapClaims) { delete(c, "iat") }},
+		{"other azp", func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "another-client"}
+			c["azp"] = "another-client"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := login(t, idp, tt.edit)
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("err = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsForeignSignatures(t *testing.T) {
+	idp := newMockIdP(t)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		sign func(jwt.MapClaims) string
+	}{
+		{"unknown key", func(c jwt.MapClaims) string {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+			token.Header["kid"] = testKeyID
+			signed, _ := token.SignedString(other)
+			return signed
+		}},
+		{"hmac", func(c jwt.MapClaims) string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
+			token.Header["kid"] = testKeyID
+			signed, _ := token.SignedString([]byte("secret"))
+			return signed
+		}},
+		{"none", func(c jwt.MapClaims) string {
+			token := jwt.NewWithClaims(jwt.SigningMethodNone, c)
+			signed, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+			return signed
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			p := idp.provider(t)
+			req, err := p.NewAuthRequest(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			code := idp.authorize(t, req.URL, nil)
+			md, err := p.discover(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			idp.mu.Lock()
+			claims := idp.codes[code].claims
+			idp.mu.Unlock()
+			if _, err := p.verifyIDToken(ctx, md, tt.sign(claims), req.Nonce); !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("err = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestExchangeEnforcesPKCE(t *testing.T) {
+	idp := newMockIdP(t)
+	ctx := context.Background()
+	p := idp.provider(t)
+
+	req, err := p.NewAuthRequest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := idp.authorize(t, req.URL, nil)
+
+	other, err := p.NewAuthRequest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Exchange(ctx, code, other.CodeVerifier, req.Nonce); !errors.Is(err, ErrExchange) {
+		t.Fatalf("wrong verifier: err = %v, want ErrExchange", err)
+	}
+
+	// The code is spent even though the exchange failed
+	if _, err := p.Exchange(ctx, code, req.CodeVerifier, req.Nonce); !errors.Is(err, ErrExchange) {
+		t.Fatalf("reused code: err = %v, want ErrExchange", err)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+	// Discovery is read from the same place, but the issuer it reports
+	// lacks the trailing slash
+	p, err := NewProvider(Config{
+		Issuer:      idp.server.URL + "/",
+		ClientID:    testClientID,
+		RedirectURL: "https://app.example.com/auth/callback",
+		HTTPClient:  idp.server.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.NewAuthRequest(context.Background()); !errors.Is(err, ErrDiscovery) {
+		t.Fatalf("err = %v, want ErrDiscovery", err)
+	}
+}
@@ -0,0 +1,21 @@
+package password
+
+// commonPasswords are rejected regardless of configuration. Longer lists
+// can be supplied through PASSWORD_DENYLIST_FILE.
+var commonPasswords = []string{
+	"123456", "123456789", "12345678", "1234567890", "12345", "1234567",
+	"password", "password1", "password12", "password123", "password1234",
+	"passw0rd", "p@ssw0rd", "p@ssword", "pa$$word",
+	"qwerty", "qwerty123", "qwertyuiop", "1q2w3e4r", "1q2w3e4r5t", "qazwsx",
+	"abc123", "abcd1234", "a1b2c3d4", "111111", "000000", "11111111",
+	"00000000", "123123", "123123123", "987654321", "654321", "666666",
+	"iloveyou", "iloveyou1", "letmein", "letmein1", "welcome", "welcome1",
+	"welcome123", "admin", "admin123", "administrator", "root", "toor",
+	"changeme", "changeme123", "default", "secret", "secret123",
+	"monkey", "dragon", "football", "baseball", "superman", "batman",
+	"sunshine", "princess", "shadow", "master", "trustno1", "starwars",
+	"whatever", "freedom", "computer", "internet", "michael", "jennifer",
+	"login", "access", "test", "test123", "testing", "testtest", "guest",
+	"zaq12wsx", "asdfghjkl", "asdf1234", "zxcvbnm", "zxcvbnm123",
+	"summer2024", "winter2024", "spring2024", "autumn2024",
+}
@@ -0,0 +1,146 @@
+// Package password hashes passwords with argon2id in PHC string format,
+// verifies legacy bcrypt hashes, and checks new passwords against a policy.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	algorithm  = "argon2id"
+	saltLength = 16
+	keyLength  = 32
+)
+
+// Params are the argon2id cost parameters. Memory is in KiB.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultParams are the second recommended option of RFC 9106 (64 MiB,
+// 3 passes) with two lanes instead of four
+var DefaultParams = Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 2}
+
+var (
+	paramsMu sync.RWMutex
+	params   = DefaultParams
+)
+
+// SetParams sets the cost of new hashes. Stored hashes with other
+// parameters still verify and report NeedsRehash.
+func SetParams(p Params) error {
+	if p.Iterations < 1 || p.Parallelism < 1 {
+		return errors.New("argon2 iterations and parallelism must be at least 1")
+	}
+	if p.Memory < 8*uint32(p.Parallelism) {
+		return fmt.Errorf("argon2 memory must be at least %d KiB for parallelism %d", 8*uint32(p.Parallelism), p.Parallelism)
+	}
+
+	paramsMu.Lock()
+	defer paramsMu.Unlock()
+	params = p
+	return nil
+}
+
+func currentParams() Params {
+	paramsMu.RLock()
+	defer paramsMu.RUnlock()
+	return params
+}
+
+// Hash returns an argon2id hash such as
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func Hash(plain string) (string, error) {
+	p := currentParams()
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, keyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		algorithm, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks plain against an argon2id or bcrypt hash
+func Verify(plain, encoded string) bool {
+	if isBcrypt(encoded) {
+		return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain)) == nil
+	}
+
+	h, err := decode(encoded)
+	if err != nil {
+		return false
+	}
+
+	key := argon2.IDKey([]byte(plain), h.salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, uint32(len(h.key)))
+	return subtle.ConstantTimeCompare(key, h.key) == 1
+}
+
+// NeedsRehash reports whether a hash uses another algorithm or other
+// parameters than new hashes would
+func NeedsRehash(encoded string) bool {
+	h, err := decode(encoded)
+	if err != nil {
+		return true
+	}
+	return h.params != currentParams() || len(h.salt) != saltLength || len(h.key) != keyLength
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+type argon2Hash struct {
+	params Params
+	salt   []byte
+	key    []byte
+}
+
+func decode(encoded string) (*argon2Hash, error) {
+	// "", algorithm, version, parameters, salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != algorithm {
+		return nil, errors.New("not an argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, errors.New("unsupported argon2 version")
+	}
+
+	var h argon2Hash
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.params.Memory, &h.params.Iterations, &h.params.Parallelism); err != nil {
+		return nil, errors.New("invalid argon2 parameters")
+	}
+	if h.params.Iterations < 1 || h.params.Parallelism < 1 {
+		return nil, errors.New("invalid argon2 parameters")
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, errors.New("invalid argon2 salt")
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(h.key) == 0 {
+		return nil, errors.New("invalid argon2 key")
+	}
+
+	return &h, nil
+}
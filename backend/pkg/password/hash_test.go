@@ -0,0 +1,161 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheapParams keeps the tests fast
+var cheapParams = Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func useParams(t *testing.T, p Params) {
+	t.Helper()
+	previous := currentParams()
+	if err := SetParams(p); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetParams(previous) })
+}
+
+func TestHashRoundTrip(t *testing.T) {
+	useParams(t, cheapParams)
+
+	for _, plain := range []string{"correct horse battery staple", "", "pässwörd ✓", strings.Repeat("x", MaxLength)} {
+		encoded, err := Hash(plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+			t.Fatalf("unexpected encoding %q", encoded)
+		}
+		if !Verify(plain, encoded) {
+			t.Fatalf("Verify(%q) failed for its own hash", plain)
+		}
+		if Verify(plain+"x", encoded) {
+			t.Fatalf("Verify accepted a different password for %q", plain)
+		}
+		if NeedsRehash(encoded) {
+			t.Fatal("a fresh hash must not need rehashing")
+		}
+
+		h, err := decode(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.params != cheapParams || len(h.salt) != saltLength || len(h.key) != keyLength {
+			t.Fatalf("decoded %+v", h)
+		}
+	}
+}
+
+func TestHashUsesRandomSalt(t *testing.T) {
+	useParams(t, cheapParams)
+
+	first, _ := Hash("secret-password")
+	second, _ := Hash("secret-password")
+	if first == second {
+		t.Fatal("two hashes of the same password are identical")
+	}
+}
+
+func TestNeedsRehashAfterParamsChange(t *testing.T) {
+	useParams(t, cheapParams)
+	encoded, err := Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useParams(t, Params{Memory: 128, Iterations: 2, Parallelism: 1})
+	if !Verify("secret-password", encoded) {
+		t.Fatal("a hash with older parameters must still verify")
+	}
+	if !NeedsRehash(encoded) {
+		t.Fatal("a hash with older parameters must be flagged for rehash")
+	}
+}
+
+func TestBcryptVerifiesAndNeedsRehash(t *testing.T) {
+	useParams(t, cheapParams)
+
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		legacy, err := bcrypt.GenerateFromPassword([]byte("legacy-password"), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded := prefix + strings.TrimPrefix(string(legacy), "$2a$")
+
+		if !Verify("legacy-password", encoded) {
+			t.Fatalf("%s hash did not verify", prefix)
+		}
+		if Verify("wrong-password", encoded) {
+			t.Fatalf("%s hash accepted the wrong password", prefix)
+		}
+		if !NeedsRehash(encoded) {
+			t.Fatalf("%s hash was not flagged for rehash", prefix)
+		}
+	}
+}
+
+func TestMalformedHashes(t *testing.T) {
+	useParams(t, cheapParams)
+	valid, err := Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, "$")
+	salt, key := parts[4], parts[5]
+
+	tests := map[string]string{
+		"empty":             "",
+		"plain text":        "secret-password",
+		"too few fields":    "$argon2id$v=19$m=64,t=1,p=1$" + salt,
+		"too many fields":   valid + "$extra",
+		"no leading dollar": strings.TrimPrefix(valid, "$"),
+		"argon2i":           "$argon2i$v=19$m=64,t=1,p=1$" + salt + "$" + key,
+		"old version":       "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key,
+		"missing version":   "$argon2id$$m=64,t=1,p=1$" + salt + "$" + key,
+		"garbled params":    "$argon2id$v=19$m=64;t=1;p=1$" + salt + "$" + key,
+		"zero iterations":   "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key,
+		"zero parallelism":  "$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key,
+		"overflowing p":     "$argon2id$v=19$m=64,t=1,p=256$" + salt + "$" + key,
+		"negative memory":   "$argon2id$v=19$m=-1,t=1,p=1$" + salt + "$" + key,
+		"bad salt":          "$argon2id$v=19$m=64,t=1,p=1$!!!$" + key,
+		"padded key":        "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$" + key + "=",
+		"empty key":         "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$",
+		"truncated bcrypt":  "$2a$04$abc",
+	}
+	for name, encoded := range tests {
+		t.Run(name, func(t *testing.T) {
+			if name != "truncated bcrypt" {
+				if _, err := decode(encoded); err == nil {
+					t.Fatalf("decode(%q) succeeded", encoded)
+				}
+			}
+			if Verify("secret-password", encoded) {
+				t.Fatalf("Verify accepted %q", encoded)
+			}
+			if !NeedsRehash(encoded) {
+				t.Fatalf("NeedsRehash(%q) = false", encoded)
+			}
+		})
+	}
+}
+
+func TestSetParamsRejectsWeakSettings(t *testing.T) {
+	useParams(t, cheapParams)
+
+	for _, p := range []Params{
+		{Memory: 64, Iterations: 0, Parallelism: 1},
+		{Memory: 64, Iterations: 1, Parallelism: 0},
+		{Memory: 15, Iterations: 1, Parallelism: 2},
+	} {
+		if err := SetParams(p); err == nil {
+			t.Fatalf("SetParams(%+v) succeeded", p)
+		}
+	}
+	if currentParams() != cheapParams {
+		t.Fatal("a rejected SetParams changed the parameters")
+	}
+}
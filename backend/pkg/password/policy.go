@@ -0,0 +1,129 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength bounds the work an attacker can cause per hash
+const MaxLength = 128
+
+// minRelatedLength is the shortest name or email part worth rejecting
+const minRelatedLength = 4
+
+// Policy decides which new passwords are acceptable. Existing passwords
+// are never re-checked.
+type Policy struct {
+	minLength      int
+	minCharClasses int
+	denied         map[string]bool
+}
+
+// NewPolicy builds a policy. denylist is added to a built-in list of the
+// most common passwords and is matched case-insensitively.
+func NewPolicy(minLength, minCharClasses int, denylist []string) *Policy {
+	p := &Policy{
+		minLength:      minLength,
+		minCharClasses: minCharClasses,
+		denied:         make(map[string]bool, len(commonPasswords)+len(denylist)),
+	}
+	for _, list := range [][]string{commonPasswords, denylist} {
+		for _, entry := range list {
+			if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+				p.denied[entry] = true
+			}
+		}
+	}
+	return p
+}
+
+// PolicyError lists every rule a password breaks
+type PolicyError struct {
+	Violations []string
+}
+
+func (e *PolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, ", ")
+}
+
+// Validate checks plain against the policy. related are values such as
+// the user's name and email that the password must not contain.
+func (p *Policy) Validate(plain string, related ...string) error {
+	var violations []string
+
+	length := utf8.RuneCountInString(plain)
+	if length < p.minLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", p.minLength))
+	}
+	if length > MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters", MaxLength))
+	}
+
+	if classes := charClasses(plain); classes < p.minCharClasses {
+		violations = append(violations, fmt.Sprintf(
+			"must mix at least %d of lowercase letters, uppercase letters, digits and symbols", p.minCharClasses))
+	}
+
+	lower := strings.ToLower(plain)
+	if p.denied[lower] {
+		violations = append(violations, "is too common")
+	}
+
+	for _, value := range relatedParts(related) {
+		if strings.Contains(lower, value) {
+			violations = append(violations, "must not contain your name or email address")
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func charClasses(s string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// relatedParts splits names into words and emails into the local part,
+// keeping only parts long enough to matter
+func relatedParts(related []string) []string {
+	var parts []string
+	for _, value := range related {
+		value = strings.ToLower(value)
+		if local, _, ok := strings.Cut(value, "@"); ok {
+			value = local
+		}
+		for _, part := range strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if utf8.RuneCountInString(part) >= minRelatedLength {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return parts
+}
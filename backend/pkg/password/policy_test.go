@@ -0,0 +1,76 @@
+package password
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := NewPolicy(10, 3, []string{"  Acme-Widgets-2024 ", ""})
+
+	tooShort := "must be at least 10 characters"
+	tooLong := "must be at most 128 characters"
+	tooPlain := "must mix at least 3 of lowercase letters, uppercase letters, digits and symbols"
+	common := "is too common"
+	personal := "must not contain your name or email address"
+
+	tests := []struct {
+		name     string
+		password string
+		related  []string
+		want     []string
+	}{
+		{"accepted", "Tr4vel-Lamp", nil, nil},
+		{"three classes without symbols", "Travel4Lamp", nil, nil},
+		{"unicode letters count as classes", "Ünïcödé-pässwörd", nil, nil},
+		{"length counts characters not bytes", "Ääääääää1!", nil, nil},
+		{"too short", "Ab1!", nil, []string{tooShort}},
+		{"too long", "Aa1!" + strings.Repeat("x", MaxLength), nil, []string{tooLong}},
+		{"two classes", "travellamp42", nil, []string{tooPlain}},
+		{"one class", "correcthorsebattery", nil, []string{tooPlain}},
+		{"built-in list ignores case", "Password1234", nil, []string{common}},
+		{"configured list is trimmed and case-insensitive", "acme-widgets-2024", nil, []string{common}},
+		{"short name parts are ignored", "Tr4vel-Al-Doe", []string{"Al Doe"}, nil},
+		{"contains name", "Tr4vel-Jane!", []string{"Jane Doe"}, []string{personal}},
+		{"contains email local part", "X9!doejane99", []string{"doejane99@example.com"}, []string{personal}},
+		{"email domain is not checked", "Example-Lamp9", []string{"jane@example.com"}, nil},
+		{"name match ignores case", "Tr4vel-SMITHERS", []string{"smithers"}, []string{personal}},
+		{"every violation is listed", "password", []string{"Password Person"}, []string{tooShort, tooPlain, common, personal}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password, tt.related...)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate(%q) = %v", tt.password, err)
+				}
+				return
+			}
+
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("Validate(%q) = %v, want a PolicyError", tt.password, err)
+			}
+			if !reflect.DeepEqual(policyErr.Violations, tt.want) {
+				t.Fatalf("violations %q, want %q", policyErr.Violations, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyErrorMessage(t *testing.T) {
+	err := NewPolicy(12, 1, nil).Validate("qwerty")
+	if err == nil || err.Error() != "password must be at least 12 characters, is too common" {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestRelatedParts(t *testing.T) {
+	got := relatedParts([]string{"Mary-Jane O'Neil", "mj.oneil+test@example.com", ""})
+	want := []string{"mary", "jane", "neil", "oneil", "test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("relatedParts = %q, want %q", got, want)
+	}
+}
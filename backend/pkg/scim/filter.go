@@ -0,0 +1,302 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// maxFilterDepth bounds nesting so hostile filters cannot exhaust the stack
+const maxFilterDepth = 16
+
+// Expr is a parsed filter: *Compare, *Logical, *Not or *ValuePath
+type Expr interface {
+	expr()
+}
+
+// Compare is "attr op value", or "attr pr" with a nil Value. Attr and Op
+// are lower case; Value is a string, float64, bool or nil.
+type Compare struct {
+	Attr  string
+	Op    string
+	Value interface{}
+}
+
+// Logical joins two filters with "and" or "or"
+type Logical struct {
+	Op          string
+	Left, Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+// ValuePath filters a multi-valued attribute, e.g. emails[type eq "work"]
+type ValuePath struct {
+	Attr   string
+	Filter Expr
+}
+
+func (*Compare) expr()   {}
+func (*Logical) expr()   {}
+func (*Not) expr()       {}
+func (*ValuePath) expr() {}
+
+var compareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// ParseFilter parses the filter query parameter of RFC 7644 section
+// 3.4.2.2. Errors are *Error with scimType invalidFilter.
+func ParseFilter(filter string) (Expr, error) {
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, invalidFilter("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+func invalidFilter(format string, args ...interface{}) *Error {
+	return NewError(http.StatusBadRequest, ErrInvalidFilter, fmt.Sprintf(format, args...))
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// value holds decoded strings and numbers
+	value interface{}
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, invalidFilter("unterminated string")
+			}
+			var value string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &value); err != nil {
+				return nil, invalidFilter("invalid string %s", s[i:end+1])
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s[i : end+1], value: value})
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+				end++
+			}
+			var value float64
+			if err := json.Unmarshal([]byte(s[i:end]), &value); err != nil {
+				return nil, invalidFilter("invalid number %q", s[i:end])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: s[i:end], value: value})
+			i = end
+		case isWordByte(c):
+			end := i + 1
+			for end < len(s) && isWordByte(s[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: s[i:end]})
+			i = end
+		default:
+			return nil, invalidFilter("unexpected character %q", c)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, invalidFilter("empty filter")
+	}
+	return tokens, nil
+}
+
+// Attribute paths may carry a schema URN prefix, hence ':' and '.'
+func isWordByte(c byte) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || strings.IndexByte("._-:$", c) >= 0)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, error) {
+	t, ok := p.peek()
+	if !ok {
+		return token{}, invalidFilter("unexpected end of filter")
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) keyword(word string) bool {
+	t, ok := p.peek()
+	if ok && t.kind == tokenWord && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != tokenPunct || t.text != punct {
+		return invalidFilter("expected %q, got %q", punct, t.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr(depth int) (Expr, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd(depth int) (Expr, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary(depth int) (Expr, error) {
+	if depth > maxFilterDepth {
+		return nil, invalidFilter("filter is nested too deeply")
+	}
+
+	if p.keyword("not") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &Not{Expr: inner}, nil
+	}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.kind == tokenPunct && t.text == "(" {
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+	if t.kind != tokenWord {
+		return nil, invalidFilter("expected an attribute, got %q", t.text)
+	}
+	attr := strings.ToLower(t.text)
+
+	if next, ok := p.peek(); ok && next.kind == tokenPunct && next.text == "[" {
+		p.pos++
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &ValuePath{Attr: attr, Filter: inner}, nil
+	}
+
+	opToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(opToken.text)
+	if opToken.kind != tokenWord || (op != "pr" && !compareOps[op]) {
+		return nil, invalidFilter("unknown operator %q", opToken.text)
+	}
+	if op == "pr" {
+		return &Compare{Attr: attr, Op: op}, nil
+	}
+
+	valueToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	switch {
+	case valueToken.kind == tokenString || valueToken.kind == tokenNumber:
+		value = valueToken.value
+	case valueToken.kind == tokenWord && strings.EqualFold(valueToken.text, "true"):
+		value = true
+	case valueToken.kind == tokenWord && strings.EqualFold(valueToken.text, "false"):
+		value = false
+	case valueToken.kind == tokenWord && strings.EqualFold(valueToken.text, "null"):
+		value = nil
+	default:
+		return nil, invalidFilter("invalid value %q", valueToken.text)
+	}
+	return &Compare{Attr: attr, Op: op, Value: value}, nil
+}
@@ -0,0 +1,153 @@
+package scim
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// format prints a parsed filter in prefix notation so tests can compare
+// whole trees, grouping included
+func format(expr Expr) string {
+	switch e := expr.(type) {
+	case *Compare:
+		if e.Op == "pr" {
+			return fmt.Sprintf("(%s pr)", e.Attr)
+		}
+		return fmt.Sprintf("(%s %s %#v)", e.Attr, e.Op, e.Value)
+	case *Logical:
+		return fmt.Sprintf("(%s %s %s)", e.Op, format(e.Left), format(e.Right))
+	case *Not:
+		return fmt.Sprintf("(not %s)", format(e.Expr))
+	case *ValuePath:
+		return fmt.Sprintf("(%s[] %s)", e.Attr, format(e.Filter))
+	}
+	return "?"
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		// Operators
+		{`userName eq "bjensen"`, `(username eq "bjensen")`},
+		{`userName ne "bjensen"`, `(username ne "bjensen")`},
+		{`name.familyName co "O'Malley"`, `(name.familyname co "O'Malley")`},
+		{`userName sw "J"`, `(username sw "J")`},
+		{`userName ew "@example.com"`, `(username ew "@example.com")`},
+		{`meta.lastModified gt "2011-05-13T04:42:34Z"`, `(meta.lastmodified gt "2011-05-13T04:42:34Z")`},
+		{`meta.lastModified ge "2011-05-13T04:42:34Z"`, `(meta.lastmodified ge "2011-05-13T04:42:34Z")`},
+		{`meta.lastModified lt "2011-05-13T04:42:34Z"`, `(meta.lastmodified lt "2011-05-13T04:42:34Z")`},
+		{`meta.lastModified le "2011-05-13T04:42:34Z"`, `(meta.lastmodified le "2011-05-13T04:42:34Z")`},
+		{`title pr`, `(title pr)`},
+
+		// Operators, keywords and attributes ignore case; values do not
+		{`UserName EQ "BJensen"`, `(username eq "BJensen")`},
+		{`title PR AND NOT (active Eq False)`, `(and (title pr) (not (active eq false)))`},
+
+		// Values
+		{`active eq true`, `(active eq true)`},
+		{`active eq false`, `(active eq false)`},
+		{`externalId eq null`, `(externalid eq <nil>)`},
+		{`age gt 42`, `(age gt 42)`},
+		{`score le -1.5e2`, `(score le -150)`},
+
+		// Schema URNs stay part of the attribute
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "a"`, `(urn:ietf:params:scim:schemas:core:2.0:user:username eq "a")`},
+
+		// and binds tighter than or, both associate to the left
+		{`a pr or b pr and c pr`, `(or (a pr) (and (b pr) (c pr)))`},
+		{`a pr and b pr or c pr`, `(or (and (a pr) (b pr)) (c pr))`},
+		{`a pr and b pr and c pr`, `(and (and (a pr) (b pr)) (c pr))`},
+		{`a pr or b pr or c pr`, `(or (or (a pr) (b pr)) (c pr))`},
+		{`(a pr or b pr) and c pr`, `(and (or (a pr) (b pr)) (c pr))`},
+		{`not (a pr or b pr) and c pr`, `(and (not (or (a pr) (b pr))) (c pr))`},
+		{`((a pr))`, `(a pr)`},
+
+		// Value paths
+		{`emails[type eq "work" and value co "@example.com"]`, `(emails[] (and (type eq "work") (value co "@example.com")))`},
+		{`emails[type eq "work"] or userName eq "x"`, `(or (emails[] (type eq "work")) (username eq "x"))`},
+
+		// Strings are JSON strings
+		{`userName eq "say \"hi\""`, `(username eq "say \"hi\"")`},
+		{`userName eq "back\\slash"`, `(username eq "back\\slash")`},
+		{`userName eq "été"`, `(username eq "été")`},
+		{`userName eq "a and b or not (c)"`, `(username eq "a and b or not (c)")`},
+		{`userName eq ""`, `(username eq "")`},
+		{"userName\teq\t\"tab\"", `(username eq "tab")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, err := ParseFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("ParseFilter: %v", err)
+			}
+			if got := format(expr); got != tt.want {
+				t.Fatalf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterRejectsMalformed(t *testing.T) {
+	deep := strings.Repeat("(", maxFilterDepth+2) + "a pr" + strings.Repeat(")", maxFilterDepth+2)
+	deepNot := strings.Repeat("not (", maxFilterDepth+2) + "a pr" + strings.Repeat(")", maxFilterDepth+2)
+
+	for _, filter := range []string{
+		``,
+		`   `,
+		`userName`,
+		`userName eq`,
+		`userName eq "unterminated`,
+		`userName eq "escaped quote\"`,
+		`userName eq "bad \x escape"`,
+		`userName eq 'single quotes'`,
+		`userName eq bjensen`,
+		`userName like "b%"`,
+		`userName eq "a" "b"`,
+		`userName eq "a" and`,
+		`userName eq "a" or or userName eq "b"`,
+		`and userName eq "a"`,
+		`(userName eq "a"`,
+		`userName eq "a")`,
+		`()`,
+		`not userName eq "a"`,
+		`not (userName eq "a"`,
+		`emails[type eq "work"`,
+		`emails[]`,
+		`emails]`,
+		`"userName" eq "a"`,
+		`42 eq 42`,
+		`userName eq -`,
+		`userName eq 1.2.3`,
+		`userName pr "extra"`,
+		`userName eq "a"; DROP TABLE users`,
+		`userName eq "a" -- comment`,
+		`user*Name eq "a"`,
+		`userName = "a"`,
+		"userName eq \"a\"\n",
+		deep,
+		deepNot,
+	} {
+		t.Run(filter, func(t *testing.T) {
+			expr, err := ParseFilter(filter)
+			if err == nil {
+				t.Fatalf("ParseFilter accepted it as %s", format(expr))
+			}
+			var scimErr *Error
+			if !errors.As(err, &scimErr) || scimErr.ScimType != ErrInvalidFilter || scimErr.StatusCode() != http.StatusBadRequest {
+				t.Fatalf("err = %#v, want a 400 invalidFilter", err)
+			}
+		})
+	}
+}
+
+func TestParseFilterAllowsNestingUpToLimit(t *testing.T) {
+	filter := strings.Repeat("(", maxFilterDepth) + "a pr" + strings.Repeat(")", maxFilterDepth)
+	if _, err := ParseFilter(filter); err != nil {
+		t.Fatalf("ParseFilter: %v", err)
+	}
+}
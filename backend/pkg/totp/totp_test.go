@@ -0,0 +1,153 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of RFC 6238 appendix B, "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// The appendix lists 8 digit codes; 6 digit codes are their last 6 digits
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+func TestCodeMatchesRFC6238Vectors(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		want := v.code[len(v.code)-digits:]
+
+		got, err := Code(rfc6238Secret, time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("at %d got %s, want %s", v.unix, got, want)
+		}
+
+		step, ok := Validate(rfc6238Secret, want, time.Unix(v.unix, 0))
+		if !ok || step != v.unix/period {
+			t.Errorf("at %d Validate returned step %d ok %v, want step %d", v.unix, step, ok, v.unix/period)
+		}
+	}
+}
+
+func TestValidateSkewWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / period
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"current step", 0, true},
+		{"one step behind", -period * time.Second, true},
+		{"one step ahead", period * time.Second, true},
+		{"two steps behind", -2 * period * time.Second, false},
+		{"two steps ahead", 2 * period * time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeTime := now.Add(tt.offset)
+			code, err := Code(rfc6238Secret, codeTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step, ok := Validate(rfc6238Secret, code, now)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+			if ok && step != codeTime.Unix()/period {
+				t.Fatalf("got step %d, want %d (current %d)", step, codeTime.Unix()/period, current)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	code, err := Code(rfc6238Secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+	}{
+		{"too short", rfc6238Secret, code[:5]},
+		{"too long", rfc6238Secret, code + "0"},
+		{"empty", rfc6238Secret, ""},
+		{"invalid secret", "not base32!", code},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Validate(tt.secret, tt.code, now); ok {
+				t.Fatal("code was accepted")
+			}
+		})
+	}
+
+	// Secrets are accepted in lower case, as some apps display them
+	if _, ok := Validate(strings.ToLower(rfc6238Secret), code, now); !ok {
+		t.Fatal("lower case secret was rejected")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatal("secrets repeat")
+	}
+
+	key, err := encoding.DecodeString(first)
+	if err != nil || len(key) != secretSize {
+		t.Fatalf("secret decodes to %d bytes (%v), want %d", len(key), err, secretSize)
+	}
+
+	// A fresh secret round-trips through Code and Validate
+	now := time.Now()
+	code, err := Code(first, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Validate(first, code, now); !ok {
+		t.Fatal("code for a generated secret was rejected")
+	}
+}
+
+func TestURL(t *testing.T) {
+	got := URL("Backend API", "jane@example.com", rfc6238Secret)
+	for _, part := range []string{
+		"otpauth://totp/Backend%20API:jane@example.com?",
+		"secret=" + rfc6238Secret,
+		"issuer=Backend+API",
+		"digits=6",
+		"period=30",
+	} {
+		if !strings.Contains(got, part) {
+			t.Errorf("%s does not contain %s", got, part)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagBackupEligible   = 0x08
+	flagBackedUp         = 0x10
+	flagAttestedCredData = 0x40
+	flagExtensionData    = 0x80
+)
+
+// authenticatorData is the structure the authenticator signs over
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+	// Only present during registration
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errors.New("authenticator data is too short")
+	}
+
+	ad := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if ad.flags&flagAttestedCredData != 0 {
+		if len(rest) < 18 {
+			return nil, errors.New("attested credential data is too short")
+		}
+		ad.aaguid = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+			return nil, errors.New("invalid credential id length")
+		}
+		ad.credentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		// The key is a CBOR item of unknown length; decoding it tells us
+		// where it ends
+		_, after, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, err
+		}
+		ad.publicKey = rest[:len(rest)-len(after)]
+		rest = after
+	}
+
+	if ad.flags&flagExtensionData != 0 {
+		var err error
+		if _, rest, err = decodeCBOR(rest); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after authenticator data")
+	}
+	return ad, nil
+}
+
+func (ad *authenticatorData) has(flag byte) bool {
+	return ad.flags&flag != 0
+}
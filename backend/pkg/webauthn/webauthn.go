@@ -0,0 +1,241 @@
+// Package webauthn is a minimal WebAuthn relying party for passkeys: it
+// verifies registration and authentication ceremonies for ES256, EdDSA
+// and RS256 credentials. Attestation statements are not checked, since
+// we ask authenticators for none; a new key is trusted on first use, like
+// a password set by the same user.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const challengeSize = 32
+
+var (
+	// ErrVerification wraps every reason a ceremony is rejected
+	ErrVerification = errors.New("webauthn verification failed")
+	// ErrCloned reports a signature counter that went backwards, which
+	// means the authenticator may have been copied
+	ErrCloned = errors.New("authenticator signature counter went backwards")
+)
+
+type Config struct {
+	// RPID is the domain credentials are scoped to, e.g. "example.com".
+	// It must be the origin's host or a registrable suffix of it.
+	RPID   string
+	RPName string
+	// Origins are the exact origins ceremonies may come from
+	Origins []string
+}
+
+// RelyingParty verifies ceremonies for one RP ID
+type RelyingParty struct {
+	cfg      Config
+	rpIDHash [32]byte
+}
+
+func New(cfg Config) (*RelyingParty, error) {
+	if cfg.RPID == "" {
+		return nil, errors.New("webauthn: rp id is required")
+	}
+	if len(cfg.Origins) == 0 {
+		return nil, errors.New("webauthn: at least one origin is required")
+	}
+	for _, origin := range cfg.Origins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("webauthn: invalid origin %q", origin)
+		}
+		host := u.Hostname()
+		if host != cfg.RPID && !strings.HasSuffix(host, "."+cfg.RPID) {
+			return nil, fmt.Errorf("webauthn: origin %q is outside rp id %q", origin, cfg.RPID)
+		}
+	}
+	if cfg.RPName == "" {
+		cfg.RPName = cfg.RPID
+	}
+	return &RelyingParty{cfg: cfg, rpIDHash: sha256.Sum256([]byte(cfg.RPID))}, nil
+}
+
+func (rp *RelyingParty) ID() string {
+	return rp.cfg.RPID
+}
+
+func (rp *RelyingParty) Name() string {
+	return rp.cfg.RPName
+}
+
+// Encode and Decode convert binary values to the unpadded base64url used
+// for them in JSON; Decode also accepts padding
+func Encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func Decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// NewChallenge returns a random challenge, base64url encoded
+func NewChallenge() (string, error) {
+	b := make([]byte, challengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Encode(b), nil
+}
+
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// Challenge reads the challenge out of clientDataJSON without verifying
+// anything, so the ceremony it belongs to can be looked up
+func Challenge(clientDataJSON []byte) (string, error) {
+	var cd clientData
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil || cd.Challenge == "" {
+		return "", fmt.Errorf("%w: invalid client data", ErrVerification)
+	}
+	return cd.Challenge, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony, challenge string) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return fmt.Errorf("%w: invalid client data", ErrVerification)
+	}
+	if cd.Type != ceremony {
+		return fmt.Errorf("%w: unexpected client data type %q", ErrVerification, cd.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(cd.Challenge), []byte(challenge)) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrVerification)
+	}
+	if !slices.Contains(rp.cfg.Origins, cd.Origin) {
+		return fmt.Errorf("%w: unexpected origin %q", ErrVerification, cd.Origin)
+	}
+	if cd.CrossOrigin {
+		return fmt.Errorf("%w: cross-origin ceremonies are not allowed", ErrVerification)
+	}
+	return nil
+}
+
+// verifyAuthenticatorData checks the RP ID hash and that the user was
+// present and verified; a passkey replaces the password, so it must
+// carry a PIN or biometric check of its own
+func (rp *RelyingParty) verifyAuthenticatorData(ad *authenticatorData) error {
+	if !bytes.Equal(ad.rpIDHash, rp.rpIDHash[:]) {
+		return fmt.Errorf("%w: rp id mismatch", ErrVerification)
+	}
+	if !ad.has(flagUserPresent) {
+		return fmt.Errorf("%w: user not present", ErrVerification)
+	}
+	if !ad.has(flagUserVerified) {
+		return fmt.Errorf("%w: user not verified", ErrVerification)
+	}
+	return nil
+}
+
+// Credential is a newly registered public key credential
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE encoded key, stored as is
+	PublicKey []byte
+	SignCount uint32
+	AAGUID    []byte
+	// BackupEligible credentials may be synced between devices
+	BackupEligible bool
+}
+
+// VerifyRegistration checks the response to navigator.credentials.create
+// against the challenge it was issued with
+func (rp *RelyingParty) VerifyRegistration(challenge string, clientDataJSON, attestationObject []byte) (*Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	item, rest, err := decodeCBOR(attestationObject)
+	if err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: invalid attestation object", ErrVerification)
+	}
+	att, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid attestation object", ErrVerification)
+	}
+	rawAuthData, ok := att["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing authenticator data", ErrVerification)
+	}
+
+	ad, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	if err := rp.verifyAuthenticatorData(ad); err != nil {
+		return nil, err
+	}
+	if !ad.has(flagAttestedCredData) {
+		return nil, fmt.Errorf("%w: no credential in authenticator data", ErrVerification)
+	}
+	if _, err := parseCOSEKey(ad.publicKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+
+	return &Credential{
+		ID:             ad.credentialID,
+		PublicKey:      ad.publicKey,
+		SignCount:      ad.signCount,
+		AAGUID:         ad.aaguid,
+		BackupEligible: ad.has(flagBackupEligible),
+	}, nil
+}
+
+// Assertion is the response to navigator.credentials.get
+type Assertion struct {
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+}
+
+// VerifyAssertion checks an assertion against the challenge and the
+// stored credential and returns the new signature counter to store
+func (rp *RelyingParty) VerifyAssertion(challenge string, a Assertion, publicKey []byte, signCount uint32) (uint32, error) {
+	if err := rp.verifyClientData(a.ClientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+
+	ad, err := parseAuthenticatorData(a.AuthenticatorData)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	if err := rp.verifyAuthenticatorData(ad); err != nil {
+		return 0, err
+	}
+
+	key, err := parseCOSEKey(publicKey)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	clientDataHash := sha256.Sum256(a.ClientDataJSON)
+	signed := append(append([]byte(nil), a.AuthenticatorData...), clientDataHash[:]...)
+	if !key.verify(signed, a.Signature) {
+		return 0, fmt.Errorf("%w: invalid signature", ErrVerification)
+	}
+
+	// Authenticators that do not count always report zero
+	if (ad.signCount != 0 || signCount != 0) && ad.signCount <= signCount {
+		return 0, ErrCloned
+	}
+	return ad.signCount, nil
+}
@@ -0,0 +1,577 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://app.example.com"
+)
+
+func newTestRP(t *testing.T) *RelyingParty {
+	t.Helper()
+	rp, err := New(Config{RPID: testRPID, Origins: []string{testOrigin}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rp
+}
+
+// cborPair keeps map entries in the order they are written
+type cborPair struct {
+	key   interface{}
+	value interface{}
+}
+
+// encodeCBOR writes the subset of CBOR authenticators produce
+func encodeCBOR(v interface{}) []byte {
+	switch v := v.(type) {
+	case int:
+		return encodeCBOR(int64(v))
+	case int64:
+		if v < 0 {
+			return cborHead(1, uint64(-1-v))
+		}
+		return cborHead(0, uint64(v))
+	case []byte:
+		return append(cborHead(2, uint64(len(v))), v...)
+	case string:
+		return append(cborHead(3, uint64(len(v))), v...)
+	case []interface{}:
+		out := cborHead(4, uint64(len(v)))
+		for _, item := range v {
+			out = append(out, encodeCBOR(item)...)
+		}
+		return out
+	case []cborPair:
+		out := cborHead(5, uint64(len(v)))
+		for _, pair := range v {
+			out = append(out, encodeCBOR(pair.key)...)
+			out = append(out, encodeCBOR(pair.value)...)
+		}
+		return out
+	case bool:
+		if v {
+			return []byte{0xf5}
+		}
+		return []byte{0xf4}
+	case nil:
+		return []byte{0xf6}
+	}
+	panic("encodeCBOR: unsupported type")
+}
+
+func cborHead(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, arg)
+}
+
+// softAuthenticator is a software passkey. Tests change its fields to
+// produce the faulty responses a broken or hostile client would send.
+type softAuthenticator struct {
+	rpID         string
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	alg          int
+	signer       crypto.Signer
+}
+
+func newSoftAuthenticator(t *testing.T, alg int) *softAuthenticator {
+	t.Helper()
+	var signer crypto.Signer
+	var err error
+	switch alg {
+	case AlgES256:
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	case AlgRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := make([]byte, 16)
+	rand.Read(id)
+	return &softAuthenticator{
+		rpID:         testRPID,
+		flags:        flagUserPresent | flagUserVerified,
+		signCount:    1,
+		credentialID: id,
+		alg:          alg,
+		signer:       signer,
+	}
+}
+
+func (a *softAuthenticator) coseKey() []byte {
+	switch pub := a.signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		return encodeCBOR([]cborPair{
+			{coseKty, ktyEC2}, {coseAlg, AlgES256}, {coseCrv, crvP256},
+			{coseX, pub.X.FillBytes(make([]byte, 32))},
+			{coseY, pub.Y.FillBytes(make([]byte, 32))},
+		})
+	case ed25519.PublicKey:
+		return encodeCBOR([]cborPair{
+			{coseKty, ktyOKP}, {coseAlg, AlgEdDSA}, {coseCrv, crvEd25519}, {coseX, []byte(pub)},
+		})
+	case *rsa.PublicKey:
+		return encodeCBOR([]cborPair{
+			{coseKty, ktyRSA}, {coseAlg, AlgRS256},
+			{coseN, pub.N.Bytes()}, {coseE, big.NewInt(int64(pub.E)).Bytes()},
+		})
+	}
+	panic("unsupported key")
+}
+
+func (a *softAuthenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags := a.flags
+	if attested {
+		flags |= flagAttestedCredData
+	}
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // aaguid
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey()...)
+	}
+	return data
+}
+
+func clientDataJSON(ceremony, challenge, origin string) []byte {
+	data, _ := json.Marshal(clientData{Type: ceremony, Challenge: challenge, Origin: origin})
+	return data
+}
+
+// register answers navigator.credentials.create
+func (a *softAuthenticator) register(challenge, origin string) (clientData, attestation []byte) {
+	attestation = encodeCBOR([]cborPair{
+		{"fmt", "none"},
+		{"attStmt", []cborPair{}},
+		{"authData", a.authData(true)},
+	})
+	return clientDataJSON("webauthn.create", challenge, origin), attestation
+}
+
+// assert answers navigator.credentials.get
+func (a *softAuthenticator) assert(t *testing.T, challenge, origin string) Assertion {
+	t.Helper()
+	cd := clientDataJSON("webauthn.get", challenge, origin)
+	ad := a.authData(false)
+	hash := sha256.Sum256(cd)
+	signed := append(append([]byte(nil), ad...), hash[:]...)
+
+	var sig []byte
+	var err error
+	if a.alg == AlgEdDSA {
+		sig, err = a.signer.Sign(rand.Reader, signed, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(signed)
+		sig, err = a.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Assertion{ClientDataJSON: cd, AuthenticatorData: ad, Signature: sig}
+}
+
+func TestCeremonies(t *testing.T) {
+	rp := newTestRP(t)
+
+	for _, alg := range Algorithms {
+		authenticator := newSoftAuthenticator(t, alg)
+		authenticator.flags |= flagBackupEligible
+
+		challenge, err := NewChallenge()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cd, att := authenticator.register(challenge, testOrigin)
+		credential, err := rp.VerifyRegistration(challenge, cd, att)
+		if err != nil {
+			t.Fatalf("alg %d: VerifyRegistration: %v", alg, err)
+		}
+		if string(credential.ID) != string(authenticator.credentialID) || credential.SignCount != 1 || !credential.BackupEligible {
+			t.Fatalf("alg %d: registered %+v", alg, credential)
+		}
+
+		challenge, _ = NewChallenge()
+		authenticator.signCount = 2
+		assertion := authenticator.assert(t, challenge, testOrigin)
+		if got, err := Challenge(assertion.ClientDataJSON); err != nil || got != challenge {
+			t.Fatalf("alg %d: Challenge = %q, %v", alg, got, err)
+		}
+		count, err := rp.VerifyAssertion(challenge, assertion, credential.PublicKey, credential.SignCount)
+		if err != nil {
+			t.Fatalf("alg %d: VerifyAssertion: %v", alg, err)
+		}
+		if count != 2 {
+			t.Fatalf("alg %d: sign count %d, want 2", alg, count)
+		}
+	}
+}
+
+func TestRegistrationRejects(t *testing.T) {
+	rp := newTestRP(t)
+	const challenge = "registration-challenge"
+
+	tests := []struct {
+		name   string
+		change func(a *softAuthenticator) (clientData, attestation []byte)
+	}{
+		{"wrong origin", func(a *softAuthenticator) ([]byte, []byte) {
+			return a.register(challenge, "https://evil.example.net")
+		}},
+		{"origin on another subdomain", func(a *softAuthenticator) ([]byte, []byte) {
+			return a.register(challenge, "https://other.example.com")
+		}},
+		{"wrong challenge", func(a *softAuthenticator) ([]byte, []byte) {
+			return a.register("another-challenge", testOrigin)
+		}},
+		{"assertion client data", func(a *softAuthenticator) ([]byte, []byte) {
+			_, att := a.register(challenge, testOrigin)
+			return clientDataJSON("webauthn.get", challenge, testOrigin), att
+		}},
+		{"cross origin", func(a *softAuthenticator) ([]byte, []byte) {
+			_, att := a.register(challenge, testOrigin)
+			cd, _ := json.Marshal(clientData{Type: "webauthn.create", Challenge: challenge, Origin: testOrigin, CrossOrigin: true})
+			return cd, att
+		}},
+		{"wrong rp id hash", func(a *softAuthenticator) ([]byte, []byte) {
+			a.rpID = "evil.example.net"
+			return a.register(challenge, testOrigin)
+		}},
+		{"user not present", func(a *softAuthenticator) ([]byte, []byte) {
+			a.flags = flagUserVerified
+			return a.register(challenge, testOrigin)
+		}},
+		{"user not verified", func(a *softAuthenticator) ([]byte, []byte) {
+			a.flags = flagUserPresent
+			return a.register(challenge, testOrigin)
+		}},
+		{"no attested credential", func(a *softAuthenticator) ([]byte, []byte) {
+			cd, _ := a.register(challenge, testOrigin)
+			return cd, encodeCBOR([]cborPair{{"fmt", "none"}, {"authData", a.authData(false)}})
+		}},
+		{"missing authData", func(a *softAuthenticator) ([]byte, []byte) {
+			cd, _ := a.register(challenge, testOrigin)
+			return cd, encodeCBOR([]cborPair{{"fmt", "none"}})
+		}},
+		{"attestation is not a map", func(a *softAuthenticator) ([]byte, []byte) {
+			cd, _ := a.register(challenge, testOrigin)
+			return cd, encodeCBOR([]interface{}{"authData", a.authData(true)})
+		}},
+		{"trailing data", func(a *softAuthenticator) ([]byte, []byte) {
+			cd, att := a.register(challenge, testOrigin)
+			return cd, append(att, 0x00)
+		}},
+		{"invalid client data", func(a *softAuthenticator) ([]byte, []byte) {
+			_, att := a.register(challenge, testOrigin)
+			return []byte("{"), att
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cd, att := tt.change(newSoftAuthenticator(t, AlgES256))
+			if _, err := rp.VerifyRegistration(challenge, cd, att); !errors.Is(err, ErrVerification) {
+				t.Fatalf("err = %v, want ErrVerification", err)
+			}
+		})
+	}
+}
+
+func TestRegistrationRejectsUnsupportedKeys(t *testing.T) {
+	rp := newTestRP(t)
+	const challenge = "registration-challenge"
+	authenticator := newSoftAuthenticator(t, AlgES256)
+	pub := authenticator.signer.Public().(*ecdsa.PublicKey)
+	x := pub.X.FillBytes(make([]byte, 32))
+	y := pub.Y.FillBytes(make([]byte, 32))
+	offCurve := append([]byte(nil), y...)
+	offCurve[31] ^= 1
+	smallRSA, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	keys := map[string][]byte{
+		"es384":        encodeCBOR([]cborPair{{coseKty, ktyEC2}, {coseAlg, -35}, {coseCrv, 2}, {coseX, x}, {coseY, y}}),
+		"p-384 curve":  encodeCBOR([]cborPair{{coseKty, ktyEC2}, {coseAlg, AlgES256}, {coseCrv, 2}, {coseX, x}, {coseY, y}}),
+		"off curve":    encodeCBOR([]cborPair{{coseKty, ktyEC2}, {coseAlg, AlgES256}, {coseCrv, crvP256}, {coseX, x}, {coseY, offCurve}}),
+		"short x":      encodeCBOR([]cborPair{{coseKty, ktyEC2}, {coseAlg, AlgES256}, {coseCrv, crvP256}, {coseX, x[1:]}, {coseY, y}}),
+		"string x":     encodeCBOR([]cborPair{{coseKty, ktyEC2}, {coseAlg, AlgES256}, {coseCrv, crvP256}, {coseX, string(x)}, {coseY, y}}),
+		"kty mismatch": encodeCBOR([]cborPair{{coseKty, ktyOKP}, {coseAlg, AlgES256}, {coseCrv, crvP256}, {coseX, x}, {coseY, y}}),
+		"rsa 1024": encodeCBOR([]cborPair{{coseKty, ktyRSA}, {coseAlg, AlgRS256},
+			{coseN, smallRSA.N.Bytes()}, {coseE, big.NewInt(int64(smallRSA.E)).Bytes()}}),
+		"not a map": encodeCBOR([]interface{}{int64(ktyEC2)}),
+	}
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			authData := authenticator.authData(false)
+			authData[32] |= flagAttestedCredData
+			authData = append(authData, make([]byte, 16)...)
+			authData = binary.BigEndian.AppendUint16(authData, uint16(len(authenticator.credentialID)))
+			authData = append(authData, authenticator.credentialID...)
+			authData = append(authData, key...)
+
+			cd := clientDataJSON("webauthn.create", challenge, testOrigin)
+			att := encodeCBOR([]cborPair{{"fmt", "none"}, {"attStmt", []cborPair{}}, {"authData", authData}})
+			if _, err := rp.VerifyRegistration(challenge, cd, att); !errors.Is(err, ErrVerification) {
+				t.Fatalf("err = %v, want ErrVerification", err)
+			}
+		})
+	}
+}
+
+func TestAssertionRejects(t *testing.T) {
+	rp := newTestRP(t)
+	const challenge = "assertion-challenge"
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T, a *softAuthenticator) Assertion
+	}{
+		{"wrong origin", func(t *testing.T, a *softAuthenticator) Assertion {
+			return a.assert(t, challenge, "https://evil.example.net")
+		}},
+		{"wrong challenge", func(t *testing.T, a *softAuthenticator) Assertion {
+			return a.assert(t, "another-challenge", testOrigin)
+		}},
+		{"wrong rp id hash", func(t *testing.T, a *softAuthenticator) Assertion {
+			a.rpID = "evil.example.net"
+			return a.assert(t, challenge, testOrigin)
+		}},
+		{"user not present", func(t *testing.T, a *softAuthenticator) Assertion {
+			a.flags = flagUserVerified
+			return a.assert(t, challenge, testOrigin)
+		}},
+		{"user not verified", func(t *testing.T, a *softAuthenticator) Assertion {
+			a.flags = flagUserPresent
+			return a.assert(t, challenge, testOrigin)
+		}},
+		{"registration client data", func(t *testing.T, a *softAuthenticator) Assertion {
+			assertion := a.assert(t, challenge, testOrigin)
+			assertion.ClientDataJSON = clientDataJSON("webauthn.create", challenge, testOrigin)
+			return assertion
+		}},
+		{"client data swapped after signing", func(t *testing.T, a *softAuthenticator) Assertion {
+			assertion := a.assert(t, "another-challenge", testOrigin)
+			assertion.ClientDataJSON = clientDataJSON("webauthn.get", challenge, testOrigin)
+			return assertion
+		}},
+		{"authenticator data changed after signing", func(t *testing.T, a *softAuthenticator) Assertion {
+			assertion := a.assert(t, challenge, testOrigin)
+			assertion.AuthenticatorData[36]++
+			return assertion
+		}},
+		{"signed by another key", func(t *testing.T, a *softAuthenticator) Assertion {
+			a.signer = newSoftAuthenticator(t, AlgES256).signer
+			return a.assert(t, challenge, testOrigin)
+		}},
+		{"garbage signature", func(t *testing.T, a *softAuthenticator) Assertion {
+			assertion := a.assert(t, challenge, testOrigin)
+			assertion.Signature = []byte{0x30, 0x00}
+			return assertion
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := newSoftAuthenticator(t, AlgES256)
+			publicKey := authenticator.coseKey()
+			authenticator.signCount = 5
+			assertion := tt.change(t, authenticator)
+			if _, err := rp.VerifyAssertion(challenge, assertion, publicKey, 4); !errors.Is(err, ErrVerification) {
+				t.Fatalf("err = %v, want ErrVerification", err)
+			}
+		})
+	}
+}
+
+func TestAssertionSignCount(t *testing.T) {
+	rp := newTestRP(t)
+	const challenge = "assertion-challenge"
+
+	tests := []struct {
+		name    string
+		stored  uint32
+		sent    uint32
+		wantErr error
+	}{
+		{"increases", 4, 5, nil},
+		{"jumps ahead", 4, 1000, nil},
+		{"authenticator without a counter", 0, 0, nil},
+		{"first use of a counter", 0, 1, nil},
+		{"repeats", 5, 5, ErrCloned},
+		{"goes backwards", 5, 4, ErrCloned},
+		{"drops to zero", 5, 0, ErrCloned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := newSoftAuthenticator(t, AlgEdDSA)
+			authenticator.signCount = tt.sent
+			count, err := rp.VerifyAssertion(challenge, authenticator.assert(t, challenge, testOrigin), authenticator.coseKey(), tt.stored)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && count != tt.sent {
+				t.Fatalf("count = %d, want %d", count, tt.sent)
+			}
+		})
+	}
+}
+
+// Every prefix and every single-byte change of a valid response must be
+// rejected or accepted without panicking
+func TestMalformedResponsesDoNotPanic(t *testing.T) {
+	rp := newTestRP(t)
+	const challenge = "challenge"
+	authenticator := newSoftAuthenticator(t, AlgES256)
+	cd, att := authenticator.register(challenge, testOrigin)
+	assertion := authenticator.assert(t, challenge, testOrigin)
+	publicKey := authenticator.coseKey()
+
+	variants := func(data []byte, try func([]byte)) {
+		for i := 0; i < len(data); i++ {
+			try(data[:i])
+			for _, b := range []byte{0x00, 0xff, data[i] ^ 0x1f, data[i] ^ 0xe0} {
+				changed := append([]byte(nil), data...)
+				changed[i] = b
+				try(changed)
+			}
+		}
+	}
+
+	variants(att, func(b []byte) { rp.VerifyRegistration(challenge, cd, b) })
+	variants(assertion.AuthenticatorData, func(b []byte) {
+		rp.VerifyAssertion(challenge, Assertion{ClientDataJSON: assertion.ClientDataJSON, AuthenticatorData: b, Signature: assertion.Signature}, publicKey, 0)
+	})
+	variants(publicKey, func(b []byte) { rp.VerifyAssertion(challenge, assertion, b, 0) })
+	variants(cd, func(b []byte) {
+		Challenge(b)
+		rp.VerifyRegistration(challenge, b, att)
+	})
+
+	// Truncations must never verify
+	for i := 0; i < len(att); i++ {
+		if _, err := rp.VerifyRegistration(challenge, cd, att[:i]); err == nil {
+			t.Fatalf("attestation truncated to %d bytes verified", i)
+		}
+	}
+	for i := 0; i < len(assertion.AuthenticatorData); i++ {
+		truncated := Assertion{ClientDataJSON: assertion.ClientDataJSON, AuthenticatorData: assertion.AuthenticatorData[:i], Signature: assertion.Signature}
+		if _, err := rp.VerifyAssertion(challenge, truncated, publicKey, 0); err == nil {
+			t.Fatalf("authenticator data truncated to %d bytes verified", i)
+		}
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	nested := make([]byte, 0, maxDepth+2)
+	for i := 0; i < maxDepth+2; i++ {
+		nested = append(nested, 0x81) // array of one item
+	}
+	nested = append(nested, 0x00)
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  interface{}
+	}{
+		{"small int", []byte{0x17}, int64(23)},
+		{"uint8", []byte{0x18, 0xff}, int64(255)},
+		{"uint16", []byte{0x19, 0x01, 0x00}, int64(256)},
+		{"uint32", []byte{0x1a, 0x00, 0x01, 0x00, 0x00}, int64(65536)},
+		{"negative", []byte{0x38, 0x63}, int64(-100)},
+		{"cose alg", encodeCBOR(AlgRS256), int64(AlgRS256)},
+		{"bytes", []byte{0x42, 0x01, 0x02}, []byte{0x01, 0x02}},
+		{"text", []byte{0x63, 'f', 'm', 't'}, "fmt"},
+		{"true", []byte{0xf5}, true},
+		{"null", []byte{0xf6}, nil},
+		{"tagged", []byte{0xc1, 0x01}, int64(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rest, err := decodeCBOR(tt.input)
+			if err != nil || len(rest) != 0 {
+				t.Fatalf("decodeCBOR(%x) = %v, rest %x, err %v", tt.input, got, rest, err)
+			}
+			if b, ok := tt.want.([]byte); ok {
+				if string(got.([]byte)) != string(b) {
+					t.Fatalf("got %x, want %x", got, b)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Fatalf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	malformed := map[string][]byte{
+		"empty":                  {},
+		"truncated uint16":       {0x19, 0x01},
+		"truncated uint64":       {0x1b, 0x00, 0x00},
+		"uint64 overflow":        {0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"negative overflow":      {0x3b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"reserved info":          {0x1c},
+		"indefinite bytes":       {0x5f, 0x41, 0x00, 0xff},
+		"indefinite map":         {0xbf, 0x01, 0x02, 0xff},
+		"string past the end":    {0x45, 0x01, 0x02},
+		"huge string length":     {0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"huge array length":      {0x9b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"huge map length":        {0xbb, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"array missing items":    {0x83, 0x01, 0x02},
+		"map missing value":      {0xa1, 0x01},
+		"byte string map key":    {0xa1, 0x41, 0x00, 0x01},
+		"array map key":          {0xa1, 0x80, 0x01},
+		"duplicate map key":      {0xa2, 0x01, 0x00, 0x01, 0x00},
+		"float":                  {0xfa, 0x3f, 0x80, 0x00, 0x00},
+		"undefined simple value": {0xf0},
+		"tag without item":       {0xc1},
+		"nested too deeply":      nested,
+	}
+	for name, input := range malformed {
+		t.Run(name, func(t *testing.T) {
+			if got, _, err := decodeCBOR(input); !errors.Is(err, errCBOR) {
+				t.Fatalf("decodeCBOR(%x) = %v, %v; want errCBOR", input, got, err)
+			}
+		})
+	}
+}
+
+func FuzzParseAuthenticatorData(f *testing.F) {
+	authenticator := &softAuthenticator{rpID: testRPID, flags: flagUserPresent | flagUserVerified, credentialID: []byte("credential"), alg: AlgEdDSA}
+	_, authenticator.signer, _ = ed25519.GenerateKey(rand.Reader)
+	f.Add(authenticator.authData(true))
+	f.Add(authenticator.authData(false))
+	f.Add(append(authenticator.authData(false)[:32], flagExtensionData, 0, 0, 0, 0, 0xa0))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if ad, err := parseAuthenticatorData(data); err == nil && ad.has(flagAttestedCredData) {
+			parseCOSEKey(ad.publicKey)
+		}
+	})
+}